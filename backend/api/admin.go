@@ -0,0 +1,217 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// adminToken is the bearer token required by the admin API, loaded from ADMIN_TOKEN
+var adminToken string
+
+// AdminAuth rejects requests that don't carry the admin bearer token.
+// The admin API is disabled entirely when ADMIN_TOKEN is not set.
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if adminToken == "" {
+			abortWithError(c, http.StatusForbidden, CodeAdminDisabled, "Admin API is disabled", nil, nil)
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			abortWithError(c, http.StatusUnauthorized, CodeUnauthorized, "Invalid admin token", nil, nil)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// adminActor is the audit log actor of an admin API request. The token is
+// shared, so admins name themselves with the X-Admin-User header.
+func adminActor(c *gin.Context) string {
+	return models.AdminActor(strings.TrimSpace(c.GetHeader("X-Admin-User")))
+}
+
+// destinationRequest is the body accepted when creating or updating a destination
+type destinationRequest struct {
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Clues     []string `json:"clues"`
+	FunFact   []string `json:"fun_fact"`
+	Trivia    []string `json:"trivia"`
+	Retired   bool     `json:"retired"`
+}
+
+// toDestination converts the request body to a destination model
+func (r destinationRequest) toDestination() models.Destination {
+	return models.Destination{
+		City:      r.City,
+		Country:   r.Country,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Clues:     r.Clues,
+		FunFact:   r.FunFact,
+		Trivia:    r.Trivia,
+		Retired:   r.Retired,
+	}
+}
+
+// AdminListDestinations handles requests to list all destinations
+func AdminListDestinations(c *gin.Context) {
+	includeRetired := c.Query("include_retired") == "true"
+
+	destinations, err := dataService.ListDestinations(includeRetired)
+	if err != nil {
+		respondError(c, nil, err, "Failed to list destinations")
+		return
+	}
+
+	c.JSON(http.StatusOK, destinations)
+}
+
+// AdminGetDestination handles requests to get a single destination
+func AdminGetDestination(c *gin.Context) {
+	destinationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid destination ID")
+		return
+	}
+
+	destination, err := dataService.GetDestination(destinationID)
+	if err != nil {
+		respondError(c, nil, err, "Failed to get destination")
+		return
+	}
+
+	c.JSON(http.StatusOK, destination)
+}
+
+// AdminCreateDestination handles requests to create a destination
+func AdminCreateDestination(c *gin.Context) {
+	var request destinationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, nil, "Invalid request")
+		return
+	}
+
+	destination, err := dataService.CreateDestination(adminActor(c), request.toDestination())
+	if err != nil {
+		respondError(c, nil, err, "Failed to create destination")
+		return
+	}
+
+	c.JSON(http.StatusCreated, destination)
+}
+
+// AdminUpdateDestination handles requests to replace a destination's content
+func AdminUpdateDestination(c *gin.Context) {
+	destinationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid destination ID")
+		return
+	}
+
+	var request destinationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, nil, "Invalid request")
+		return
+	}
+
+	dest := request.toDestination()
+	dest.ID = destinationID
+
+	destination, err := dataService.UpdateDestination(adminActor(c), dest)
+	if err != nil {
+		respondError(c, nil, err, "Failed to update destination")
+		return
+	}
+
+	c.JSON(http.StatusOK, destination)
+}
+
+// AdminDeleteDestination handles requests to delete a destination
+func AdminDeleteDestination(c *gin.Context) {
+	destinationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid destination ID")
+		return
+	}
+
+	retired, err := dataService.DeleteDestination(adminActor(c), destinationID)
+	if err != nil {
+		respondError(c, nil, err, "Failed to delete destination")
+		return
+	}
+
+	// Destinations used by existing games are retired rather than removed
+	c.JSON(http.StatusOK, gin.H{"id": destinationID, "retired": retired, "deleted": !retired})
+}
+
+// AdminListClues handles requests to list a destination's clues with their IDs
+func AdminListClues(c *gin.Context) {
+	destinationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid destination ID")
+		return
+	}
+
+	clues, err := dataService.ListClues(destinationID, c.Query("include_retired") == "true")
+	if err != nil {
+		respondError(c, nil, err, "Failed to list clues")
+		return
+	}
+
+	c.JSON(http.StatusOK, clues)
+}
+
+// AdminUpdateClue handles requests to edit, tag or retire a single clue.
+// Fields left out of the request body are unchanged.
+func AdminUpdateClue(c *gin.Context) {
+	clueID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid clue ID")
+		return
+	}
+
+	var request struct {
+		Text    *string   `json:"text"`
+		Tags    *[]string `json:"tags"`
+		Retired *bool     `json:"retired"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, nil, "Invalid request")
+		return
+	}
+
+	clue, err := dataService.GetClue(clueID)
+	if err != nil {
+		respondError(c, nil, err, "Failed to get clue")
+		return
+	}
+
+	if request.Text != nil {
+		clue.Text = *request.Text
+	}
+	if request.Tags != nil {
+		clue.Tags = *request.Tags
+	}
+	if request.Retired != nil {
+		clue.Retired = *request.Retired
+	}
+
+	updated, err := dataService.UpdateClue(adminActor(c), *clue)
+	if err != nil {
+		respondError(c, nil, err, "Failed to update clue")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/services"
+)
+
+// AdminDifficultyReport handles requests for the destinations and clues whose
+// answers look off. Pass ?min_samples= to change how many answers an item
+// needs before it is flagged.
+func AdminDifficultyReport(c *gin.Context) {
+	minSamples := services.DefaultMinSamples
+	if value := c.Query("min_samples"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			respondInvalid(c, nil, "min_samples must be a positive number")
+			return
+		}
+		minSamples = parsed
+	}
+
+	report, err := dataService.DifficultyReport(minSamples)
+	if err != nil {
+		respondError(c, nil, err, "Failed to build difficulty report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// AdminDestinationDifficulty handles requests for the statistics of every answered destination
+func AdminDestinationDifficulty(c *gin.Context) {
+	stats, err := dataService.ListDestinationDifficulty()
+	if err != nil {
+		respondError(c, nil, err, "Failed to list destination difficulty")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// AdminClueDifficulty handles requests for the statistics of every answered clue
+func AdminClueDifficulty(c *gin.Context) {
+	stats, err := dataService.ListClueDifficulty()
+	if err != nil {
+		respondError(c, nil, err, "Failed to list clue difficulty")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// AdminRefreshDifficulty handles requests to aggregate the statistics now
+// instead of waiting for the background job
+func AdminRefreshDifficulty(c *gin.Context) {
+	if err := dataService.RefreshDifficulty(); err != nil {
+		respondError(c, nil, err, "Failed to refresh difficulty statistics")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
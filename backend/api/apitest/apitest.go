@@ -0,0 +1,109 @@
+// Package apitest serves the API from a database of each test's own, so the
+// tests of the API and of its clients don't depend on each other. A
+// package's TestMain calls Main, and each test calls Open before sending
+// requests to api.SetupRoutes.
+package apitest
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/api"
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/ratelimit"
+)
+
+// AdminToken is the admin API token the API accepts in tests
+const AdminToken = "apitest"
+
+// GamesPerHour is how many games a player may start in an hour in tests,
+// low enough for a test to reach the limit
+const GamesPerHour = 3
+
+// template is a database seeded once by Main, copied for each test
+var template string
+
+// Main configures the API for tests, seeds a template database from the
+// dataset at datasetPath, relative to the package directory, and runs the
+// package's tests. It returns the exit code for os.Exit. The server log is
+// only shown with -v.
+func Main(m *testing.M, datasetPath string) int {
+	flag.Parse()
+	if !testing.Verbose() {
+		log.SetOutput(io.Discard)
+	}
+
+	os.Setenv("DATASET_PATH", datasetPath)
+	os.Setenv("ADMIN_TOKEN", AdminToken)
+	os.Setenv("OPENAPI_VALIDATION", "false")
+	os.Setenv("RATE_LIMIT_GAMES_USER", strconv.Itoa(GamesPerHour)+"/h")
+
+	dir, err := os.MkdirTemp("", "apitest")
+	if err != nil {
+		log.SetOutput(os.Stderr)
+		log.Print(err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	template = filepath.Join(dir, "globetrotter.db")
+	database, err := db.Open(template)
+	if err != nil {
+		log.SetOutput(os.Stderr)
+		log.Printf("seeding the template database: %v", err)
+		return 1
+	}
+	database.Close()
+
+	return m.Run()
+}
+
+// Open serves the API from a copy of the template database for the rest of
+// the test, with empty rate limit buckets, and returns the database.
+// Validation against the OpenAPI document is off until the test sets a
+// validator with api.SetSpecValidator.
+func Open(t testing.TB) *db.Database {
+	t.Helper()
+	if template == "" {
+		t.Fatal("apitest.Open called without apitest.Main")
+	}
+
+	path := filepath.Join(t.TempDir(), "globetrotter.db")
+	if err := copyFile(template, path); err != nil {
+		t.Fatal(err)
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	api.InitServices(database)
+	api.SetRateLimitStore(ratelimit.NewMemoryStore())
+	t.Cleanup(func() { api.SetSpecValidator(nil) })
+	return database
+}
+
+// copyFile copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// AdminListAuditEntries handles requests to read the audit log, newest first.
+// It can be filtered by ?actor=, ?action=, ?target_type=, ?target_id= and the
+// RFC 3339 times ?since= and ?until=. Pass ?limit= to change the page size and
+// ?before= with the last ID of a page to get the next one.
+func AdminListAuditEntries(c *gin.Context) {
+	filter := storage.AuditFilter{
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{
+		{"target_id", &filter.TargetID},
+		{"before", &filter.BeforeID},
+		{"limit", &filter.Limit},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			respondInvalid(c, nil, param.name+" must be a positive number")
+			return
+		}
+		*param.target = parsed
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondInvalid(c, nil, param.name+" must be an RFC 3339 time")
+			return
+		}
+		*param.target = parsed
+	}
+
+	entries, err := dataService.ListAuditEntries(filter)
+	if err != nil {
+		respondError(c, nil, err, "Failed to list audit entries")
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
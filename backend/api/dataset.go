@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/dataset"
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// ReloadDataset loads the dataset at DATASET_PATH into the running server
+// without removing destinations it no longer has. main calls it on SIGHUP.
+func ReloadDataset() (*models.DatasetReload, error) {
+	return dataService.ReloadDataset(models.ActorSystem, dataset.Path(), false)
+}
+
+// AdminReloadDataset handles requests to load the dataset at DATASET_PATH
+// into the running server. Pass ?prune=true to also remove destinations the
+// dataset no longer has. An invalid dataset is rejected and the current
+// destinations stay in place.
+func AdminReloadDataset(c *gin.Context) {
+	report, err := dataService.ReloadDataset(adminActor(c), dataset.Path(), c.Query("prune") == "true")
+	if err != nil {
+		respondError(c, nil, err, "Failed to reload dataset")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
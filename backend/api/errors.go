@@ -0,0 +1,147 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/services"
+)
+
+// Error codes of failures detected by the API itself. Failures reported by
+// the services carry their own codes, such as "game_not_found".
+const (
+	CodeInvalidRequest  = "invalid_request"
+	CodeUnauthorized    = "unauthorized"
+	CodeAdminDisabled   = "admin_disabled"
+	CodeInvalidDataset  = "invalid_dataset"
+	CodeAlreadyAnswered = "already_answered"
+	CodeInternal        = "internal_error"
+)
+
+// requestIDHeader carries the request ID in both directions: a caller may
+// send one, and every response includes the one used
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey is where RequestID stores the ID in the gin context
+const requestIDKey = "request_id"
+
+// RequestID gives every request an ID, the caller's X-Request-ID when it
+// sends a usable one, and returns it in the X-Request-ID response header.
+// Error responses and the log lines of internal errors include it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID accepts short IDs of printable ASCII, so they can be logged as is
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 16 byte ID in hex
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
+
+// requestID returns the ID of the request
+func requestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// errorBody is the envelope of every error response, under "error"
+type errorBody struct {
+	Code      string   `json:"code"`
+	Message   string   `json:"message"`
+	RequestID string   `json:"request_id"`
+	Problems  []string `json:"problems,omitempty"`
+}
+
+// abortWithError ends the request with an error response. extra adds fields
+// next to "error", such as the recorded answer of a repeated submission.
+func abortWithError(c *gin.Context, status int, code, message string, problems []string, extra gin.H) {
+	body := gin.H{"error": errorBody{
+		Code:      code,
+		Message:   message,
+		RequestID: requestID(c),
+		Problems:  problems,
+	}}
+	for key, value := range extra {
+		body[key] = value
+	}
+	c.AbortWithStatusJSON(status, body)
+}
+
+// respondInvalid ends the request with a 400 for input the handler couldn't
+// parse, translating message along locales
+func respondInvalid(c *gin.Context, locales []string, message string) {
+	abortWithError(c, http.StatusBadRequest, CodeInvalidRequest, i18n.T(locales, message), nil, nil)
+}
+
+// respondError ends the request with the error response for err. Domain
+// errors from the services get the status of their kind and their own code
+// and message, translated along locales. Anything else is logged with the
+// request ID and reported as an internal error with failure as its message,
+// so database and other internal details don't reach clients.
+func respondError(c *gin.Context, locales []string, err error, failure string) {
+	var (
+		validation *services.ValidationError
+		answered   *services.AlreadyAnsweredError
+		dataset    *services.InvalidDatasetError
+		domain     *services.Error
+	)
+
+	switch {
+	case errors.As(err, &validation):
+		abortWithError(c, http.StatusBadRequest, validation.Code(), i18n.T(locales, validation.Message()), validation.Problems, nil)
+	case errors.As(err, &answered):
+		// A retried or doubled request gets the outcome of the answer that was recorded
+		abortWithError(c, http.StatusConflict, CodeAlreadyAnswered, i18n.T(locales, "Question already answered"), nil, gin.H{"answer": answered.Answer})
+	case errors.As(err, &dataset):
+		abortWithError(c, http.StatusUnprocessableEntity, CodeInvalidDataset, "Invalid dataset", dataset.Problems, nil)
+	case errors.As(err, &domain):
+		abortWithError(c, kindStatus(domain.Kind), domain.Code, i18n.T(locales, domain.Message), nil, nil)
+	default:
+		log.Printf("[%s] %s: %v", requestID(c), failure, err)
+		abortWithError(c, http.StatusInternalServerError, CodeInternal, i18n.T(locales, failure), nil, nil)
+	}
+}
+
+// kindStatus maps a kind of domain error to its HTTP status
+func kindStatus(kind error) int {
+	switch kind {
+	case services.ErrNotFound:
+		return http.StatusNotFound
+	case services.ErrConflict:
+		return http.StatusConflict
+	case services.ErrValidation:
+		return http.StatusBadRequest
+	case services.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
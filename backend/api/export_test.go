@@ -0,0 +1,10 @@
+package api
+
+// Unexported parts of the rate limits, for the tests in package api_test
+var (
+	RatePlayer = ratePlayer
+	GameOwner  = gameOwner
+)
+
+// MaxRateBody is the most of a body RatePlayer reads
+const MaxRateBody = maxRateBody
@@ -12,10 +12,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/shubhsherl/globetrotter/backend/db"
 	"github.com/shubhsherl/globetrotter/backend/models"
 	"github.com/shubhsherl/globetrotter/backend/services"
 	"github.com/shubhsherl/globetrotter/backend/services/images"
+	"github.com/shubhsherl/globetrotter/backend/storage"
 )
 
 // Define service objects at the package level
@@ -25,32 +25,75 @@ var (
 )
 
 // InitServices initializes the service objects
-func InitServices(database *db.Database) {
-	dataService = services.NewDataService(database)
+func InitServices(store storage.Store) {
+	dataService = services.NewDataService(store)
+	adminToken = os.Getenv("ADMIN_TOKEN")
 	startTime = time.Now()
+	initSpec()
+	initRateLimits()
 	log.Println("API services initialized successfully")
 }
 
+// Services returns the data service behind the handlers, so the gRPC API can
+// serve the same data
+func Services() *services.DataService {
+	return dataService
+}
+
 // SetupRoutes configures the API routes
 func SetupRoutes(r *gin.Engine) {
+	// Every response carries a request ID that error responses and logs refer to
+	r.Use(RequestID())
+
 	// Health check endpoint - register at multiple paths for redundancy
-	r.GET("/health", HealthCheck)
+	r.GET("/health", validateSpec, HealthCheck)
 
 	log.Println("Health check endpoints registered at /health and /")
 
 	// API routes
-	api := r.Group("/api")
+	api := r.Group("/api", validateSpec)
 	{
+		api.GET("/openapi.json", GetOpenAPI)
 		api.GET("/destinations/random", GetRandomDestination)
-		api.POST("/users", CreateUser)
 		api.GET("/users/:username", GetUser)
+		api.GET("/users/:username/submissions", GetUserSubmissions)
+
+		// Routes that write to the database are rate limited per client IP and per player
+		accounts := api.Group("", limitRate(&accountLimits))
+		{
+			accounts.POST("/users", CreateUser)
+			accounts.PATCH("/users/:username", UpdateUser)
+			accounts.POST("/submissions", CreateSubmission)
+		}
 
 		// Game routes
-		api.POST("/game/play", StartGame)
+		api.POST("/game/play", limitRate(&gameLimits), StartGame)
 		api.GET("/game/:id/next-question", GetNextQuestion)
-		api.POST("/game/:id/submit-answer", SubmitAnswer)
+		api.POST("/game/:id/submit-answer", limitRate(&answerLimits), SubmitAnswer)
 		api.GET("/game/:id/result", GetGameResult)
 		api.GET("/game/:id/summary", GetGameSummary)
+
+		// Admin routes, checked against the specification once the caller is authenticated
+		admin := r.Group("/api/admin", AdminAuth(), validateSpec)
+		{
+			admin.GET("/destinations", AdminListDestinations)
+			admin.POST("/destinations", AdminCreateDestination)
+			admin.GET("/destinations/:id", AdminGetDestination)
+			admin.PUT("/destinations/:id", AdminUpdateDestination)
+			admin.DELETE("/destinations/:id", AdminDeleteDestination)
+			admin.GET("/destinations/:id/clues", AdminListClues)
+			admin.PATCH("/clues/:id", AdminUpdateClue)
+			admin.GET("/submissions", AdminListSubmissions)
+			admin.GET("/submissions/:id", AdminGetSubmission)
+			admin.POST("/submissions/:id/approve", AdminApproveSubmission)
+			admin.POST("/submissions/:id/reject", AdminRejectSubmission)
+			admin.GET("/analytics/difficulty", AdminDifficultyReport)
+			admin.GET("/analytics/destinations", AdminDestinationDifficulty)
+			admin.GET("/analytics/clues", AdminClueDifficulty)
+			admin.POST("/analytics/refresh", AdminRefreshDifficulty)
+			admin.POST("/dataset/reload", AdminReloadDataset)
+			admin.GET("/audit", AdminListAuditEntries)
+		}
 	}
 
 	log.Println("All API routes registered successfully")
@@ -69,12 +112,14 @@ func HealthCheck(c *gin.Context) {
 	})
 }
 
-// GetRandomDestination handles requests for a random destination
+// GetRandomDestination handles requests for a random destination, optionally
+// filtered by the country or region query parameter
 func GetRandomDestination(c *gin.Context) {
-	destination, err := dataService.GetRandomDestination()
+	locales := requestLocales(c)
+
+	destination, err := dataService.GetRandomDestination(c.Query("country"), c.Query("region"))
 	if err != nil {
-		fmt.Println("Error getting random destination:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get random destination"})
+		respondError(c, locales, err, "Failed to get random destination")
 		return
 	}
 
@@ -84,22 +129,21 @@ func GetRandomDestination(c *gin.Context) {
 
 // CreateUser handles requests to create a new user
 func CreateUser(c *gin.Context) {
+	locales := requestLocales(c)
+
 	var request struct {
 		Username string `json:"username" binding:"required"`
+		Locale   string `json:"locale"` // Optional preferred language, e.g. "es" or "pt-BR"
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondInvalid(c, locales, "Invalid request")
 		return
 	}
 
-	user, err := dataService.CreateUser(request.Username)
+	user, err := dataService.CreateUser(request.Username, request.Locale)
 	if err != nil {
-		if err.Error() == "username already exists" {
-			c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		respondError(c, locales, err, "Failed to create user")
 		return
 	}
 
@@ -108,11 +152,36 @@ func CreateUser(c *gin.Context) {
 
 // GetUser handles requests to get a user by username
 func GetUser(c *gin.Context) {
+	locales := requestLocales(c)
+
 	username := c.Param("username")
 
 	user, err := dataService.GetUser(username)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, locales, err, "Failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateUser handles requests to change a user's preferred language.
+// Send an empty locale to go back to following Accept-Language.
+func UpdateUser(c *gin.Context) {
+	locales := requestLocales(c)
+
+	var request struct {
+		Locale *string `json:"locale"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil || request.Locale == nil {
+		respondInvalid(c, locales, "Invalid request")
+		return
+	}
+
+	user, err := dataService.SetUserLocale(c.Param("username"), *request.Locale)
+	if err != nil {
+		respondError(c, locales, err, "Failed to update user")
 		return
 	}
 
@@ -121,18 +190,21 @@ func GetUser(c *gin.Context) {
 
 // StartGame handles requests to start a new game
 func StartGame(c *gin.Context) {
+	locales := requestLocales(c)
+
 	var request struct {
 		Username string `json:"username" binding:"required"`
+		Scoring  string `json:"scoring"` // "standard" (default), "proximity" or "pin"
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondInvalid(c, locales, "Invalid request")
 		return
 	}
 
-	gameID, err := dataService.CreateGame(request.Username)
+	gameID, err := dataService.CreateGame(request.Username, request.Scoring)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create game: %v", err)})
+		respondError(c, locales, err, "Failed to create game")
 		return
 	}
 
@@ -141,84 +213,58 @@ func StartGame(c *gin.Context) {
 
 // GetNextQuestion handles requests to get the next question in a game
 func GetNextQuestion(c *gin.Context) {
+	locales := requestLocales(c)
+
 	gameID := c.Param("id")
 
 	// Convert gameID to int
 	gameIDInt, err := strconv.Atoi(gameID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		respondInvalid(c, locales, "Invalid game ID")
 		return
 	}
 
-	question, err := dataService.GetNextQuestion(gameIDInt)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Failed to get next question: %v", err)})
-		return
-	}
+	locales = gameLocales(c, gameIDInt)
 
-	// Check if there are more questions
-	hasNext, err := dataService.HasNextQuestion(gameIDInt)
+	response, err := dataService.NextQuestion(gameIDInt, locales)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to check for more questions: %v", err)})
+		respondError(c, locales, err, "Failed to get next question")
 		return
 	}
 
-	// Get destination details for each option
-	optionsDisplay := make(map[int]string)
-	for _, destID := range question.OptionDestinationIDs {
-		dest, err := dataService.GetDestinationByID(destID)
-		if err != nil {
-			continue // Skip if destination not found
-		}
-		optionsDisplay[destID] = fmt.Sprintf("%s, %s", dest.City, dest.Country)
-	}
-
-	// Create response
-	response := models.NextQuestionResponse{
-		GameID:         gameIDInt,
-		QuestionID:     question.ID,
-		Question:       question.Question,
-		OptionsDisplay: optionsDisplay,
-		HasNext:        hasNext,
-	}
-
 	c.JSON(http.StatusOK, response)
 }
 
 // SubmitAnswer handles requests to submit an answer for a question
 func SubmitAnswer(c *gin.Context) {
+	locales := requestLocales(c)
+
 	gameID := c.Param("id")
 
 	// Convert gameID to int
 	gameIDInt, err := strconv.Atoi(gameID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		respondInvalid(c, locales, "Invalid game ID")
 		return
 	}
 
 	var request models.SubmitAnswerRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondInvalid(c, locales, "Invalid request")
 		return
 	}
 
 	// Validate that the game ID in the URL matches the one in the request
 	if gameIDInt != request.GameID {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Game ID mismatch"})
+		respondInvalid(c, locales, "Game ID mismatch")
 		return
 	}
 
-	result, err := dataService.SubmitAnswer(request.GameID, request.QuestionID, request.SelectedDestination)
+	locales = gameLocales(c, gameIDInt)
+
+	result, err := dataService.SubmitAnswer(request.GameID, request.QuestionID, request.SelectedDestination, request.Pin, locales)
 	if err != nil {
-		// Check for specific error messages
-		if err.Error() == "question already answered" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Question already answered"})
-			return
-		} else if err.Error() == "selected destination is not in the list of options" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Selected destination is not in the list of options"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to submit answer: %v", err)})
+		respondError(c, locales, err, "Failed to submit answer")
 		return
 	}
 
@@ -227,18 +273,20 @@ func SubmitAnswer(c *gin.Context) {
 
 // GetGameResult handles requests to get the result of a game
 func GetGameResult(c *gin.Context) {
+	locales := requestLocales(c)
+
 	gameID := c.Param("id")
 
 	// Convert gameID to int
 	gameIDInt, err := strconv.Atoi(gameID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		respondInvalid(c, locales, "Invalid game ID")
 		return
 	}
 
 	result, err := dataService.GetGameResult(gameIDInt)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Failed to get game result: %v", err)})
+		respondError(c, locales, err, "Failed to get game result")
 		return
 	}
 
@@ -247,17 +295,19 @@ func GetGameResult(c *gin.Context) {
 
 // GetGameSummary handles requests to get a summary of a game
 func GetGameSummary(c *gin.Context) {
+	locales := requestLocales(c)
+
 	gameID := c.Param("id")
 	// Convert gameID to int
 	gameIDInt, err := strconv.Atoi(gameID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		respondInvalid(c, locales, "Invalid game ID")
 		return
 	}
 
 	summary, err := dataService.GetGameSummary(gameIDInt)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Failed to get game summary: %v", err)})
+		respondError(c, locales, err, "Failed to get game summary")
 		return
 	}
 
@@ -290,8 +340,13 @@ func ServeChallengePage(c *gin.Context) {
 	// Convert to string for easier manipulation
 	htmlContent := string(content)
 
-	// Define the image URL - use a reliable, consistent image
-	imageURL := images.GetTravelImage()
+	// Preview the challenge with a photo from the game when there is one
+	imageURL := images.DefaultImageURL
+	if gameIDInt, err := strconv.Atoi(gameID); err == nil {
+		if summary, err := dataService.GetGameSummary(gameIDInt); err == nil {
+			imageURL = absoluteURL(c, summary.ImageURL)
+		}
+	}
 
 	// Check if we already have OG tags
 	if !strings.Contains(htmlContent, "og:image") {
@@ -329,3 +384,21 @@ func ServeChallengePage(c *gin.Context) {
 	c.Header("Content-Type", "text/html")
 	c.String(http.StatusOK, htmlContent)
 }
+
+// absoluteURL turns a path served by this server into a full URL, since
+// social media crawlers don't resolve relative og:image URLs
+func absoluteURL(c *gin.Context, target string) string {
+	if !strings.HasPrefix(target, "/") {
+		return target
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + c.Request.Host + target
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+)
+
+// acceptedLocales returns the languages listed in the request's Accept-Language header
+func acceptedLocales(c *gin.Context) []string {
+	return i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// requestLocales returns the fallback chain for the request's Accept-Language header
+func requestLocales(c *gin.Context) []string {
+	return i18n.Chain(acceptedLocales(c)...)
+}
+
+// gameLocales returns the fallback chain for a game, preferring the player's chosen language
+func gameLocales(c *gin.Context, gameID int) []string {
+	return dataService.GameLocales(gameID, acceptedLocales(c))
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/openapi"
+)
+
+var (
+	// specJSON is the OpenAPI document served at /api/openapi.json
+	specJSON []byte
+	// specValidator checks requests and responses against the OpenAPI
+	// document, nil when validation is off
+	specValidator *openapi.Validator
+)
+
+// initSpec loads the OpenAPI document and turns on validation in debug mode,
+// or as set by OPENAPI_VALIDATION
+func initSpec() {
+	doc, err := openapi.Load()
+	if err != nil {
+		log.Fatalf("Failed to load the OpenAPI document: %v", err)
+	}
+	if specJSON, err = json.Marshal(doc); err != nil {
+		log.Fatalf("Failed to encode the OpenAPI document: %v", err)
+	}
+
+	enabled := gin.Mode() != gin.ReleaseMode
+	if value := os.Getenv("OPENAPI_VALIDATION"); value != "" {
+		if enabled, err = strconv.ParseBool(value); err != nil {
+			log.Fatalf("Invalid OPENAPI_VALIDATION %q", value)
+		}
+	}
+	if !enabled {
+		SetSpecValidator(nil)
+		return
+	}
+
+	validator, err := openapi.NewValidator(doc)
+	if err != nil {
+		log.Fatalf("Failed to set up OpenAPI validation: %v", err)
+	}
+	validator.Reject = func(c *gin.Context, problems []string) {
+		abortWithError(c, http.StatusBadRequest, CodeInvalidRequest, "Request does not match the API specification", problems, nil)
+	}
+	SetSpecValidator(validator)
+	log.Println("Validating API requests and responses against the OpenAPI document")
+}
+
+// SetSpecValidator replaces the validator of API requests and responses, nil
+// to turn validation off
+func SetSpecValidator(validator *openapi.Validator) {
+	specValidator = validator
+}
+
+// validateSpec checks the request and its response against the OpenAPI
+// document when validation is on
+func validateSpec(c *gin.Context) {
+	if specValidator != nil {
+		specValidator.Handle(c)
+	}
+}
+
+// GetOpenAPI serves the OpenAPI document of the API
+func GetOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", specJSON)
+}
@@ -0,0 +1,558 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/api"
+	"github.com/shubhsherl/globetrotter/backend/api/apitest"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/openapi"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.ReleaseMode)
+	os.Exit(apitest.Main(m, filepath.Join("..", "data", "data.json")))
+}
+
+// adminUser names the admin in the audit log of admin requests and seeds
+const adminUser = "apitest"
+
+// fixture is the state of a spec case: the router it is sent to and the
+// values its request refers to as {name}
+type fixture struct {
+	t      *testing.T
+	router *gin.Engine
+	values map[string]string
+}
+
+// seed creates state a case's request needs
+type seed func(f *fixture)
+
+// user creates the player alice
+func user(f *fixture) {
+	if _, err := api.Services().CreateUser("alice", ""); err != nil {
+		f.t.Fatalf("creating alice: %v", err)
+	}
+}
+
+// game starts a game for alice and records it as {game}, its first question
+// as {question} and that question's lowest option as {option}
+func game(scoring string) seed {
+	return func(f *fixture) {
+		data := api.Services()
+		gameID, err := data.CreateGame("alice", scoring)
+		if err != nil {
+			f.t.Fatalf("starting a game: %v", err)
+		}
+		question, err := data.NextQuestion(gameID, nil)
+		if err != nil {
+			f.t.Fatalf("getting a question: %v", err)
+		}
+		option := 0
+		for id := range question.OptionsDisplay {
+			if option == 0 || id < option {
+				option = id
+			}
+		}
+		f.values["game"] = strconv.Itoa(gameID)
+		f.values["question"] = strconv.Itoa(question.QuestionID)
+		f.values["option"] = strconv.Itoa(option)
+	}
+}
+
+// answered answers {question} with {option}
+func answered(f *fixture) {
+	gameID, _ := strconv.Atoi(f.values["game"])
+	questionID, _ := strconv.Atoi(f.values["question"])
+	option, _ := strconv.Atoi(f.values["option"])
+	if _, err := api.Services().SubmitAnswer(gameID, questionID, option, nil, nil); err != nil {
+		f.t.Fatalf("answering: %v", err)
+	}
+}
+
+// gamesStarted starts n games for alice through the API, so they count
+// towards her rate limit
+func gamesStarted(n int) seed {
+	return func(f *fixture) {
+		for i := 0; i < n; i++ {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/game/play", strings.NewReader(`{"username": "alice"}`))
+			f.router.ServeHTTP(recorder, req)
+			if recorder.Code != http.StatusCreated {
+				f.t.Fatalf("starting game %d: got status %d", i+1, recorder.Code)
+			}
+		}
+	}
+}
+
+// stats aggregates the answers into difficulty statistics
+func stats(f *fixture) {
+	if err := api.Services().RefreshDifficulty(); err != nil {
+		f.t.Fatalf("refreshing statistics: %v", err)
+	}
+}
+
+// submission has alice propose a destination, recorded as {submission}
+func submission(f *fixture) {
+	latitude, longitude := 10.5, 20.5
+	sub, err := api.Services().SubmitContent("alice", models.Submission{
+		Kind:      models.SubmissionDestination,
+		City:      "Specville",
+		Country:   "Apitestistan",
+		Latitude:  &latitude,
+		Longitude: &longitude,
+		Clues:     []string{"A city that only exists to be tested", "Its streets are all named after status codes"},
+		FunFact:   []string{"Every response here is documented"},
+		Trivia:    []string{"It was founded by a conformance test"},
+	})
+	if err != nil {
+		f.t.Fatalf("submitting: %v", err)
+	}
+	f.values["submission"] = strconv.Itoa(sub.ID)
+}
+
+// reviewed approves {submission}, or rejects it
+func reviewed(approve bool) seed {
+	return func(f *fixture) {
+		data := api.Services()
+		id, _ := strconv.Atoi(f.values["submission"])
+		var err error
+		if approve {
+			var sub *models.Submission
+			if sub, err = data.GetSubmission(id); err == nil {
+				_, err = data.ApproveSubmission(models.AdminActor(adminUser), *sub)
+			}
+		} else {
+			_, err = data.RejectSubmission(models.AdminActor(adminUser), id, "")
+		}
+		if err != nil {
+			f.t.Fatalf("reviewing submission %d: %v", id, err)
+		}
+	}
+}
+
+// destinationBody is the destination created by destination and admin requests
+const destinationBody = `{
+	"city": "Conformance",
+	"country": "Apitestistan",
+	"clues": ["Nothing here is undocumented", "Its only export is green checkmarks"],
+	"fun_fact": ["It was added by the API tests"],
+	"trivia": ["It is deleted again right away"]
+}`
+
+// destination creates the destination of destinationBody, recorded as
+// {destination}, and its first clue as {clue}
+func destination(f *fixture) {
+	var dest models.Destination
+	if err := json.Unmarshal([]byte(destinationBody), &dest); err != nil {
+		f.t.Fatal(err)
+	}
+	created, err := api.Services().CreateDestination(models.AdminActor(adminUser), dest)
+	if err != nil {
+		f.t.Fatalf("creating a destination: %v", err)
+	}
+	clues, err := api.Services().ListClues(created.ID, false)
+	if err != nil || len(clues) == 0 {
+		f.t.Fatalf("listing clues: got %d, %v", len(clues), err)
+	}
+	f.values["destination"] = strconv.Itoa(created.ID)
+	f.values["clue"] = strconv.Itoa(clues[0].ID)
+}
+
+// emptyDataset points dataset reloads at a file without destinations
+func emptyDataset(f *fixture) {
+	path := filepath.Join(f.t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		f.t.Fatal(err)
+	}
+	f.t.Setenv("DATASET_PATH", path)
+}
+
+// missing is an ID nothing has
+const missing = "999999"
+
+// specCase is a request sent to a fresh database, after its seeds
+type specCase struct {
+	name   string
+	seeds  []seed
+	method string
+	path   string // May refer to seeded values as {name}
+	body   string // JSON, sent unless empty, with the same references
+	admin  bool   // Send the admin token
+	header map[string]string
+	// invalid marks requests that deliberately don't match the document, to
+	// check the handler's response to them
+	invalid bool
+	want    int    // Expected status
+	code    string // Expected error code, checked when set
+}
+
+var (
+	inSpanish    = map[string]string{"Accept-Language": "es"}
+	asAdminUser  = map[string]string{"X-Admin-User": adminUser}
+	submitAnswer = `{"game_id": {game}, "question_id": {question}, "selected_destination": {option}}`
+)
+
+// specCases request every documented operation, with requests that succeed
+// and ones that fail
+var specCases = []specCase{
+	{name: "health", method: "GET", path: "/health", want: http.StatusOK},
+	{name: "document", method: "GET", path: "/api/openapi.json", want: http.StatusOK},
+
+	// Destinations
+	{name: "random destination", method: "GET", path: "/api/destinations/random", want: http.StatusOK},
+	{name: "random destination by country", method: "GET", path: "/api/destinations/random?country=France", want: http.StatusOK},
+	{name: "random destination by region in Spanish", method: "GET", path: "/api/destinations/random?region=europe", header: inSpanish, want: http.StatusOK},
+	{name: "random destination in unknown region", method: "GET", path: "/api/destinations/random?region=atlantis", invalid: true, want: http.StatusBadRequest, code: "unknown_region"},
+
+	// Users
+	{name: "create user", method: "POST", path: "/api/users", body: `{"username": "alice"}`, want: http.StatusCreated},
+	{name: "create user with locale", method: "POST", path: "/api/users", body: `{"username": "alice", "locale": "es"}`, want: http.StatusCreated},
+	{name: "create taken user", seeds: []seed{user}, method: "POST", path: "/api/users", body: `{"username": "alice"}`, want: http.StatusConflict, code: "username_taken"},
+	{name: "create user with invalid locale", method: "POST", path: "/api/users", body: `{"username": "alice", "locale": "not a locale"}`, want: http.StatusBadRequest, code: "invalid_locale"},
+	{name: "create user without name", method: "POST", path: "/api/users", body: `{}`, invalid: true, want: http.StatusBadRequest, code: "invalid_request"},
+	{name: "get user", seeds: []seed{user}, method: "GET", path: "/api/users/alice", want: http.StatusOK},
+	{name: "get missing user", method: "GET", path: "/api/users/nobody", header: map[string]string{"X-Request-ID": "apitest-nobody"}, want: http.StatusNotFound, code: "user_not_found"},
+	{name: "set locale", seeds: []seed{user}, method: "PATCH", path: "/api/users/alice", body: `{"locale": "fr"}`, want: http.StatusOK},
+	{name: "clear locale", seeds: []seed{user}, method: "PATCH", path: "/api/users/alice", body: `{"locale": ""}`, want: http.StatusOK},
+	{name: "update user without locale", seeds: []seed{user}, method: "PATCH", path: "/api/users/alice", body: `{}`, invalid: true, want: http.StatusBadRequest},
+	{name: "set locale of missing user", method: "PATCH", path: "/api/users/nobody", body: `{"locale": "fr"}`, want: http.StatusNotFound, code: "user_not_found"},
+
+	// Games
+	{name: "start game", seeds: []seed{user}, method: "POST", path: "/api/game/play", body: `{"username": "alice"}`, want: http.StatusCreated},
+	{name: "start pin game", seeds: []seed{user}, method: "POST", path: "/api/game/play", body: `{"username": "alice", "scoring": "pin"}`, want: http.StatusCreated},
+	{name: "start game with unknown scoring", seeds: []seed{user}, method: "POST", path: "/api/game/play", body: `{"username": "alice", "scoring": "golf"}`, invalid: true, want: http.StatusBadRequest, code: "invalid_scoring"},
+	{name: "start game without player", method: "POST", path: "/api/game/play", body: `{}`, invalid: true, want: http.StatusBadRequest},
+	{name: "start game over the limit", seeds: []seed{user, gamesStarted(apitest.GamesPerHour)}, method: "POST", path: "/api/game/play", body: `{"username": "alice"}`, want: http.StatusTooManyRequests, code: "rate_limited"},
+	{name: "next question", seeds: []seed{user, game("")}, method: "GET", path: "/api/game/{game}/next-question", header: inSpanish, want: http.StatusOK},
+	{name: "next question of invalid game", method: "GET", path: "/api/game/abc/next-question", invalid: true, want: http.StatusBadRequest, code: "invalid_request"},
+	{name: "next question of missing game", method: "GET", path: "/api/game/" + missing + "/next-question", want: http.StatusNotFound, code: "game_not_found"},
+	{name: "submit answer", seeds: []seed{user, game("")}, method: "POST", path: "/api/game/{game}/submit-answer", body: submitAnswer, want: http.StatusOK},
+	{name: "submit answer again", seeds: []seed{user, game(""), answered}, method: "POST", path: "/api/game/{game}/submit-answer", body: submitAnswer, want: http.StatusConflict, code: "already_answered"},
+	{name: "submit answer to another game", seeds: []seed{user, game("")}, method: "POST", path: "/api/game/{game}/submit-answer", body: `{"game_id": ` + missing + `, "question_id": {question}}`, want: http.StatusBadRequest, code: "invalid_request"},
+	{name: "submit empty answer", seeds: []seed{user, game("")}, method: "POST", path: "/api/game/{game}/submit-answer", body: `{}`, invalid: true, want: http.StatusBadRequest},
+	{name: "submit pin", seeds: []seed{user, game(models.ScoringPin)}, method: "POST", path: "/api/game/{game}/submit-answer",
+		body: `{"game_id": {game}, "question_id": {question}, "pin": {"latitude": 48.85, "longitude": 2.35}}`, want: http.StatusOK},
+	{name: "game result", seeds: []seed{user, game(""), answered}, method: "GET", path: "/api/game/{game}/result", want: http.StatusOK},
+	{name: "result of missing game", method: "GET", path: "/api/game/" + missing + "/result", want: http.StatusNotFound, code: "game_not_found"},
+	{name: "game summary", seeds: []seed{user, game("")}, method: "GET", path: "/api/game/{game}/summary", want: http.StatusOK},
+	{name: "summary of missing game", method: "GET", path: "/api/game/" + missing + "/summary", want: http.StatusNotFound, code: "game_not_found"},
+
+	// Submissions
+	{name: "submit destination", seeds: []seed{user}, method: "POST", path: "/api/submissions",
+		body: `{"username": "alice", "kind": "destination", "city": "Specville", "country": "Apitestistan", "latitude": 10.5, "longitude": 20.5,
+			"clues": ["A city that only exists to be tested", "Its streets are all named after status codes"],
+			"fun_fact": ["Every response here is documented"], "trivia": ["It was founded by a conformance test"]}`,
+		want: http.StatusCreated},
+	{name: "submit incomplete destination", seeds: []seed{user}, method: "POST", path: "/api/submissions", body: `{"username": "alice", "kind": "destination"}`, want: http.StatusBadRequest, code: "invalid_submission"},
+	{name: "submit unknown kind", seeds: []seed{user}, method: "POST", path: "/api/submissions", body: `{"username": "alice", "kind": "rumour"}`, invalid: true, want: http.StatusBadRequest},
+	{name: "submit as missing user", method: "POST", path: "/api/submissions",
+		body: `{"username": "nobody", "kind": "destination", "city": "Nowhere", "country": "Apitestistan", "clues": ["One", "Two"], "fun_fact": ["Fun"], "trivia": ["Trivia"]}`,
+		want: http.StatusNotFound, code: "user_not_found"},
+	{name: "user submissions", seeds: []seed{user, submission}, method: "GET", path: "/api/users/alice/submissions", want: http.StatusOK},
+	{name: "submissions of missing user", method: "GET", path: "/api/users/nobody/submissions", want: http.StatusNotFound, code: "user_not_found"},
+
+	// Admin destinations and clues
+	{name: "list destinations without token", method: "GET", path: "/api/admin/destinations", want: http.StatusUnauthorized, code: "unauthorized"},
+	{name: "list destinations", method: "GET", path: "/api/admin/destinations", admin: true, want: http.StatusOK},
+	{name: "list destinations with retired", method: "GET", path: "/api/admin/destinations?include_retired=true", admin: true, want: http.StatusOK},
+	{name: "create destination", method: "POST", path: "/api/admin/destinations", body: destinationBody, admin: true, header: asAdminUser, want: http.StatusCreated},
+	{name: "create duplicate destination", seeds: []seed{destination}, method: "POST", path: "/api/admin/destinations", body: destinationBody, admin: true, want: http.StatusConflict, code: "duplicate_destination"},
+	{name: "create invalid destination", method: "POST", path: "/api/admin/destinations", body: `{"city": "Nowhere"}`, admin: true, want: http.StatusBadRequest, code: "invalid_destination"},
+	{name: "get destination", seeds: []seed{destination}, method: "GET", path: "/api/admin/destinations/{destination}", admin: true, want: http.StatusOK},
+	{name: "get missing destination", method: "GET", path: "/api/admin/destinations/" + missing, admin: true, want: http.StatusNotFound, code: "destination_not_found"},
+	{name: "get invalid destination", method: "GET", path: "/api/admin/destinations/abc", admin: true, invalid: true, want: http.StatusBadRequest, code: "invalid_request"},
+	{name: "update destination", seeds: []seed{destination}, method: "PUT", path: "/api/admin/destinations/{destination}",
+		body: `{"city": "Conformance", "country": "Apitestistan", "latitude": 1.5, "longitude": 2.5,
+			"clues": ["Nothing here is undocumented", "Its only export is green checkmarks"], "fun_fact": ["Moved"], "trivia": ["Twice"]}`,
+		admin: true, header: asAdminUser, want: http.StatusOK},
+	{name: "update missing destination", method: "PUT", path: "/api/admin/destinations/" + missing, body: destinationBody, admin: true, want: http.StatusNotFound, code: "destination_not_found"},
+	{name: "delete destination", seeds: []seed{destination}, method: "DELETE", path: "/api/admin/destinations/{destination}", admin: true, header: asAdminUser, want: http.StatusOK},
+	{name: "delete missing destination", method: "DELETE", path: "/api/admin/destinations/" + missing, admin: true, want: http.StatusNotFound, code: "destination_not_found"},
+	{name: "list clues", seeds: []seed{destination}, method: "GET", path: "/api/admin/destinations/{destination}/clues", admin: true, want: http.StatusOK},
+	{name: "list clues with retired", seeds: []seed{destination}, method: "GET", path: "/api/admin/destinations/{destination}/clues?include_retired=true", admin: true, want: http.StatusOK},
+	{name: "list clues of missing destination", method: "GET", path: "/api/admin/destinations/" + missing + "/clues", admin: true, want: http.StatusNotFound, code: "destination_not_found"},
+	{name: "tag clue", seeds: []seed{destination}, method: "PATCH", path: "/api/admin/clues/{clue}", body: `{"tags": ["easy"]}`, admin: true, header: asAdminUser, want: http.StatusOK},
+	{name: "empty clue", seeds: []seed{destination}, method: "PATCH", path: "/api/admin/clues/{clue}", body: `{"text": ""}`, admin: true, want: http.StatusBadRequest, code: "invalid_clue"},
+	{name: "retire missing clue", method: "PATCH", path: "/api/admin/clues/" + missing, body: `{"retired": true}`, admin: true, want: http.StatusNotFound, code: "clue_not_found"},
+
+	// Admin submissions
+	{name: "list submissions", seeds: []seed{user, submission}, method: "GET", path: "/api/admin/submissions", admin: true, want: http.StatusOK},
+	{name: "list all submissions", seeds: []seed{user, submission, reviewed(false)}, method: "GET", path: "/api/admin/submissions?status=all", admin: true, want: http.StatusOK},
+	{name: "list submissions with unknown status", method: "GET", path: "/api/admin/submissions?status=lost", admin: true, invalid: true, want: http.StatusBadRequest, code: "invalid_request"},
+	{name: "get submission", seeds: []seed{user, submission}, method: "GET", path: "/api/admin/submissions/{submission}", admin: true, want: http.StatusOK},
+	{name: "get missing submission", method: "GET", path: "/api/admin/submissions/" + missing, admin: true, want: http.StatusNotFound, code: "submission_not_found"},
+	{name: "approve submission", seeds: []seed{user, submission}, method: "POST", path: "/api/admin/submissions/{submission}/approve",
+		body: `{"note": "Welcome", "city": "Spec City"}`, admin: true, header: asAdminUser, want: http.StatusOK},
+	{name: "approve reviewed submission", seeds: []seed{user, submission, reviewed(true)}, method: "POST", path: "/api/admin/submissions/{submission}/approve", admin: true, want: http.StatusConflict, code: "submission_reviewed"},
+	{name: "approve missing submission", method: "POST", path: "/api/admin/submissions/" + missing + "/approve", admin: true, want: http.StatusNotFound, code: "submission_not_found"},
+	{name: "reject submission", seeds: []seed{user, submission}, method: "POST", path: "/api/admin/submissions/{submission}/reject", admin: true, header: asAdminUser, want: http.StatusOK},
+	{name: "reject reviewed submission", seeds: []seed{user, submission, reviewed(false)}, method: "POST", path: "/api/admin/submissions/{submission}/reject", body: `{"note": "Twice"}`, admin: true, want: http.StatusConflict, code: "submission_reviewed"},
+	{name: "reject missing submission", method: "POST", path: "/api/admin/submissions/" + missing + "/reject", admin: true, want: http.StatusNotFound, code: "submission_not_found"},
+
+	// Analytics, before and after answers are aggregated
+	{name: "empty destination statistics", method: "GET", path: "/api/admin/analytics/destinations", admin: true, want: http.StatusOK},
+	{name: "empty clue statistics", method: "GET", path: "/api/admin/analytics/clues", admin: true, want: http.StatusOK},
+	{name: "empty difficulty report", method: "GET", path: "/api/admin/analytics/difficulty", admin: true, want: http.StatusOK},
+	{name: "refresh statistics", seeds: []seed{user, game(""), answered}, method: "POST", path: "/api/admin/analytics/refresh", admin: true, want: http.StatusNoContent},
+	{name: "destination statistics", seeds: []seed{user, game(""), answered, stats}, method: "GET", path: "/api/admin/analytics/destinations", admin: true, want: http.StatusOK},
+	{name: "clue statistics", seeds: []seed{user, game(""), answered, stats}, method: "GET", path: "/api/admin/analytics/clues", admin: true, want: http.StatusOK},
+	{name: "difficulty report", seeds: []seed{user, game(""), answered, stats}, method: "GET", path: "/api/admin/analytics/difficulty?min_samples=1", admin: true, want: http.StatusOK},
+	{name: "difficulty report without samples", method: "GET", path: "/api/admin/analytics/difficulty?min_samples=0", admin: true, invalid: true, want: http.StatusBadRequest, code: "invalid_request"},
+
+	// Dataset
+	{name: "reload dataset", method: "POST", path: "/api/admin/dataset/reload", admin: true, header: asAdminUser, want: http.StatusOK},
+	{name: "reload invalid dataset", seeds: []seed{emptyDataset}, method: "POST", path: "/api/admin/dataset/reload?prune=true", admin: true, want: http.StatusUnprocessableEntity, code: "invalid_dataset"},
+
+	// Audit log
+	{name: "audit log", seeds: []seed{destination}, method: "GET", path: "/api/admin/audit", admin: true, want: http.StatusOK},
+	{name: "filtered audit log", seeds: []seed{destination}, method: "GET", path: "/api/admin/audit?actor=admin:" + adminUser + "&target_type=destination&limit=5", admin: true, want: http.StatusOK},
+	{name: "audit log by time", seeds: []seed{destination}, method: "GET", path: "/api/admin/audit?since=2000-01-01T00:00:00Z&until=2100-01-01T00:00:00Z&before=1000", admin: true, want: http.StatusOK},
+	{name: "audit log with invalid time", method: "GET", path: "/api/admin/audit?since=yesterday", admin: true, invalid: true, want: http.StatusBadRequest, code: "invalid_request"},
+}
+
+// TestSpec sends each case's request to a database of its own, and checks
+// its status and that it and its response match the OpenAPI document
+func TestSpec(t *testing.T) {
+	for _, tt := range specCases {
+		t.Run(tt.name, func(t *testing.T) {
+			apitest.Open(t)
+			f := &fixture{t: t, router: gin.New(), values: make(map[string]string)}
+			api.SetupRoutes(f.router)
+			for _, seed := range tt.seeds {
+				seed(f)
+			}
+
+			// Requests are not rejected, so handlers' own error responses are checked too
+			var mismatches []openapi.Mismatch
+			validator := newValidator(t)
+			validator.ReportOnly = true
+			validator.OnMismatch = func(m openapi.Mismatch) {
+				mismatches = append(mismatches, m)
+			}
+			api.SetSpecValidator(validator)
+
+			recorder := f.send(tt)
+			if recorder.Code != tt.want {
+				t.Errorf("got status %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+			checkRequestID(t, tt, recorder)
+			if recorder.Code == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") == "" {
+				t.Error("429 response has no Retry-After header")
+			}
+
+			requestMismatched := false
+			for _, m := range mismatches {
+				if m.Kind == openapi.MismatchRequest {
+					requestMismatched = true
+					if tt.invalid {
+						continue
+					}
+				}
+				t.Errorf("%s", m)
+			}
+			if tt.invalid && !requestMismatched {
+				t.Error("request was expected not to match the API specification")
+			}
+		})
+	}
+}
+
+// send sends the case's request with the fixture's values filled in
+func (f *fixture) send(tt specCase) *httptest.ResponseRecorder {
+	var replacements []string
+	for name, value := range f.values {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	fill := strings.NewReplacer(replacements...).Replace
+
+	req := httptest.NewRequest(tt.method, fill(tt.path), strings.NewReader(fill(tt.body)))
+	if tt.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if tt.admin {
+		req.Header.Set("Authorization", "Bearer "+apitest.AdminToken)
+	}
+	for name, value := range tt.header {
+		req.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// checkRequestID checks that the response carries the request's ID, the one
+// sent by the case when there is one, and that an error response reports it
+// with the expected code
+func checkRequestID(t *testing.T, tt specCase, recorder *httptest.ResponseRecorder) {
+	t.Helper()
+	id := recorder.Header().Get("X-Request-ID")
+	if id == "" {
+		t.Error("response has no X-Request-ID header")
+	} else if sent := tt.header["X-Request-ID"]; sent != "" && id != sent {
+		t.Errorf("got request ID %q, want the one sent, %q", id, sent)
+	}
+	if recorder.Code < http.StatusBadRequest {
+		return
+	}
+
+	var failure struct {
+		Error struct {
+			Code      string `json:"code"`
+			RequestID string `json:"request_id"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &failure); err != nil {
+		t.Errorf("failed to decode error response %q: %v", recorder.Body.String(), err)
+	}
+	if failure.Error.RequestID != id {
+		t.Errorf("error has request ID %q, header has %q", failure.Error.RequestID, id)
+	}
+	if tt.code != "" && failure.Error.Code != tt.code {
+		t.Errorf("got error code %q, want %q", failure.Error.Code, tt.code)
+	}
+}
+
+// newValidator creates a validator for the OpenAPI document
+func newValidator(t *testing.T) *openapi.Validator {
+	t.Helper()
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := openapi.NewValidator(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return validator
+}
+
+// TestSpecRoutes checks that the registered routes and the documented
+// operations are the same
+func TestSpecRoutes(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	api.SetupRoutes(router)
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		// Gin's :param is {param} in the document
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") {
+				segments[i] = "{" + segment[1:] + "}"
+			}
+		}
+		path := strings.Join(segments, "/")
+		registered[route.Method+" "+path] = true
+
+		item := doc.Paths.Find(path)
+		if item == nil || item.GetOperation(route.Method) == nil {
+			t.Errorf("%s %s: route is not in the API specification", route.Method, route.Path)
+		}
+	}
+
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s: documented operation has no route", method, path)
+			}
+		}
+	}
+}
+
+// TestSpecCoverage checks that every documented operation has a case
+func TestSpecCoverage(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	covered := make(map[string]bool)
+	for _, tt := range specCases {
+		path := tt.path
+		for strings.Contains(path, "{") {
+			start := strings.Index(path, "{")
+			end := strings.Index(path[start:], "}")
+			path = path[:start] + "1" + path[start+end+1:]
+		}
+		route, _, err := router.FindRoute(httptest.NewRequest(tt.method, path, nil))
+		if err != nil {
+			t.Errorf("%s: %s %s is not a documented operation: %v", tt.name, tt.method, tt.path, err)
+			continue
+		}
+		covered[route.Operation.OperationID] = true
+	}
+
+	var missed []string
+	for path, item := range doc.Paths.Map() {
+		for method, operation := range item.Operations() {
+			if !covered[operation.OperationID] {
+				missed = append(missed, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(missed)
+	for _, operation := range missed {
+		t.Errorf("%s: operation has no case, add one to specCases", operation)
+	}
+}
+
+// TestSpecValidation checks which requests validation rejects when it is on
+func TestSpecValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		contentType string
+		admin       bool
+		want        int
+	}{
+		// Handlers parse JSON bodies whatever their Content-Type
+		{"JSON without Content-Type", "/api/users", `{"username": "untyped"}`, "", false, http.StatusCreated},
+		{"JSON sent as text", "/api/users", `{"username": "texted"}`, "text/plain", false, http.StatusCreated},
+		{"invalid JSON without Content-Type", "/api/users", `{}`, "", false, http.StatusBadRequest},
+		// Admin requests are authenticated before they are validated
+		{"invalid admin request without a token", "/api/admin/destinations", `{"city": 5}`, "application/json", false, http.StatusUnauthorized},
+		{"invalid admin request", "/api/admin/destinations", `{"city": 5}`, "application/json", true, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apitest.Open(t)
+			api.SetSpecValidator(newValidator(t))
+			router := gin.New()
+			api.SetupRoutes(router)
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.admin {
+				req.Header.Set("Authorization", "Bearer "+apitest.AdminToken)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			if recorder.Code != tt.want {
+				t.Errorf("got status %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+		})
+	}
+}
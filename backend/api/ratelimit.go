@@ -0,0 +1,281 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/ratelimit"
+)
+
+// CodeRateLimited is the error code of requests refused by a rate limit
+const CodeRateLimited = "rate_limited"
+
+// rateLimitGroup holds the limits of a group of routes, per client IP and
+// per player. Each group has its own buckets.
+type rateLimitGroup struct {
+	name    string // Used in bucket keys and the RATE_LIMIT_<NAME>_IP and _USER variables
+	perIP   ratelimit.Limit
+	perUser ratelimit.Limit
+}
+
+// Rate limited route groups, with their default limits
+var (
+	// Creating and changing players and submitting content
+	accountLimits = rateLimitGroup{
+		name:    "accounts",
+		perIP:   ratelimit.Limit{Rate: 30, Per: time.Hour},
+		perUser: ratelimit.Limit{Rate: 10, Per: time.Hour},
+	}
+	// Starting games
+	gameLimits = rateLimitGroup{
+		name:    "games",
+		perIP:   ratelimit.Limit{Rate: 60, Per: time.Hour},
+		perUser: ratelimit.Limit{Rate: 30, Per: time.Hour},
+	}
+	// Answering questions
+	answerLimits = rateLimitGroup{
+		name:    "answers",
+		perIP:   ratelimit.Limit{Rate: 120, Per: time.Minute},
+		perUser: ratelimit.Limit{Rate: 60, Per: time.Minute},
+	}
+)
+
+// rateLimitGroups lists every rate limited group
+var rateLimitGroups = []*rateLimitGroup{&accountLimits, &gameLimits, &answerLimits}
+
+// rateLimitStore keeps the buckets of every rate limit
+var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+
+// SetRateLimitStore replaces the in-memory store of rate limit buckets, for
+// example with one shared by every server behind a load balancer
+func SetRateLimitStore(store ratelimit.Store) {
+	rateLimitStore = store
+}
+
+// initRateLimits reads the limits of each group from the environment:
+// RATE_LIMIT_<GROUP>_IP and RATE_LIMIT_<GROUP>_USER, such as "30/m" or
+// "off". RATE_LIMIT=off turns every limit off.
+func initRateLimits() {
+	// Cached owners belong to the games of the previous services
+	gameOwners.Lock()
+	gameOwners.ids = make(map[int]int)
+	gameOwners.Unlock()
+
+	if os.Getenv("RATE_LIMIT") == "off" {
+		for _, group := range rateLimitGroups {
+			group.perIP, group.perUser = ratelimit.Limit{}, ratelimit.Limit{}
+		}
+		log.Println("Rate limits are off")
+		return
+	}
+
+	for _, group := range rateLimitGroups {
+		prefix := "RATE_LIMIT_" + strings.ToUpper(group.name)
+		for _, limit := range []struct {
+			name   string
+			target *ratelimit.Limit
+		}{
+			{prefix + "_IP", &group.perIP},
+			{prefix + "_USER", &group.perUser},
+		} {
+			value := os.Getenv(limit.name)
+			if value == "" {
+				continue
+			}
+			parsed, err := ratelimit.ParseLimit(value)
+			if err != nil {
+				log.Fatalf("Invalid %s: %v", limit.name, err)
+			}
+			*limit.target = parsed
+		}
+		log.Printf("Rate limits of %s: %s per IP, %s per player", group.name, group.perIP, group.perUser)
+	}
+}
+
+// limitRate refuses requests beyond the group's limits with 429 Too Many
+// Requests and a Retry-After header. Requests are counted per client IP and,
+// when they act for a player, per player.
+func limitRate(group *rateLimitGroup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !takeToken(c, group.name+":ip:"+c.ClientIP(), group.perIP) {
+			return
+		}
+		if group.perUser.Enabled() {
+			if player := ratePlayer(c); player != "" && !takeToken(c, group.name+":player:"+player, group.perUser) {
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// takeToken takes a token from the bucket named key and reports whether the
+// request may go on, ending it with a 429 when it may not
+func takeToken(c *gin.Context, key string, limit ratelimit.Limit) bool {
+	ok, wait, err := allow(c.Request.Context(), key, limit)
+	if err != nil {
+		log.Printf("[%s] Failed to check rate limit: %v", requestID(c), err)
+	}
+	if ok {
+		return true
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	abortWithError(c, http.StatusTooManyRequests, CodeRateLimited, i18n.T(requestLocales(c), "Too many requests, try again later"), nil, nil)
+	return false
+}
+
+// allow takes a token from the bucket named key, reporting whether the call
+// may go on and, when it may not, how long until it may. Calls are let
+// through if the store fails, with its error, so an unreachable shared store
+// doesn't take the game down with it.
+func allow(ctx context.Context, key string, limit ratelimit.Limit) (bool, time.Duration, error) {
+	if !limit.Enabled() {
+		return true, 0, nil
+	}
+	ok, wait, err := rateLimitStore.Take(ctx, key, limit)
+	if err != nil {
+		return true, 0, err
+	}
+	return ok, wait, nil
+}
+
+// RateLimit applies the limits of a group of routes to a call that doesn't
+// go through them, such as a gRPC call, counting it in the same buckets.
+// group is "accounts", "games" or "answers"; the call comes from ip and acts
+// for the player named username or, when that is empty, the player of
+// gameID. It reports whether the call may go on and, when it may not, how
+// long until it may. Calls of other groups are not limited.
+func RateLimit(ctx context.Context, group, ip, username string, gameID int) (bool, time.Duration) {
+	for _, g := range rateLimitGroups {
+		if g.name != group {
+			continue
+		}
+
+		if ok, wait := takeCallToken(ctx, g.name+":ip:"+ip, g.perIP); !ok {
+			return false, wait
+		}
+		if !g.perUser.Enabled() {
+			return true, 0
+		}
+		var player string
+		if username != "" {
+			player = "name:" + username
+		} else if gameID > 0 {
+			if owner, ok := gameOwner(gameID); ok {
+				player = "id:" + strconv.Itoa(owner)
+			}
+		}
+		if player != "" {
+			return takeCallToken(ctx, g.name+":player:"+player, g.perUser)
+		}
+		return true, 0
+	}
+	return true, 0
+}
+
+// takeCallToken takes a token for a call made outside the routes, logging
+// store failures
+func takeCallToken(ctx context.Context, key string, limit ratelimit.Limit) (bool, time.Duration) {
+	ok, wait, err := allow(ctx, key, limit)
+	if err != nil {
+		log.Printf("Failed to check rate limit of %s: %v", key, err)
+	}
+	return ok, wait
+}
+
+// maxRateBody is the most of a request body ratePlayer reads looking for a
+// username. Requests with larger bodies are only limited per IP.
+const maxRateBody = 64 << 10
+
+// ratePlayer identifies the player a request acts for: the username in the
+// path or the JSON body, or the owner of the game in the path. Each group's
+// routes identify players the same way. Requests for no known player are
+// only limited per IP.
+func ratePlayer(c *gin.Context) string {
+	if username := c.Param("username"); username != "" {
+		return "name:" + username
+	}
+
+	if id := c.Param("id"); id != "" {
+		gameID, err := strconv.Atoi(id)
+		if err != nil {
+			return ""
+		}
+		owner, ok := gameOwner(gameID)
+		if !ok {
+			return ""
+		}
+		return "id:" + strconv.Itoa(owner)
+	}
+
+	if c.Request.Body == nil {
+		return ""
+	}
+	// Put back what was read, followed by the rest of the body
+	original := c.Request.Body
+	body, err := io.ReadAll(io.LimitReader(original, maxRateBody+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), original), original}
+	if err != nil || len(body) > maxRateBody {
+		return ""
+	}
+	var request struct {
+		Username string `json:"username"`
+	}
+	if json.Unmarshal(body, &request) != nil || request.Username == "" {
+		return ""
+	}
+	return "name:" + request.Username
+}
+
+// maxGameOwners is the number of games whose owner is cached before the
+// cache is cleared
+const maxGameOwners = 10000
+
+// gameOwners caches the player of each game seen by the rate limits, so
+// answering a question doesn't load the game once more. A game's player
+// never changes.
+var gameOwners = struct {
+	sync.Mutex
+	ids map[int]int
+}{ids: make(map[int]int)}
+
+// gameOwner returns the ID of the player of a game, and false if there is
+// no such game
+func gameOwner(gameID int) (int, bool) {
+	gameOwners.Lock()
+	owner, ok := gameOwners.ids[gameID]
+	gameOwners.Unlock()
+	if ok {
+		return owner, true
+	}
+
+	game, err := dataService.GetGame(gameID)
+	if err != nil {
+		return 0, false
+	}
+
+	gameOwners.Lock()
+	defer gameOwners.Unlock()
+	if len(gameOwners.ids) >= maxGameOwners {
+		gameOwners.ids = make(map[int]int)
+	}
+	gameOwners.ids[gameID] = game.UserID
+	return game.UserID, true
+}
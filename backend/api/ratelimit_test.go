@@ -0,0 +1,215 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/api"
+	"github.com/shubhsherl/globetrotter/backend/api/apitest"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// createUsers creates players and returns them by name
+func createUsers(t *testing.T, usernames ...string) map[string]models.User {
+	t.Helper()
+	users := make(map[string]models.User)
+	for _, username := range usernames {
+		user, err := api.Services().CreateUser(username, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user, err = api.Services().GetUser(username); err != nil {
+			t.Fatal(err)
+		}
+		users[username] = user
+	}
+	return users
+}
+
+// TestRateLimited checks the response to a request over a limit, and that
+// calls outside the routes count against the same buckets
+func TestRateLimited(t *testing.T) {
+	apitest.Open(t)
+	router := gin.New()
+	api.SetupRoutes(router)
+	createUsers(t, "alice", "bob")
+
+	startGame := func(username string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/game/play", strings.NewReader(`{"username":"`+username+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "es")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	var gameID int
+	for i := 0; i < apitest.GamesPerHour; i++ {
+		recorder := startGame("alice")
+		if recorder.Code != http.StatusCreated {
+			t.Fatalf("game %d: got status %d: %s", i+1, recorder.Code, recorder.Body.String())
+		}
+		var started struct {
+			GameID int `json:"game_id"`
+		}
+		json.Unmarshal(recorder.Body.Bytes(), &started)
+		gameID = started.GameID
+	}
+
+	recorder := startGame("alice")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want 429: %s", recorder.Code, recorder.Body.String())
+	}
+	// A token is back after an hour divided by the rate, rounded up to seconds
+	if got, want := recorder.Header().Get("Retry-After"), strconv.Itoa(3600/apitest.GamesPerHour); got != want {
+		t.Errorf("got Retry-After %q, want %q", got, want)
+	}
+	var failure struct {
+		Error struct {
+			Code      string `json:"code"`
+			Message   string `json:"message"`
+			RequestID string `json:"request_id"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &failure); err != nil {
+		t.Fatalf("failed to decode %q: %v", recorder.Body.String(), err)
+	}
+	if failure.Error.Code != api.CodeRateLimited ||
+		failure.Error.Message != i18n.T([]string{"es"}, "Too many requests, try again later") ||
+		failure.Error.RequestID == "" || failure.Error.RequestID != recorder.Header().Get("X-Request-ID") {
+		t.Errorf("got error %+v, want the rate_limited envelope in Spanish", failure.Error)
+	}
+
+	// The limit is per player: bob plays on from the same IP
+	if recorder := startGame("bob"); recorder.Code != http.StatusCreated {
+		t.Errorf("bob got status %d, want 201", recorder.Code)
+	}
+
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name     string
+		group    string
+		username string
+		gameID   int
+		ok       bool
+	}{
+		{"alice", "games", "alice", 0, false},
+		{"bob", "games", "bob", 0, true},
+		{"a group with no limits", "scores", "alice", 0, true},
+	} {
+		ok, wait := api.RateLimit(ctx, tt.group, "198.51.100.7", tt.username, tt.gameID)
+		if ok != tt.ok {
+			t.Errorf("RateLimit of %s: got %t, want %t", tt.name, ok, tt.ok)
+		}
+		if !ok && (wait <= 0 || wait > time.Hour/apitest.GamesPerHour) {
+			t.Errorf("RateLimit of %s: got a wait of %s", tt.name, wait)
+		}
+	}
+
+	// Answers are counted per player of the game, across their games
+	otherID, err := api.Services().CreateGame("alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answers := 0
+	for ok := true; ok; answers++ {
+		if answers > 1000 {
+			t.Fatal("RateLimit never refused an answer")
+		}
+		ok, _ = api.RateLimit(ctx, "answers", "198.51.100.7", "", gameID)
+	}
+	if ok, _ := api.RateLimit(ctx, "answers", "198.51.100.7", "", otherID); ok {
+		t.Error("alice's other game has answers left")
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/game/"+strconv.Itoa(otherID)+"/submit-answer",
+		strings.NewReader(`{"game_id":`+strconv.Itoa(otherID)+`,"question_id":1,"selected_destination":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("answering over REST after %d answers over RateLimit: got status %d, want 429", answers-1, recorder.Code)
+	}
+}
+
+// TestRatePlayer checks how requests are matched to the player they act for
+func TestRatePlayer(t *testing.T) {
+	apitest.Open(t)
+	users := createUsers(t, "alice")
+	gameID, err := api.Services().CreateGame("alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large := `{"username":"alice","padding":"` + strings.Repeat("x", 2*api.MaxRateBody) + `"}`
+	for _, tt := range []struct {
+		name   string
+		params gin.Params
+		body   string
+		want   string
+	}{
+		{"username in the path", gin.Params{{Key: "username", Value: "bob"}}, `{"username":"alice"}`, "name:bob"},
+		{"username in the body", nil, `{"username":"alice","scoring":"pin"}`, "name:alice"},
+		{"game in the path", gin.Params{{Key: "id", Value: strconv.Itoa(gameID)}}, "", "id:" + strconv.Itoa(users["alice"].ID)},
+		{"unknown game", gin.Params{{Key: "id", Value: "999999"}}, "", ""},
+		{"game ID that isn't a number", gin.Params{{Key: "id", Value: "latest"}}, "", ""},
+		{"body without a username", nil, `{"scoring":"pin"}`, ""},
+		{"body that isn't JSON", nil, `username=alice`, ""},
+		{"body over the limit", nil, large, ""},
+		{"no body", nil, "", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/game/play", strings.NewReader(tt.body))
+			c.Params = tt.params
+
+			if got := api.RatePlayer(c); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			// The handler reads the whole body, as it was sent
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil || !bytes.Equal(body, []byte(tt.body)) {
+				t.Errorf("the handler reads %d bytes, %v; want the %d sent", len(body), err, len(tt.body))
+			}
+		})
+	}
+}
+
+func TestGameOwner(t *testing.T) {
+	database := apitest.Open(t)
+	users := createUsers(t, "alice")
+	gameID, err := api.Services().CreateGame("alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if owner, ok := api.GameOwner(gameID); !ok || owner != users["alice"].ID {
+		t.Errorf("got %d, %t; want alice's ID %d", owner, ok, users["alice"].ID)
+	}
+	if owner, ok := api.GameOwner(gameID + 1); ok {
+		t.Errorf("unknown game: got owner %d", owner)
+	}
+
+	// Owners are cached, so a game gone since is still matched to its player
+	if deleted, err := database.DeleteAbandonedGames(time.Now().Add(time.Minute)); err != nil || deleted != 1 {
+		t.Fatalf("deleted %d games, %v", deleted, err)
+	}
+	if owner, ok := api.GameOwner(gameID); !ok || owner != users["alice"].ID {
+		t.Errorf("after deleting the game: got %d, %t; want the cached owner", owner, ok)
+	}
+
+	// New services start with an empty cache
+	api.InitServices(database)
+	if owner, ok := api.GameOwner(gameID); ok {
+		t.Errorf("after InitServices: got owner %d of a deleted game", owner)
+	}
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// submissionRequest is the body accepted when a player proposes content.
+// Kind "destination" proposes a new destination; kind "clues" adds clues,
+// fun facts or trivia to the destination given by destination_id.
+type submissionRequest struct {
+	Username      string   `json:"username" binding:"required"`
+	Kind          string   `json:"kind" binding:"required"`
+	DestinationID *int     `json:"destination_id"`
+	City          string   `json:"city"`
+	Country       string   `json:"country"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+	Clues         []string `json:"clues"`
+	FunFact       []string `json:"fun_fact"`
+	Trivia        []string `json:"trivia"`
+}
+
+// CreateSubmission handles requests from players to propose a destination or clues
+func CreateSubmission(c *gin.Context) {
+	locales := requestLocales(c)
+
+	var request submissionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondInvalid(c, locales, "Invalid request")
+		return
+	}
+
+	submission, err := dataService.SubmitContent(request.Username, models.Submission{
+		Kind:          request.Kind,
+		DestinationID: request.DestinationID,
+		City:          request.City,
+		Country:       request.Country,
+		Latitude:      request.Latitude,
+		Longitude:     request.Longitude,
+		Clues:         request.Clues,
+		FunFact:       request.FunFact,
+		Trivia:        request.Trivia,
+	})
+	if err != nil {
+		respondError(c, locales, err, "Failed to save submission")
+		return
+	}
+
+	c.JSON(http.StatusCreated, submission)
+}
+
+// GetUserSubmissions handles requests from players to see their submissions and how they were reviewed
+func GetUserSubmissions(c *gin.Context) {
+	locales := requestLocales(c)
+
+	submissions, err := dataService.ListUserSubmissions(c.Param("username"))
+	if err != nil {
+		respondError(c, locales, err, "Failed to list submissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, submissions)
+}
+
+// AdminListSubmissions handles requests for the review queue. It lists pending
+// submissions unless another status, or "all", is asked for.
+func AdminListSubmissions(c *gin.Context) {
+	status := c.DefaultQuery("status", models.SubmissionPending)
+	switch status {
+	case models.SubmissionPending, models.SubmissionApproved, models.SubmissionRejected:
+	case "all":
+		status = ""
+	default:
+		respondInvalid(c, nil, "Status must be pending, approved, rejected or all")
+		return
+	}
+
+	submissions, err := dataService.ListSubmissions(status)
+	if err != nil {
+		respondError(c, nil, err, "Failed to list submissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, submissions)
+}
+
+// AdminGetSubmission handles requests to get a single submission
+func AdminGetSubmission(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid submission ID")
+		return
+	}
+
+	submission, err := dataService.GetSubmission(submissionID)
+	if err != nil {
+		respondError(c, nil, err, "Failed to get submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, submission)
+}
+
+// AdminApproveSubmission handles requests to approve a submission and merge it
+// into the destinations. Fields given in the request body replace the
+// submitted ones first, so a reviewer can fix a proposal while approving it.
+func AdminApproveSubmission(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid submission ID")
+		return
+	}
+
+	var request struct {
+		Note      string    `json:"note"`
+		City      *string   `json:"city"`
+		Country   *string   `json:"country"`
+		Latitude  *float64  `json:"latitude"`
+		Longitude *float64  `json:"longitude"`
+		Clues     *[]string `json:"clues"`
+		FunFact   *[]string `json:"fun_fact"`
+		Trivia    *[]string `json:"trivia"`
+	}
+	// The body is optional when approving as submitted
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondInvalid(c, nil, "Invalid request")
+			return
+		}
+	}
+
+	submission, err := dataService.GetSubmission(submissionID)
+	if err != nil {
+		respondError(c, nil, err, "Failed to get submission")
+		return
+	}
+
+	submission.ReviewNote = request.Note
+	if request.City != nil {
+		submission.City = *request.City
+	}
+	if request.Country != nil {
+		submission.Country = *request.Country
+	}
+	if request.Latitude != nil {
+		submission.Latitude = request.Latitude
+	}
+	if request.Longitude != nil {
+		submission.Longitude = request.Longitude
+	}
+	if request.Clues != nil {
+		submission.Clues = *request.Clues
+	}
+	if request.FunFact != nil {
+		submission.FunFact = *request.FunFact
+	}
+	if request.Trivia != nil {
+		submission.Trivia = *request.Trivia
+	}
+
+	destination, err := dataService.ApproveSubmission(adminActor(c), *submission)
+	if err != nil {
+		respondError(c, nil, err, "Failed to approve submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, destination)
+}
+
+// AdminRejectSubmission handles requests to reject a submission with an optional note for the submitter
+func AdminRejectSubmission(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, nil, "Invalid submission ID")
+		return
+	}
+
+	var request struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondInvalid(c, nil, "Invalid request")
+			return
+		}
+	}
+
+	submission, err := dataService.RejectSubmission(adminActor(c), submissionID, request.Note)
+	if err != nil {
+		respondError(c, nil, err, "Failed to reject submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, submission)
+}
@@ -0,0 +1,352 @@
+// Package backup writes timestamped snapshots of the SQLite database, keeps
+// the newest of them and restores them after checking their integrity.
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/db"
+)
+
+// Defaults used when the environment doesn't configure backups
+const (
+	DefaultDir  = "./data/backups"
+	DefaultKeep = 7
+)
+
+// Snapshot file names are the prefix, a UTC timestamp and an extension
+const (
+	filePrefix      = "globetrotter-"
+	timestampLayout = "20060102T150405Z"
+	extension       = ".db"
+	gzipExtension   = ".db.gz"
+)
+
+// Options configures where snapshots are written and how many are kept
+type Options struct {
+	Dir      string
+	Compress bool // Gzip snapshots
+	Keep     int  // Newest snapshots to keep; 0 keeps them all
+}
+
+// OptionsFromEnv reads BACKUP_DIR, BACKUP_KEEP and BACKUP_COMPRESS, using the
+// defaults for unset ones. Snapshots are compressed unless BACKUP_COMPRESS is false.
+func OptionsFromEnv() (Options, error) {
+	opts := Options{Dir: DefaultDir, Compress: true, Keep: DefaultKeep}
+
+	if dir := os.Getenv("BACKUP_DIR"); dir != "" {
+		opts.Dir = dir
+	}
+	if value := os.Getenv("BACKUP_KEEP"); value != "" {
+		keep, err := strconv.Atoi(value)
+		if err != nil || keep < 0 {
+			return opts, fmt.Errorf("invalid BACKUP_KEEP %q", value)
+		}
+		opts.Keep = keep
+	}
+	if value := os.Getenv("BACKUP_COMPRESS"); value != "" {
+		compress, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid BACKUP_COMPRESS %q", value)
+		}
+		opts.Compress = compress
+	}
+
+	return opts, nil
+}
+
+// Snapshot is a backup file
+type Snapshot struct {
+	Path       string
+	CreatedAt  time.Time
+	Size       int64
+	Compressed bool
+}
+
+// Create writes a snapshot of the SQLite database at dsn into opts.Dir and
+// then removes the oldest snapshots beyond opts.Keep. The snapshot only
+// appears under its final name once it is complete.
+func Create(ctx context.Context, dsn string, opts Options) (Snapshot, error) {
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to create backup directory: %v", err)
+	}
+
+	createdAt := time.Now().UTC()
+	name := filePrefix + createdAt.Format(timestampLayout) + extension
+	if opts.Compress {
+		name = filePrefix + createdAt.Format(timestampLayout) + gzipExtension
+	}
+	path := filepath.Join(opts.Dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return Snapshot{}, fmt.Errorf("%s already exists", path)
+	}
+
+	// Hidden temporary names are not listed, so an interrupted backup is
+	// never taken for a snapshot or counted by the retention
+	raw := filepath.Join(opts.Dir, "."+name+".tmp")
+	defer os.Remove(raw)
+	if err := db.Backup(ctx, dsn, raw); err != nil {
+		return Snapshot{}, err
+	}
+
+	finished := raw
+	if opts.Compress {
+		finished = filepath.Join(opts.Dir, "."+name+".gz.tmp")
+		defer os.Remove(finished)
+		if err := compress(raw, finished); err != nil {
+			return Snapshot{}, fmt.Errorf("failed to compress backup: %v", err)
+		}
+	}
+
+	if err := os.Rename(finished, path); err != nil {
+		return Snapshot{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot := Snapshot{Path: path, CreatedAt: createdAt, Size: info.Size(), Compressed: opts.Compress}
+
+	if _, err := Prune(opts.Dir, opts.Keep); err != nil {
+		return snapshot, fmt.Errorf("backup written, but removing old ones failed: %v", err)
+	}
+	return snapshot, nil
+}
+
+// List returns the snapshots in dir, newest first
+func List(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) {
+			continue
+		}
+
+		stamp := strings.TrimPrefix(name, filePrefix)
+		compressed := strings.HasSuffix(stamp, gzipExtension)
+		switch {
+		case compressed:
+			stamp = strings.TrimSuffix(stamp, gzipExtension)
+		case strings.HasSuffix(stamp, extension):
+			stamp = strings.TrimSuffix(stamp, extension)
+		default:
+			continue
+		}
+		createdAt, err := time.Parse(timestampLayout, stamp)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, Snapshot{
+			Path:       filepath.Join(dir, name),
+			CreatedAt:  createdAt,
+			Size:       info.Size(),
+			Compressed: compressed,
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+// Prune removes all but the newest keep snapshots in dir and returns the
+// removed ones. A keep of 0 removes nothing.
+func Prune(dir string, keep int) ([]Snapshot, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+
+	snapshots, err := List(dir)
+	if err != nil || len(snapshots) <= keep {
+		return nil, err
+	}
+
+	removed := snapshots[keep:]
+	for _, snapshot := range removed {
+		if err := os.Remove(snapshot.Path); err != nil {
+			return nil, err
+		}
+	}
+	return removed, nil
+}
+
+// Restore replaces the SQLite database at dbPath with the snapshot at path.
+// The snapshot is decompressed next to the database and must pass
+// PRAGMA integrity_check before it is moved into place; otherwise the database
+// is left untouched. The replaced database and its journal files are kept
+// with a .pre-restore-<timestamp> suffix, so earlier ones are never
+// overwritten, and the path the database was kept at is returned, or "" if
+// there was none. The server must not be running.
+func Restore(path, dbPath string) (string, error) {
+	if db.IsPostgres(dbPath) {
+		return "", db.ErrBackupUnsupported
+	}
+
+	dir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	candidate := filepath.Join(dir, "."+filepath.Base(dbPath)+".restore")
+	defer os.Remove(candidate)
+	if strings.HasSuffix(path, ".gz") {
+		if err := decompress(path, candidate); err != nil {
+			return "", fmt.Errorf("failed to decompress %s: %v", path, err)
+		}
+	} else if err := copyFile(path, candidate); err != nil {
+		return "", err
+	}
+
+	// SQLite takes an empty file for an empty database, which would pass
+	if info, err := os.Stat(candidate); err != nil {
+		return "", err
+	} else if info.Size() == 0 {
+		return "", fmt.Errorf("not restoring %s: snapshot is empty", path)
+	}
+	if err := db.CheckIntegrity(candidate); err != nil {
+		return "", fmt.Errorf("not restoring %s: %v", path, err)
+	}
+
+	// A journal left by the replaced database would be applied to the
+	// restored one, so it is moved aside with the database
+	suffix := ".pre-restore-" + time.Now().UTC().Format(timestampLayout)
+	var current []string
+	for _, journal := range []string{"", "-wal", "-shm", "-journal"} {
+		if _, err := os.Stat(dbPath + journal); err != nil {
+			continue
+		}
+		if _, err := os.Stat(dbPath + journal + suffix); err == nil {
+			return "", fmt.Errorf("not restoring %s: %s already exists", path, dbPath+journal+suffix)
+		}
+		current = append(current, dbPath+journal)
+	}
+	for _, file := range current {
+		if err := os.Rename(file, file+suffix); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.Rename(candidate, dbPath); err != nil {
+		return "", err
+	}
+	if len(current) == 0 || current[0] != dbPath {
+		return "", nil
+	}
+	return dbPath + suffix, nil
+}
+
+// Run writes a snapshot every interval until ctx is cancelled, logging failures
+func Run(ctx context.Context, dsn string, opts Options, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		snapshot, err := Create(ctx, dsn, opts)
+		if err != nil {
+			log.Printf("Scheduled backup failed: %v", err)
+			continue
+		}
+		log.Printf("Wrote backup %s (%d bytes)", snapshot.Path, snapshot.Size)
+	}
+}
+
+// compress gzips the file at source into a new file at dest
+func compress(source, dest string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// decompress gunzips the file at source into dest, replacing it
+func decompress(source, dest string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	zr, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	return writeFile(dest, zr)
+}
+
+// copyFile copies the file at source to dest, replacing it
+func copyFile(source, dest string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return writeFile(dest, in)
+}
+
+// writeFile writes r to dest and syncs it to disk
+func writeFile(dest string, r io.Reader) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, r); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	return out.Close()
+}
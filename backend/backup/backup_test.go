@@ -0,0 +1,292 @@
+package backup_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/backup"
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// openDatabase opens the database at path, seeding it from the repository's dataset when new
+func openDatabase(t *testing.T, path string) *db.Database {
+	t.Helper()
+	t.Setenv("DATASET_PATH", filepath.Join("..", "data", "data.json"))
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// saveUser adds a player to the database
+func saveUser(t *testing.T, database *db.Database, username string) {
+	t.Helper()
+	if err := database.SaveUser(models.ActorSystem, models.User{Username: username}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// hasUser reports whether the database at path has a player
+func hasUser(t *testing.T, path, username string) bool {
+	t.Helper()
+	database := openDatabase(t, path)
+	defer database.Close()
+	_, err := database.GetUserByUsername(username)
+	return err == nil
+}
+
+// snapshotName returns the file name Create gives a snapshot taken at createdAt
+func snapshotName(createdAt time.Time, compressed bool) string {
+	name := "globetrotter-" + createdAt.UTC().Format("20060102T150405Z") + ".db"
+	if compressed {
+		name += ".gz"
+	}
+	return name
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		name := "plain"
+		if compress {
+			name = "compressed"
+		}
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "globetrotter.db")
+			database := openDatabase(t, path)
+			saveUser(t, database, "before")
+
+			opts := backup.Options{Dir: filepath.Join(dir, "backups"), Compress: compress}
+			snapshot, err := backup.Create(context.Background(), path, opts)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if snapshot.Compressed != compress || snapshot.Size == 0 || filepath.Base(snapshot.Path) != snapshotName(snapshot.CreatedAt, compress) {
+				t.Errorf("Create returned %+v", snapshot)
+			}
+			listed, err := backup.List(opts.Dir)
+			if err != nil || len(listed) != 1 || listed[0].Path != snapshot.Path {
+				t.Errorf("List returned %+v, %v; want the snapshot", listed, err)
+			}
+
+			saveUser(t, database, "after")
+			database.Close()
+
+			previous, err := backup.Restore(snapshot.Path, path)
+			if err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+			if !strings.HasPrefix(previous, path+".pre-restore-") {
+				t.Errorf("Restore kept the previous database as %q", previous)
+			}
+			if !hasUser(t, path, "before") || hasUser(t, path, "after") {
+				t.Error("the restored database does not hold the snapshot's users")
+			}
+			if !hasUser(t, previous, "after") {
+				t.Error("the kept database lost the user added after the snapshot")
+			}
+		})
+	}
+}
+
+func TestRestoreRefusesBadSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source.db")
+	openDatabase(t, source).Close()
+	valid, err := os.ReadFile(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var zipped bytes.Buffer
+	zw := gzip.NewWriter(&zipped)
+	zw.Write(valid)
+	zw.Close()
+
+	for _, tt := range []struct {
+		name     string
+		file     string
+		snapshot []byte
+	}{
+		{"truncated", "truncated.db", valid[:len(valid)/2]},
+		{"truncated gzip", "truncated.db.gz", zipped.Bytes()[:zipped.Len()/2]},
+		{"not a database", "text.db", []byte("these are not the pages you are looking for, not by a long way")},
+		{"not gzipped", "text.db.gz", valid},
+		{"empty", "empty.db", []byte{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "globetrotter.db")
+			database := openDatabase(t, path)
+			saveUser(t, database, "current")
+			database.Close()
+			current, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(snapshot, tt.snapshot, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := backup.Restore(snapshot, path); err == nil {
+				t.Fatal("Restore succeeded")
+			}
+
+			after, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(after, current) {
+				t.Errorf("the current database was changed (%v)", err)
+			}
+			entries, _ := os.ReadDir(dir)
+			for _, entry := range entries {
+				if name := entry.Name(); name != "globetrotter.db" && name != tt.file {
+					t.Errorf("Restore left %s behind", name)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreKeepsEarlierDatabases(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "globetrotter.db")
+	database := openDatabase(t, path)
+	saveUser(t, database, "first")
+	snapshot := filepath.Join(dir, "snapshot.db")
+	if err := db.Backup(context.Background(), path, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	database.Close()
+
+	first, err := backup.Restore(snapshot, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Restores in different seconds keep the database each replaced
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	second, err := backup.Restore(snapshot, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("both restores kept the database as %s", first)
+	}
+	for _, kept := range []string{first, second} {
+		if _, err := os.Stat(kept); err != nil {
+			t.Errorf("kept database is gone: %v", err)
+		}
+	}
+
+	// A restore that would replace a kept database is refused
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		taken := path + ".pre-restore-" + now.Add(time.Duration(i)*time.Second).Format("20060102T150405Z")
+		if err := os.WriteFile(taken, []byte("kept"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := backup.Restore(snapshot, path); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Restore over a kept database: got %v, want it refused", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the current database was moved: %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name      string
+		snapshots int
+		keep      int
+		want      int
+	}{
+		{"more than kept", 5, 3, 3},
+		{"as many as kept", 3, 3, 3},
+		{"fewer than kept", 2, 3, 2},
+		{"keep all", 5, 0, 5},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i := 0; i < tt.snapshots; i++ {
+				name := snapshotName(start.Add(time.Duration(i)*time.Hour), i%2 == 0)
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("snapshot"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Other files are neither listed nor removed
+			others := []string{"notes.txt", "globetrotter-latest.db", ".globetrotter-20260101T000000Z.db.tmp"}
+			for _, name := range others {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			removed, err := backup.Prune(dir, tt.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(removed) != tt.snapshots-tt.want {
+				t.Errorf("removed %d snapshots, want %d", len(removed), tt.snapshots-tt.want)
+			}
+
+			left, err := backup.List(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(left) != tt.want {
+				t.Fatalf("%d snapshots are left, want %d", len(left), tt.want)
+			}
+			for i, snapshot := range left {
+				// Newest first
+				if want := start.Add(time.Duration(tt.snapshots-1-i) * time.Hour); !snapshot.CreatedAt.Equal(want) {
+					t.Errorf("snapshot %d was taken at %s, want %s", i, snapshot.CreatedAt, want)
+				}
+			}
+			for _, name := range others {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("%s was removed", name)
+				}
+			}
+		})
+	}
+}
+
+// TestCreateKeepsNewest checks that Create prunes to opts.Keep snapshots,
+// keeping the one it wrote
+func TestCreateKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "globetrotter.db")
+	openDatabase(t, path)
+
+	opts := backup.Options{Dir: filepath.Join(dir, "backups"), Keep: 2}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		name := snapshotName(time.Date(2026, 1, i, 0, 0, 0, 0, time.UTC), false)
+		if err := os.WriteFile(filepath.Join(opts.Dir, name), []byte("old"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshot, err := backup.Create(context.Background(), path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := backup.List(opts.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 2 || left[0].Path != snapshot.Path || filepath.Base(left[1].Path) != snapshotName(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC), false) {
+		t.Errorf("left %+v, want the new snapshot and the newest old one", left)
+	}
+}
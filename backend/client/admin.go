@@ -0,0 +1,315 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// Admin calls need WithAdminToken, and WithAdminUser to name the admin in
+// the audit log. They fail with ErrUnauthorized for a wrong token and
+// ErrForbidden when the server has no ADMIN_TOKEN.
+
+// destinationBody is the body of the calls that create or replace a destination
+type destinationBody struct {
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	Clues     []string `json:"clues,omitempty"`
+	FunFact   []string `json:"fun_fact,omitempty"`
+	Trivia    []string `json:"trivia,omitempty"`
+	Retired   bool     `json:"retired"`
+}
+
+// newDestinationBody returns the body for destination
+func newDestinationBody(destination models.Destination) destinationBody {
+	return destinationBody{
+		City:      destination.City,
+		Country:   destination.Country,
+		Latitude:  destination.Latitude,
+		Longitude: destination.Longitude,
+		Clues:     destination.Clues,
+		FunFact:   destination.FunFact,
+		Trivia:    destination.Trivia,
+		Retired:   destination.Retired,
+	}
+}
+
+// ListDestinations lists the destinations, with retired ones if includeRetired is set
+func (c *Client) ListDestinations(ctx context.Context, includeRetired bool) ([]models.Destination, error) {
+	query := url.Values{}
+	if includeRetired {
+		query.Set("include_retired", "true")
+	}
+
+	var destinations []models.Destination
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/admin/destinations", query: query, admin: true, idempotent: true}, &destinations)
+	if err != nil {
+		return nil, err
+	}
+	return destinations, nil
+}
+
+// GetDestination gets a destination by ID
+func (c *Client) GetDestination(ctx context.Context, id int) (*models.Destination, error) {
+	var destination models.Destination
+	err := c.do(ctx, call{method: http.MethodGet, path: pathf("/api/admin/destinations/%d", id), admin: true, idempotent: true}, &destination)
+	if err != nil {
+		return nil, err
+	}
+	return &destination, nil
+}
+
+// CreateDestination adds a destination and returns it with its ID
+func (c *Client) CreateDestination(ctx context.Context, destination models.Destination) (*models.Destination, error) {
+	var created models.Destination
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/admin/destinations", body: newDestinationBody(destination), admin: true}, &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateDestination replaces the destination with the ID of destination
+func (c *Client) UpdateDestination(ctx context.Context, destination models.Destination) (*models.Destination, error) {
+	var updated models.Destination
+	err := c.do(ctx, call{
+		method:     http.MethodPut,
+		path:       pathf("/api/admin/destinations/%d", destination.ID),
+		body:       newDestinationBody(destination),
+		admin:      true,
+		idempotent: true,
+	}, &updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteDestination deletes a destination, or retires it if games used it,
+// and reports whether it was retired
+func (c *Client) DeleteDestination(ctx context.Context, id int) (bool, error) {
+	var response struct {
+		Retired bool `json:"retired"`
+	}
+	err := c.do(ctx, call{method: http.MethodDelete, path: pathf("/api/admin/destinations/%d", id), admin: true, idempotent: true}, &response)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Retried && errors.Is(err, ErrNotFound) {
+		// An earlier attempt deleted it but its response was lost
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return response.Retired, nil
+}
+
+// ListClues lists the clues of a destination, with retired ones if includeRetired is set
+func (c *Client) ListClues(ctx context.Context, destinationID int, includeRetired bool) ([]models.Clue, error) {
+	query := url.Values{}
+	if includeRetired {
+		query.Set("include_retired", "true")
+	}
+
+	var clues []models.Clue
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       pathf("/api/admin/destinations/%d/clues", destinationID),
+		query:      query,
+		admin:      true,
+		idempotent: true,
+	}, &clues)
+	if err != nil {
+		return nil, err
+	}
+	return clues, nil
+}
+
+// ClueUpdate holds the changes to a clue; nil fields are left as they are
+type ClueUpdate struct {
+	Text    *string   `json:"text,omitempty"`
+	Tags    *[]string `json:"tags,omitempty"`
+	Retired *bool     `json:"retired,omitempty"`
+}
+
+// UpdateClue edits, tags, retires or restores a clue
+func (c *Client) UpdateClue(ctx context.Context, id int, update ClueUpdate) (*models.Clue, error) {
+	var clue models.Clue
+	err := c.do(ctx, call{method: http.MethodPatch, path: pathf("/api/admin/clues/%d", id), body: update, admin: true, idempotent: true}, &clue)
+	if err != nil {
+		return nil, err
+	}
+	return &clue, nil
+}
+
+// ListSubmissions lists the submissions with a status, one of the
+// models.Submission status constants, "" for pending ones or "all"
+func (c *Client) ListSubmissions(ctx context.Context, status string) ([]models.Submission, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+
+	var submissions []models.Submission
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/admin/submissions", query: query, admin: true, idempotent: true}, &submissions)
+	if err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// GetSubmission gets a submission by ID, with the destinations and
+// submissions it duplicates
+func (c *Client) GetSubmission(ctx context.Context, id int) (*models.Submission, error) {
+	var submission models.Submission
+	err := c.do(ctx, call{method: http.MethodGet, path: pathf("/api/admin/submissions/%d", id), admin: true, idempotent: true}, &submission)
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// Approval holds the review note of an approved submission and the fields
+// the reviewer fixed; nil fields are approved as submitted
+type Approval struct {
+	Note      string    `json:"note,omitempty"`
+	City      *string   `json:"city,omitempty"`
+	Country   *string   `json:"country,omitempty"`
+	Latitude  *float64  `json:"latitude,omitempty"`
+	Longitude *float64  `json:"longitude,omitempty"`
+	Clues     *[]string `json:"clues,omitempty"`
+	FunFact   *[]string `json:"fun_fact,omitempty"`
+	Trivia    *[]string `json:"trivia,omitempty"`
+}
+
+// ApproveSubmission approves a pending submission and returns the
+// destination it created or added to. A reviewed submission fails with the
+// code "submission_reviewed".
+func (c *Client) ApproveSubmission(ctx context.Context, id int, approval Approval) (*models.Destination, error) {
+	var destination models.Destination
+	err := c.do(ctx, call{method: http.MethodPost, path: pathf("/api/admin/submissions/%d/approve", id), body: approval, admin: true}, &destination)
+	if err != nil {
+		return nil, err
+	}
+	return &destination, nil
+}
+
+// RejectSubmission rejects a pending submission with a review note
+func (c *Client) RejectSubmission(ctx context.Context, id int, note string) (*models.Submission, error) {
+	body := struct {
+		Note string `json:"note,omitempty"`
+	}{note}
+
+	var submission models.Submission
+	err := c.do(ctx, call{method: http.MethodPost, path: pathf("/api/admin/submissions/%d/reject", id), body: body, admin: true}, &submission)
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// DifficultyReport gets the destinations and clues whose answers look off,
+// flagging items with at least minSamples answers, or the server's default
+// if minSamples is 0
+func (c *Client) DifficultyReport(ctx context.Context, minSamples int) (*models.DifficultyReport, error) {
+	query := url.Values{}
+	if minSamples > 0 {
+		query.Set("min_samples", strconv.Itoa(minSamples))
+	}
+
+	var report models.DifficultyReport
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/admin/analytics/difficulty", query: query, admin: true, idempotent: true}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// DestinationDifficulty lists the statistics of every answered destination
+func (c *Client) DestinationDifficulty(ctx context.Context) ([]models.DifficultyStats, error) {
+	var stats []models.DifficultyStats
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/admin/analytics/destinations", admin: true, idempotent: true}, &stats)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ClueDifficulty lists the statistics of every answered clue
+func (c *Client) ClueDifficulty(ctx context.Context) ([]models.DifficultyStats, error) {
+	var stats []models.DifficultyStats
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/admin/analytics/clues", admin: true, idempotent: true}, &stats)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// RefreshDifficulty aggregates the statistics now instead of waiting for the
+// server's background job
+func (c *Client) RefreshDifficulty(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/admin/analytics/refresh", admin: true, idempotent: true}, nil)
+}
+
+// ReloadDataset reloads the destinations from the server's dataset file,
+// removing stored destinations the file no longer has if prune is set. An
+// invalid dataset fails with the code "invalid_dataset".
+func (c *Client) ReloadDataset(ctx context.Context, prune bool) (*models.DatasetReload, error) {
+	query := url.Values{}
+	if prune {
+		query.Set("prune", "true")
+	}
+
+	var report models.DatasetReload
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/admin/dataset/reload", query: query, admin: true, idempotent: true}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// AuditLog lists the audit log entries matching filter, newest first
+func (c *Client) AuditLog(ctx context.Context, filter storage.AuditFilter) ([]models.AuditEntry, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"actor":       filter.Actor,
+		"action":      filter.Action,
+		"target_type": filter.TargetType,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	for name, value := range map[string]int{
+		"target_id": filter.TargetID,
+		"before":    filter.BeforeID,
+		"limit":     filter.Limit,
+	} {
+		if value > 0 {
+			query.Set(name, strconv.Itoa(value))
+		}
+	}
+	for name, value := range map[string]time.Time{
+		"since": filter.Since,
+		"until": filter.Until,
+	} {
+		if !value.IsZero() {
+			query.Set(name, value.Format(time.RFC3339))
+		}
+	}
+
+	var entries []models.AuditEntry
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/admin/audit", query: query, admin: true, idempotent: true}, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
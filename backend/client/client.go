@@ -0,0 +1,223 @@
+// Package client is a Go client for the Globetrotter REST API. Its methods
+// take and return the types of package models, report error responses as
+// *Error and retry idempotent calls when the server is briefly unavailable.
+//
+//	c := client.New("http://localhost:8080", client.WithLanguage("es"))
+//	gameID, err := c.StartGame(ctx, "alice", models.ScoringStandard)
+//	question, err := c.NextQuestion(ctx, gameID)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Defaults of a new client
+const (
+	DefaultTimeout = 30 * time.Second
+	DefaultRetries = 2
+	DefaultBackoff = 200 * time.Millisecond
+)
+
+// Client calls a Globetrotter server. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	adminToken string
+	adminUser  string
+	language   string
+	retries    int
+	backoff    time.Duration
+}
+
+// Option configures a client
+type Option func(*Client)
+
+// WithHTTPClient sends requests with httpClient instead of a client with DefaultTimeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAdminToken sets the bearer token sent with admin API calls, the
+// server's ADMIN_TOKEN
+func WithAdminToken(token string) Option {
+	return func(c *Client) {
+		c.adminToken = token
+	}
+}
+
+// WithAdminUser names the admin in the audit log of the changes the client makes
+func WithAdminUser(name string) Option {
+	return func(c *Client) {
+		c.adminUser = name
+	}
+}
+
+// WithLanguage sets the Accept-Language header, such as "es" or "pt-BR, en",
+// used for questions, answers and error messages of players without a
+// preferred language
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+// WithRetries sets how many times an idempotent call is retried after a
+// network error or a 502, 503 or 504 response, waiting backoff before the
+// first retry and twice as long before each next one
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// New creates a client for the server at baseURL, such as "http://localhost:8080"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		retries:    DefaultRetries,
+		backoff:    DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// call is one API call
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{} // Sent as JSON unless nil
+	admin  bool        // Send the admin token and user
+	// idempotent calls can be retried: repeating them has the same effect as
+	// sending them once
+	idempotent bool
+}
+
+// do sends the call, retrying it if it is idempotent, and decodes the
+// response body into out unless out is nil. Error responses are returned
+// as *Error.
+func (c *Client) do(ctx context.Context, cl call, out interface{}) error {
+	var body []byte
+	if cl.body != nil {
+		var err error
+		if body, err = json.Marshal(cl.body); err != nil {
+			return fmt.Errorf("failed to encode the request: %w", err)
+		}
+	}
+
+	attempts := 1
+	if cl.idempotent && c.retries > 0 {
+		attempts += c.retries
+	}
+
+	var err error
+	backoff := c.backoff
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		retry, err = c.send(ctx, cl, body, out)
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			apiErr.Retried = attempt > 0
+		}
+		if !retry {
+			break
+		}
+	}
+	return err
+}
+
+// send sends the call once and reports whether it may succeed if retried
+func (c *Client) send(ctx context.Context, cl call, body []byte, out interface{}) (bool, error) {
+	target := c.baseURL + cl.path
+	if len(cl.query) > 0 {
+		target += "?" + cl.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, target, reader)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+	if cl.admin {
+		if c.adminToken != "" {
+			req.Header.Set("Authorization", "Bearer "+c.adminToken)
+		}
+		if c.adminUser != "" {
+			req.Header.Set("X-Admin-User", c.adminUser)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The context ending is final; anything else may be a dropped connection
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := decodeError(resp)
+		return retryable(resp.StatusCode), apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode the response of %s %s: %w", cl.method, cl.path, err)
+	}
+	return false, nil
+}
+
+// retryable reports whether a response with status may succeed if the call is repeated
+func retryable(status int) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// pathf formats a path, escaping string arguments as path segments
+func pathf(format string, args ...interface{}) string {
+	for i, arg := range args {
+		if s, ok := arg.(string); ok {
+			args[i] = url.PathEscape(s)
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,701 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubhsherl/globetrotter/backend/api"
+	"github.com/shubhsherl/globetrotter/backend/api/apitest"
+	"github.com/shubhsherl/globetrotter/backend/client"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/openapi"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// adminUser names the admin in the audit log
+const adminUser = "clienttest"
+
+// TestMain seeds the databases the tests serve the API from with
+// data/data.json. Pass -v to see the server log.
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.ReleaseMode)
+	os.Exit(apitest.Main(m, filepath.Join("..", "data", "data.json")))
+}
+
+// server serves the routes of api.SetupRoutes from a database of the test's
+// own, behind a front that can fail calls. Every request the clients send
+// must match the OpenAPI document.
+type server struct {
+	url    string
+	front  *front
+	player *client.Client
+	admin  *client.Client // With the admin token
+}
+
+// newServer starts a server for the rest of the test
+func newServer(t *testing.T) *server {
+	t.Helper()
+	apitest.Open(t)
+
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := openapi.NewValidator(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator.ReportOnly = true
+	validator.OnMismatch = func(m openapi.Mismatch) {
+		t.Errorf("%s", m)
+	}
+	api.SetSpecValidator(validator)
+
+	router := gin.New()
+	api.SetupRoutes(router)
+	front := &front{next: router}
+	httpServer := httptest.NewServer(front)
+	t.Cleanup(httpServer.Close)
+
+	return &server{
+		url:    httpServer.URL,
+		front:  front,
+		player: client.New(httpServer.URL, client.WithRetries(2, time.Millisecond)),
+		admin: client.New(httpServer.URL,
+			client.WithAdminToken(apitest.AdminToken),
+			client.WithAdminUser(adminUser),
+			client.WithRetries(2, time.Millisecond),
+		),
+	}
+}
+
+// withUser creates the player alice
+func withUser(t *testing.T) {
+	t.Helper()
+	if _, err := api.Services().CreateUser("alice", ""); err != nil {
+		t.Fatalf("creating alice: %v", err)
+	}
+}
+
+// withSubmission has alice propose a destination and returns its ID
+func withSubmission(t *testing.T) int {
+	t.Helper()
+	submission, err := api.Services().SubmitContent("alice", models.Submission{
+		Kind:    models.SubmissionDestination,
+		City:    "Clienttest City",
+		Country: "Clienttestistan",
+		Clues:   []string{"Home of the client tests", "Found on no map"},
+		FunFact: []string{"It only exists while the tests run"},
+		Trivia:  []string{"Its name was picked by a test"},
+	})
+	if err != nil {
+		t.Fatalf("submitting: %v", err)
+	}
+	return submission.ID
+}
+
+// lowestOption returns the lowest option of a question, so tests don't
+// depend on map order
+func lowestOption(question *models.NextQuestionResponse) int {
+	selected := 0
+	for id := range question.OptionsDisplay {
+		if selected == 0 || id < selected {
+			selected = id
+		}
+	}
+	return selected
+}
+
+// check reports a failure if err is not nil and reports whether it was nil
+func check(t *testing.T, what string, err error) bool {
+	t.Helper()
+	if err != nil {
+		t.Errorf("%s: %v", what, err)
+		return false
+	}
+	return true
+}
+
+// expectError checks that err is an *Error of kind with code and a request ID
+func expectError(t *testing.T, what string, err error, kind error, code string) *client.Error {
+	t.Helper()
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		t.Errorf("%s: got %v, want a %q error", what, err, code)
+		return nil
+	}
+	if !errors.Is(err, kind) {
+		t.Errorf("%s: error with status %d is not %q", what, apiErr.StatusCode, kind)
+	}
+	if apiErr.Code != code || client.Code(err) != code {
+		t.Errorf("%s: got error code %q, want %q", what, apiErr.Code, code)
+	}
+	if !errors.Is(err, &client.Error{Code: code}) {
+		t.Errorf("%s: error does not match its code %q", what, code)
+	}
+	if apiErr.RequestID == "" {
+		t.Errorf("%s: error has no request ID", what)
+	}
+	if apiErr.Message == "" {
+		t.Errorf("%s: error has no message", what)
+	}
+	return apiErr
+}
+
+// front sits in front of the API and fails the next calls as a proxy or an
+// overloaded server would
+type front struct {
+	next http.Handler
+
+	mu      sync.Mutex
+	fail    int  // Number of next calls to fail
+	status  int  // Status of the failed calls
+	deliver bool // Serve failed calls before failing them, as if the response was lost
+	hits    int  // Calls since the last failNext
+}
+
+// failNext fails the next n calls with status, serving them first if deliver is set
+func (f *front) failNext(n, status int, deliver bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fail, f.status, f.deliver, f.hits = n, status, deliver, 0
+}
+
+// sinceFail returns the number of calls received since the last failNext
+func (f *front) sinceFail() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hits
+}
+
+// ServeHTTP implements http.Handler
+func (f *front) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.hits++
+	failing := f.fail > 0
+	if failing {
+		f.fail--
+	}
+	status, deliver := f.status, f.deliver
+	f.mu.Unlock()
+
+	if !failing {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+	if deliver {
+		f.next.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	http.Error(w, "upstream unavailable", status)
+}
+
+func TestHealth(t *testing.T) {
+	s := newServer(t)
+	health, err := s.player.Health(context.Background())
+	if check(t, "Health", err) && health.Status != "ok" {
+		t.Errorf("Health: got status %q, want ok", health.Status)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	user, err := s.player.CreateUser(ctx, "alice", "")
+	if check(t, "CreateUser", err) && user.Username != "alice" {
+		t.Errorf("CreateUser: got %+v", user)
+	}
+
+	user, err = s.player.GetUser(ctx, "alice")
+	if check(t, "GetUser", err) && user.Username != "alice" {
+		t.Errorf("GetUser: got %+v", user)
+	}
+
+	user, err = s.player.SetUserLocale(ctx, "alice", "es")
+	if check(t, "SetUserLocale", err) && user.Locale != "es" {
+		t.Errorf("SetUserLocale: got locale %q, want es", user.Locale)
+	}
+	user, err = s.player.SetUserLocale(ctx, "alice", "")
+	if check(t, "SetUserLocale", err) && user.Locale != "" {
+		t.Errorf("SetUserLocale: got locale %q, want it cleared", user.Locale)
+	}
+}
+
+func TestRandomDestination(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	destination, err := s.player.RandomDestination(ctx, "", "")
+	if !check(t, "RandomDestination", err) {
+		return
+	}
+	if destination.City == "" || len(destination.Clues) == 0 {
+		t.Errorf("RandomDestination: got %+v", destination)
+	}
+	found, err := s.player.RandomDestination(ctx, destination.Country, "")
+	if check(t, "RandomDestination by country", err) && found.Country != destination.Country {
+		t.Errorf("RandomDestination by country: got %q, want %q", found.Country, destination.Country)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	s := newServer(t)
+	withUser(t)
+	ctx := context.Background()
+
+	submission, err := s.player.Submit(ctx, models.Submission{
+		Username: "alice",
+		Kind:     models.SubmissionDestination,
+		City:     "Clienttest City",
+		Country:  "Clienttestistan",
+		Clues:    []string{"Home of the client tests", "Found on no map"},
+		FunFact:  []string{"It only exists while the tests run"},
+		Trivia:   []string{"Its name was picked by a test"},
+	})
+	if check(t, "Submit", err) && (submission.ID == 0 || submission.Status != models.SubmissionPending) {
+		t.Errorf("Submit: got %+v", submission)
+	}
+
+	submissions, err := s.player.UserSubmissions(ctx, "alice")
+	if check(t, "UserSubmissions", err) && len(submissions) != 1 {
+		t.Errorf("UserSubmissions: got %d submissions, want 1", len(submissions))
+	}
+}
+
+// TestGame plays a game to the end through the client
+func TestGame(t *testing.T) {
+	s := newServer(t)
+	withUser(t)
+	ctx := context.Background()
+
+	gameID, err := s.player.StartGame(ctx, "alice", models.ScoringStandard)
+	if !check(t, "StartGame", err) {
+		return
+	}
+
+	answered, correct := 0, 0
+	var first *models.NextQuestionResponse
+	for {
+		question, err := s.player.NextQuestion(ctx, gameID)
+		if client.Code(err) == "game_finished" {
+			break
+		}
+		if !check(t, "NextQuestion", err) {
+			return
+		}
+		if question.GameID != gameID || len(question.OptionsDisplay) == 0 || question.Question == "" {
+			t.Fatalf("NextQuestion: got %+v", question)
+		}
+		if first == nil {
+			first = question
+		}
+
+		selected := lowestOption(question)
+		result, err := s.player.SubmitAnswer(ctx, models.SubmitAnswerRequest{
+			GameID:              gameID,
+			QuestionID:          question.QuestionID,
+			SelectedDestination: selected,
+		})
+		if !check(t, "SubmitAnswer", err) {
+			return
+		}
+		if result.Correct != (result.CorrectOptionID == selected) {
+			t.Errorf("SubmitAnswer: correct is %t for option %d, the answer is %d", result.Correct, selected, result.CorrectOptionID)
+		}
+		if result.Correct {
+			correct++
+		}
+		answered++
+	}
+	if answered == 0 {
+		t.Fatal("NextQuestion: the game has no questions")
+	}
+
+	// Answering again reports the recorded answer
+	_, err = s.player.SubmitAnswer(ctx, models.SubmitAnswerRequest{
+		GameID:              gameID,
+		QuestionID:          first.QuestionID,
+		SelectedDestination: 1,
+	})
+	if apiErr := expectError(t, "SubmitAnswer again", err, client.ErrConflict, client.CodeAlreadyAnswered); apiErr != nil {
+		if apiErr.Answer == nil {
+			t.Error("SubmitAnswer again: error has no recorded answer")
+		}
+		if apiErr.Retried {
+			t.Error("SubmitAnswer again: call was retried")
+		}
+	}
+
+	result, err := s.player.GameResult(ctx, gameID)
+	if check(t, "GameResult", err) {
+		if result.GameID != gameID || result.TotalQuestions != answered || result.TotalCorrect != correct {
+			t.Errorf("GameResult: got %d of %d correct, want %d of %d", result.TotalCorrect, result.TotalQuestions, correct, answered)
+		}
+		if len(result.Questions) != answered {
+			t.Errorf("GameResult: got %d questions, want %d", len(result.Questions), answered)
+		}
+	}
+
+	summary, err := s.player.GameSummary(ctx, gameID)
+	if check(t, "GameSummary", err) && (summary.Username != "alice" || summary.TotalAnswered != answered) {
+		t.Errorf("GameSummary: got %+v", summary)
+	}
+}
+
+// TestErrors checks how error responses are reported
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		withUser bool
+		call     func(ctx context.Context, s *server) error
+		kind     error
+		code     string
+	}{
+		{"CreateUser taken", true, func(ctx context.Context, s *server) error {
+			_, err := s.player.CreateUser(ctx, "alice", "")
+			return err
+		}, client.ErrConflict, "username_taken"},
+		{"CreateUser with invalid locale", false, func(ctx context.Context, s *server) error {
+			_, err := s.player.CreateUser(ctx, "bob", "xx-invalid-locale")
+			return err
+		}, client.ErrBadRequest, "invalid_locale"},
+		{"GetUser missing", false, func(ctx context.Context, s *server) error {
+			_, err := s.player.GetUser(ctx, "nobody")
+			return err
+		}, client.ErrNotFound, "user_not_found"},
+		{"NextQuestion of missing game", false, func(ctx context.Context, s *server) error {
+			_, err := s.player.NextQuestion(ctx, 999999)
+			return err
+		}, client.ErrNotFound, "game_not_found"},
+		{"GameResult of missing game", false, func(ctx context.Context, s *server) error {
+			_, err := s.player.GameResult(ctx, 999999)
+			return err
+		}, client.ErrNotFound, "game_not_found"},
+		{"ListDestinations without token", false, func(ctx context.Context, s *server) error {
+			_, err := s.player.ListDestinations(ctx, false)
+			return err
+		}, client.ErrUnauthorized, client.CodeUnauthorized},
+		{"ListDestinations with wrong token", false, func(ctx context.Context, s *server) error {
+			_, err := client.New(s.url, client.WithAdminToken("wrong")).ListDestinations(ctx, false)
+			return err
+		}, client.ErrUnauthorized, client.CodeUnauthorized},
+		{"GetSubmission missing", false, func(ctx context.Context, s *server) error {
+			_, err := s.admin.GetSubmission(ctx, 999999)
+			return err
+		}, client.ErrNotFound, "submission_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t)
+			if tt.withUser {
+				withUser(t)
+			}
+			expectError(t, tt.name, tt.call(context.Background(), s), tt.kind, tt.code)
+		})
+	}
+}
+
+// TestErrorLanguage checks that messages of player calls follow the
+// client's language
+func TestErrorLanguage(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	_, err := s.player.GetUser(ctx, "nobody")
+	english := expectError(t, "GetUser missing", err, client.ErrNotFound, "user_not_found")
+	_, err = client.New(s.url, client.WithLanguage("fr")).GetUser(ctx, "nobody")
+	french := expectError(t, "GetUser missing in French", err, client.ErrNotFound, "user_not_found")
+	if english != nil && french != nil && french.Message == english.Message {
+		t.Errorf("GetUser missing in French: message %q is not translated", french.Message)
+	}
+}
+
+func TestAdminDestinations(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	destinations, err := s.admin.ListDestinations(ctx, true)
+	if check(t, "ListDestinations", err) && len(destinations) == 0 {
+		t.Error("ListDestinations: got no destinations")
+	}
+
+	latitude, longitude := 12.5, -45.25
+	created, err := s.admin.CreateDestination(ctx, models.Destination{
+		City:      "Checkville",
+		Country:   "Clientland",
+		Latitude:  &latitude,
+		Longitude: &longitude,
+		Clues:     []string{"Tested by every change", "Runs in a temporary directory", "Named after a package"},
+		FunFact:   []string{"Its database lives for under a minute"},
+		Trivia:    []string{"It is named after a test"},
+	})
+	if !check(t, "CreateDestination", err) {
+		return
+	}
+	if created.ID == 0 || created.City != "Checkville" {
+		t.Errorf("CreateDestination: got %+v", created)
+	}
+
+	_, err = s.admin.CreateDestination(ctx, *created)
+	expectError(t, "CreateDestination duplicate", err, client.ErrConflict, "duplicate_destination")
+
+	_, err = s.admin.CreateDestination(ctx, models.Destination{City: "Nowhere"})
+	if apiErr := expectError(t, "CreateDestination invalid", err, client.ErrBadRequest, "invalid_destination"); apiErr != nil && len(apiErr.Problems) == 0 {
+		t.Error("CreateDestination invalid: error has no problems")
+	}
+
+	created.Trivia = append(created.Trivia, "It was updated once")
+	updated, err := s.admin.UpdateDestination(ctx, *created)
+	if check(t, "UpdateDestination", err) && len(updated.Trivia) != 2 {
+		t.Errorf("UpdateDestination: got %d trivia, want 2", len(updated.Trivia))
+	}
+
+	got, err := s.admin.GetDestination(ctx, created.ID)
+	if check(t, "GetDestination", err) && got.Country != "Clientland" {
+		t.Errorf("GetDestination: got %+v", got)
+	}
+
+	clues, err := s.admin.ListClues(ctx, created.ID, false)
+	if check(t, "ListClues", err) && len(clues) != 3 {
+		t.Errorf("ListClues: got %d clues, want 3", len(clues))
+	}
+	if len(clues) > 0 {
+		retired, tags := true, []string{"checked"}
+		clue, err := s.admin.UpdateClue(ctx, clues[0].ID, client.ClueUpdate{Tags: &tags, Retired: &retired})
+		if check(t, "UpdateClue", err) && (!clue.Retired || len(clue.Tags) != 1) {
+			t.Errorf("UpdateClue: got %+v", clue)
+		}
+	}
+
+	// Checkville is in no game, so it is deleted rather than retired
+	retired, err := s.admin.DeleteDestination(ctx, created.ID)
+	if check(t, "DeleteDestination", err) && retired {
+		t.Error("DeleteDestination: unused destination was retired")
+	}
+	_, err = s.admin.GetDestination(ctx, created.ID)
+	expectError(t, "GetDestination deleted", err, client.ErrNotFound, "destination_not_found")
+}
+
+func TestAdminSubmissions(t *testing.T) {
+	s := newServer(t)
+	withUser(t)
+	id := withSubmission(t)
+	ctx := context.Background()
+
+	submissions, err := s.admin.ListSubmissions(ctx, "")
+	if check(t, "ListSubmissions", err) && (len(submissions) != 1 || submissions[0].ID != id) {
+		t.Errorf("ListSubmissions: got %+v, want submission %d pending", submissions, id)
+	}
+
+	submission, err := s.admin.GetSubmission(ctx, id)
+	if !check(t, "GetSubmission", err) {
+		return
+	}
+
+	city := "Approved City"
+	approved, err := s.admin.ApproveSubmission(ctx, submission.ID, client.Approval{Note: "Thanks", City: &city})
+	if check(t, "ApproveSubmission", err) && approved.City != city {
+		t.Errorf("ApproveSubmission: got city %q, want %q", approved.City, city)
+	}
+
+	_, err = s.admin.RejectSubmission(ctx, submission.ID, "Too late")
+	expectError(t, "RejectSubmission reviewed", err, client.ErrConflict, "submission_reviewed")
+}
+
+func TestAnalytics(t *testing.T) {
+	s := newServer(t)
+	withUser(t)
+	ctx := context.Background()
+
+	// One answered question gives the statistics a sample
+	data := api.Services()
+	gameID, err := data.CreateGame("alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	question, err := data.NextQuestion(gameID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := data.SubmitAnswer(gameID, question.QuestionID, lowestOption(question), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	check(t, "RefreshDifficulty", s.admin.RefreshDifficulty(ctx))
+	report, err := s.admin.DifficultyReport(ctx, 1)
+	if check(t, "DifficultyReport", err) && report.MinSamples != 1 {
+		t.Errorf("DifficultyReport: got min samples %d, want 1", report.MinSamples)
+	}
+	stats, err := s.admin.DestinationDifficulty(ctx)
+	if check(t, "DestinationDifficulty", err) && len(stats) == 0 {
+		t.Error("DestinationDifficulty: got no statistics after an answer")
+	}
+	stats, err = s.admin.ClueDifficulty(ctx)
+	if check(t, "ClueDifficulty", err) && len(stats) == 0 {
+		t.Error("ClueDifficulty: got no statistics after an answer")
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	created, err := s.admin.CreateDestination(ctx, models.Destination{
+		City:    "Auditville",
+		Country: "Clientland",
+		Clues:   []string{"Every change here is written down", "Its archive never shrinks"},
+		FunFact: []string{"It exists to be logged"},
+		Trivia:  []string{"It is deleted right away"},
+	})
+	if !check(t, "CreateDestination", err) {
+		return
+	}
+	_, err = s.admin.DeleteDestination(ctx, created.ID)
+	check(t, "DeleteDestination", err)
+
+	actor := models.AdminActor(adminUser)
+	entries, err := s.admin.AuditLog(ctx, storage.AuditFilter{Actor: actor, TargetType: models.AuditTargetDestination, Limit: 50})
+	if check(t, "AuditLog", err) {
+		if len(entries) != 2 {
+			t.Errorf("AuditLog: got %d destination entries by %s, want the create and delete", len(entries), actor)
+		}
+		for _, entry := range entries {
+			if entry.Actor != actor || entry.TargetType != models.AuditTargetDestination {
+				t.Errorf("AuditLog: entry %d does not match the filter: %+v", entry.ID, entry)
+			}
+		}
+	}
+
+	entries, err = s.admin.AuditLog(ctx, storage.AuditFilter{Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)})
+	if check(t, "AuditLog by time", err) && len(entries) < 2 {
+		t.Errorf("AuditLog by time: got %d entries, want at least 2", len(entries))
+	}
+}
+
+// TestRetries checks that idempotent calls are retried, and only those
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s *server)
+	}{
+		{"idempotent call succeeds once the server is back", func(t *testing.T, s *server) {
+			s.front.failNext(2, http.StatusServiceUnavailable, false)
+			_, err := s.player.GetUser(context.Background(), "alice")
+			check(t, "GetUser after two 503s", err)
+			if hits := s.front.sinceFail(); hits != 3 {
+				t.Errorf("GetUser after two 503s: got %d calls, want 3", hits)
+			}
+		}},
+		{"idempotent call fails with the proxy's status when the retries run out", func(t *testing.T, s *server) {
+			s.front.failNext(3, http.StatusBadGateway, false)
+			_, err := s.player.GetUser(context.Background(), "alice")
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) || !errors.Is(err, client.ErrServer) || apiErr.StatusCode != http.StatusBadGateway || !apiErr.Retried {
+				t.Errorf("GetUser after three 502s: got %v, want a retried 502 error", err)
+			}
+		}},
+		{"other calls are sent once", func(t *testing.T, s *server) {
+			s.front.failNext(1, http.StatusServiceUnavailable, false)
+			_, err := s.player.StartGame(context.Background(), "alice", "")
+			if !errors.Is(err, client.ErrServer) {
+				t.Errorf("StartGame after a 503: got %v, want a server error", err)
+			}
+			if hits := s.front.sinceFail(); hits != 1 {
+				t.Errorf("StartGame after a 503: got %d calls, want 1", hits)
+			}
+		}},
+		{"errors other than unavailability are not retried", func(t *testing.T, s *server) {
+			s.front.failNext(0, 0, false)
+			_, err := s.player.GetUser(context.Background(), "nobody")
+			expectError(t, "GetUser missing", err, client.ErrNotFound, "user_not_found")
+			if hits := s.front.sinceFail(); hits != 1 {
+				t.Errorf("GetUser missing: got %d calls, want 1", hits)
+			}
+		}},
+		{"answer whose response was lost is recorded once", func(t *testing.T, s *server) {
+			ctx := context.Background()
+			gameID, err := s.player.StartGame(ctx, "alice", "")
+			if !check(t, "StartGame", err) {
+				return
+			}
+			question, err := s.player.NextQuestion(ctx, gameID)
+			if !check(t, "NextQuestion", err) {
+				return
+			}
+
+			// The retry returns the recorded outcome
+			s.front.failNext(1, http.StatusBadGateway, true)
+			result, err := s.player.SubmitAnswer(ctx, models.SubmitAnswerRequest{
+				GameID:              gameID,
+				QuestionID:          question.QuestionID,
+				SelectedDestination: lowestOption(question),
+			})
+			if check(t, "SubmitAnswer with a lost response", err) && result.CorrectOptionID == 0 {
+				t.Errorf("SubmitAnswer with a lost response: got %+v", result)
+			}
+			if hits := s.front.sinceFail(); hits != 2 {
+				t.Errorf("SubmitAnswer with a lost response: got %d calls, want 2", hits)
+			}
+
+			gameResult, err := s.player.GameResult(ctx, gameID)
+			if check(t, "GameResult", err) {
+				answered := 0
+				for _, q := range gameResult.Questions {
+					answered += q.IsAnswered
+				}
+				if answered != 1 {
+					t.Errorf("GameResult after a lost response: got %d answered questions, want 1", answered)
+				}
+			}
+		}},
+		{"waiting to retry ends with the context", func(t *testing.T, s *server) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			slow := client.New(s.url, client.WithRetries(1, time.Minute))
+			s.front.failNext(1, http.StatusServiceUnavailable, false)
+			time.AfterFunc(10*time.Millisecond, cancel)
+			if _, err := slow.GetUser(ctx, "alice"); !errors.Is(err, context.Canceled) {
+				t.Errorf("GetUser canceled while waiting to retry: got %v, want context.Canceled", err)
+			}
+		}},
+		{"rate limited calls report when to try again", func(t *testing.T, s *server) {
+			ctx := context.Background()
+			for i := 0; i < apitest.GamesPerHour; i++ {
+				_, err := s.player.StartGame(ctx, "alice", "")
+				check(t, "StartGame", err)
+			}
+			s.front.failNext(0, 0, false)
+			_, err := s.player.StartGame(ctx, "alice", "")
+			if apiErr := expectError(t, "StartGame over the limit", err, client.ErrRateLimited, client.CodeRateLimited); apiErr != nil && apiErr.RetryAfter <= 0 {
+				t.Error("StartGame over the limit: error has no retry delay")
+			}
+			if hits := s.front.sinceFail(); hits != 1 {
+				t.Errorf("StartGame over the limit: got %d calls, want 1", hits)
+			}
+		}},
+		{"network errors are returned as they are", func(t *testing.T, s *server) {
+			closed := httptest.NewServer(http.NotFoundHandler())
+			closed.Close()
+			_, err := client.New(closed.URL, client.WithRetries(1, time.Millisecond)).Health(context.Background())
+			var apiErr *client.Error
+			if err == nil || errors.As(err, &apiErr) {
+				t.Errorf("Health of a closed server: got %v, want a network error", err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t)
+			withUser(t)
+			tt.run(t, s)
+		})
+	}
+}
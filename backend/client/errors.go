@@ -0,0 +1,146 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// Kinds of error responses, matched with errors.Is by their HTTP status
+var (
+	ErrBadRequest   = errors.New("bad request")  // 400 and 422
+	ErrUnauthorized = errors.New("unauthorized") // 401
+	ErrForbidden    = errors.New("forbidden")    // 403
+	ErrNotFound     = errors.New("not found")    // 404
+	ErrConflict     = errors.New("conflict")     // 409
+	ErrRateLimited  = errors.New("rate limited") // 429
+	ErrServer       = errors.New("server error") // 5xx
+)
+
+// Error codes the server reports for problems it detects itself. Domain
+// errors carry their own codes, such as "game_not_found" or "username_taken".
+const (
+	CodeInvalidRequest  = "invalid_request"
+	CodeUnauthorized    = "unauthorized"
+	CodeAdminDisabled   = "admin_disabled"
+	CodeInvalidDataset  = "invalid_dataset"
+	CodeAlreadyAnswered = "already_answered"
+	CodeRateLimited     = "rate_limited"
+	CodeInternal        = "internal_error"
+)
+
+// Error is an error response of the API
+type Error struct {
+	StatusCode int
+	Code       string   // Stable error code, such as "game_not_found"
+	Message    string   // Human-readable message, translated for player routes
+	RequestID  string   // ID to find the request in the server's logs
+	Problems   []string // Each problem of a request that failed validation
+	// Answer is the outcome recorded for the question of an already_answered error
+	Answer *models.SubmitAnswerResponse
+	// RetryAfter is how long to wait before calling again, sent with rate_limited errors
+	RetryAfter time.Duration
+	// Retried reports whether the call was sent more than once
+	Retried bool
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	message := fmt.Sprintf("globetrotter: %s (%s, status %d", e.Message, e.Code, e.StatusCode)
+	if len(e.Problems) > 0 {
+		message = fmt.Sprintf("globetrotter: %s: %s (%s, status %d", e.Message, strings.Join(e.Problems, "; "), e.Code, e.StatusCode)
+	}
+	if e.RequestID != "" {
+		message += ", request " + e.RequestID
+	}
+	return message + ")"
+}
+
+// Is matches the kind of the error's status, or an *Error with the same code
+func (e *Error) Is(target error) bool {
+	if other, ok := target.(*Error); ok {
+		return other.Code == e.Code
+	}
+	return target == statusKind(e.StatusCode)
+}
+
+// statusKind maps an HTTP status to its kind of error
+func statusKind(status int) error {
+	switch {
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrBadRequest
+	case status == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case status == http.StatusForbidden:
+		return ErrForbidden
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// Code returns the error code of an *Error in err's chain, or "" if there is none
+func Code(err error) string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
+
+// errorResponse is the envelope of every error response
+type errorResponse struct {
+	Error struct {
+		Code      string   `json:"code"`
+		Message   string   `json:"message"`
+		RequestID string   `json:"request_id"`
+		Problems  []string `json:"problems"`
+	} `json:"error"`
+	Answer *models.SubmitAnswerResponse `json:"answer"`
+}
+
+// decodeError reads an error response. Responses without the envelope, such
+// as those of a proxy in front of the server, get the status text as their
+// message.
+func decodeError(resp *http.Response) *Error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	var envelope errorResponse
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error.Code != "" {
+		apiErr.Code = envelope.Error.Code
+		apiErr.Message = envelope.Error.Message
+		apiErr.Problems = envelope.Error.Problems
+		apiErr.Answer = envelope.Answer
+		if envelope.Error.RequestID != "" {
+			apiErr.RequestID = envelope.Error.RequestID
+		}
+		return apiErr
+	}
+
+	apiErr.Message = http.StatusText(resp.StatusCode)
+	if apiErr.Message == "" {
+		apiErr.Message = resp.Status
+	}
+	return apiErr
+}
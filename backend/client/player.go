@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// Health is the response of the health check
+type Health struct {
+	Status    string `json:"status"`
+	Uptime    string `json:"uptime"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Health checks that the server is up
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var health Health
+	err := c.do(ctx, call{method: http.MethodGet, path: "/health", idempotent: true}, &health)
+	if err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// RandomDestination gets a random destination, from country or region if
+// either is set
+func (c *Client) RandomDestination(ctx context.Context, country, region string) (*models.Destination, error) {
+	query := url.Values{}
+	if country != "" {
+		query.Set("country", country)
+	}
+	if region != "" {
+		query.Set("region", region)
+	}
+
+	var destination models.Destination
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/destinations/random", query: query, idempotent: true}, &destination)
+	if err != nil {
+		return nil, err
+	}
+	return &destination, nil
+}
+
+// CreateUser creates a player, optionally with a preferred language such as
+// "es" or "pt-BR". A taken username fails with the code "username_taken".
+func (c *Client) CreateUser(ctx context.Context, username, locale string) (*models.User, error) {
+	body := struct {
+		Username string `json:"username"`
+		Locale   string `json:"locale,omitempty"`
+	}{username, locale}
+
+	var user models.User
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/users", body: body}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUser gets a player by username
+func (c *Client) GetUser(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	err := c.do(ctx, call{method: http.MethodGet, path: pathf("/api/users/%s", username), idempotent: true}, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetUserLocale sets a player's preferred language; an empty locale clears it
+func (c *Client) SetUserLocale(ctx context.Context, username, locale string) (*models.User, error) {
+	body := struct {
+		Locale string `json:"locale"`
+	}{locale}
+
+	var user models.User
+	err := c.do(ctx, call{method: http.MethodPatch, path: pathf("/api/users/%s", username), body: body, idempotent: true}, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// StartGame starts a game for a player with a scoring mode, one of the
+// models.Scoring constants or "" for standard scoring, and returns its ID
+func (c *Client) StartGame(ctx context.Context, username, scoring string) (int, error) {
+	body := struct {
+		Username string `json:"username"`
+		Scoring  string `json:"scoring,omitempty"`
+	}{username, scoring}
+
+	var response struct {
+		GameID int `json:"game_id"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/game/play", body: body}, &response); err != nil {
+		return 0, err
+	}
+	return response.GameID, nil
+}
+
+// NextQuestion gets the next unanswered question of a game. Once every
+// question is answered it fails with the code "game_finished".
+func (c *Client) NextQuestion(ctx context.Context, gameID int) (*models.NextQuestionResponse, error) {
+	var question models.NextQuestionResponse
+	err := c.do(ctx, call{method: http.MethodGet, path: pathf("/api/game/%d/next-question", gameID), idempotent: true}, &question)
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// SubmitAnswer answers a question with an option, or with a pin in pin games.
+//
+// A question can only be answered once, so the call is safe to retry: if a
+// retry finds the answer already recorded, the recorded outcome is returned.
+// Answering a question that was answered before the call fails with the code
+// "already_answered", and the error's Answer holds the recorded outcome.
+// Questions of a finished game removed by compaction fail with the code
+// "game_compacted".
+func (c *Client) SubmitAnswer(ctx context.Context, answer models.SubmitAnswerRequest) (*models.SubmitAnswerResponse, error) {
+	var result models.SubmitAnswerResponse
+	err := c.do(ctx, call{
+		method:     http.MethodPost,
+		path:       pathf("/api/game/%d/submit-answer", answer.GameID),
+		body:       answer,
+		idempotent: true,
+	}, &result)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Retried && apiErr.Code == CodeAlreadyAnswered && apiErr.Answer != nil {
+		// An earlier attempt was recorded but its response was lost
+		return apiErr.Answer, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GameResult gets the totals and every question of a game
+func (c *Client) GameResult(ctx context.Context, gameID int) (*models.GameResult, error) {
+	var result models.GameResult
+	err := c.do(ctx, call{method: http.MethodGet, path: pathf("/api/game/%d/result", gameID), idempotent: true}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GameSummary gets the summary of a game shown on challenge pages
+func (c *Client) GameSummary(ctx context.Context, gameID int) (*models.GameSummary, error) {
+	var summary models.GameSummary
+	err := c.do(ctx, call{method: http.MethodGet, path: pathf("/api/game/%d/summary", gameID), idempotent: true}, &summary)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Submit submits a destination, or clues, fun facts and trivia for an
+// existing one, for review. Kind and Username must be set, and DestinationID
+// for clues submissions; the review fields are ignored.
+func (c *Client) Submit(ctx context.Context, submission models.Submission) (*models.Submission, error) {
+	body := struct {
+		Username      string   `json:"username"`
+		Kind          string   `json:"kind"`
+		DestinationID *int     `json:"destination_id,omitempty"`
+		City          string   `json:"city,omitempty"`
+		Country       string   `json:"country,omitempty"`
+		Latitude      *float64 `json:"latitude,omitempty"`
+		Longitude     *float64 `json:"longitude,omitempty"`
+		Clues         []string `json:"clues,omitempty"`
+		FunFact       []string `json:"fun_fact,omitempty"`
+		Trivia        []string `json:"trivia,omitempty"`
+	}{
+		Username:      submission.Username,
+		Kind:          submission.Kind,
+		DestinationID: submission.DestinationID,
+		City:          submission.City,
+		Country:       submission.Country,
+		Latitude:      submission.Latitude,
+		Longitude:     submission.Longitude,
+		Clues:         submission.Clues,
+		FunFact:       submission.FunFact,
+		Trivia:        submission.Trivia,
+	}
+
+	var created models.Submission
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/api/submissions", body: body}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UserSubmissions lists a player's submissions
+func (c *Client) UserSubmissions(ctx context.Context, username string) ([]models.Submission, error) {
+	var submissions []models.Submission
+	err := c.do(ctx, call{method: http.MethodGet, path: pathf("/api/users/%s/submissions", username), idempotent: true}, &submissions)
+	if err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
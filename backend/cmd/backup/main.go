@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/shubhsherl/globetrotter/backend/backup"
+	"github.com/shubhsherl/globetrotter/backend/db"
+)
+
+const usage = `Usage: backup [flags] <command>
+
+Backs up the SQLite database at DB_PATH with SQLite's online backup API,
+which is safe while the server is running. Flags default to BACKUP_DIR,
+BACKUP_KEEP and BACKUP_COMPRESS when they are set.
+
+Commands:
+  create          Write a timestamped snapshot and remove the oldest beyond --keep
+  list            List snapshots, newest first
+  restore <file>  Check a snapshot with PRAGMA integrity_check and replace the
+                  database with it; stop the server first
+
+Flags:
+`
+
+func main() {
+	defaults, err := backup.OptionsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dsn := flag.String("dsn", db.DSN(), "SQLite database to back up or restore")
+	dir := flag.String("dir", defaults.Dir, "directory snapshots are kept in")
+	keep := flag.Int("keep", defaults.Keep, "newest snapshots to keep, 0 keeps all")
+	compress := flag.Bool("compress", defaults.Compress, "gzip snapshots")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := backup.Options{Dir: *dir, Compress: *compress, Keep: *keep}
+	if err := run(*dsn, opts, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run executes command against the database named by dsn
+func run(dsn string, opts backup.Options, command string, args []string) error {
+	switch command {
+	case "create":
+		snapshot, err := backup.Create(context.Background(), dsn, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s (%d bytes)\n", snapshot.Path, snapshot.Size)
+		return nil
+	case "list":
+		return list(opts.Dir)
+	case "restore":
+		if len(args) != 1 {
+			return fmt.Errorf("restore needs a snapshot file")
+		}
+		previous, err := backup.Restore(args[0], dsn)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s from %s\n", dsn, args[0])
+		if previous != "" {
+			fmt.Printf("The previous database was kept as %s\n", previous)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// list prints the snapshots in dir with their size
+func list(dir string) error {
+	snapshots, err := backup.List(dir)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("No snapshots in %s\n", dir)
+		return nil
+	}
+	for _, s := range snapshots {
+		fmt.Printf("%s  %10d  %s\n", s.CreatedAt.Format("2006-01-02 15:04:05"), s.Size, s.Path)
+	}
+	return nil
+}
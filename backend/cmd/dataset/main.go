@@ -0,0 +1,393 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/shubhsherl/globetrotter/backend/dataset"
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+const usage = `Usage:
+  dataset import [--format json|csv|yaml] [--dry-run] [--prune] [--skip-lint] FILE
+  dataset import --locale LOCALE [--format json|csv|yaml] [--dry-run] FILE
+  dataset export [--format json|csv|yaml] [--include-retired] [FILE]
+  dataset export --locale LOCALE [--format json|csv|yaml] [FILE]
+  dataset lint [--format json|csv|yaml] [--output text|json] [FILE]
+
+Import upserts destinations by city and country. With --prune, destinations
+missing from FILE are deleted, or retired if existing games reference them.
+Export writes to stdout when FILE is omitted or "-".
+
+With --locale, import and export per-locale translation files instead: one
+entry per city, country, clue, fun fact and trivia line, matched to the
+English dataset by city, country and source text. Export lists every item,
+with empty text where no translation exists yet. Pass --locale auto to take
+the locale from the file name (e.g. locales/pt-BR.json).
+
+Lint checks FILE (default DATASET_PATH or data/data.json) for content
+problems and exits with status 1 if it finds errors. Import refuses files
+with lint errors unless --skip-lint is given.
+
+The database is read from DATABASE_URL if set, otherwise from the SQLite
+file at DB_PATH (default ./data/globetrotter.db).
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "lint":
+		err = runLint(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// openDatabase opens the database configured by DATABASE_URL or DB_PATH
+func openDatabase() (storage.Store, error) {
+	return db.Open(db.DSN())
+}
+
+// resolveFormat picks the explicit format if given, otherwise guesses from the path
+func resolveFormat(name, path string) (dataset.Format, error) {
+	if name != "" {
+		return dataset.ParseFormat(name)
+	}
+	if path == "" || path == "-" {
+		return dataset.FormatJSON, nil
+	}
+	return dataset.FormatFromPath(path)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := fs.String("format", "", "file format (default: from file extension)")
+	dryRun := fs.Bool("dry-run", false, "show what would change without writing")
+	prune := fs.Bool("prune", false, "remove destinations that are not in the file")
+	skipLint := fs.Bool("skip-lint", false, "import even if the file has lint errors")
+	locale := fs.String("locale", "", "import a translation file for this locale")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("import needs exactly one file")
+	}
+	path := fs.Arg(0)
+
+	format, err := resolveFormat(*formatName, path)
+	if err != nil {
+		return err
+	}
+
+	if *locale != "" {
+		return importTranslations(path, format, *locale, *dryRun)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	incoming, err := dataset.Read(file, format)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	if issues := dataset.Lint(incoming, dataset.DefaultLintOptions); dataset.HasErrors(issues) && !*skipLint {
+		printIssues(os.Stderr, issues)
+		return fmt.Errorf("%s has lint errors, fix them or pass --skip-lint", path)
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	existing, err := database.ListDestinations(true)
+	if err != nil {
+		return err
+	}
+
+	plan := dataset.Diff(existing, incoming)
+
+	if *dryRun {
+		plan.WriteDiff(os.Stdout, *prune)
+		printSummary(plan, *prune, nil)
+		fmt.Println("Dry run, no changes written")
+		return nil
+	}
+
+	added, updated, removedIDs := plan.Changes(*prune)
+	result, err := database.ImportDestinations(models.ActorCLI, added, updated, removedIDs, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range plan.Warnings {
+		fmt.Printf("! %s\n", warning)
+	}
+	printSummary(plan, *prune, &result)
+	return nil
+}
+
+// printSummary reports how many rows the import adds, updates and leaves alone
+func printSummary(plan *dataset.Plan, prune bool, result *storage.ImportResult) {
+	fmt.Printf("Added: %d, Updated: %d, Unchanged: %d\n", len(plan.Added), len(plan.Updated), len(plan.Unchanged))
+
+	if !prune {
+		if len(plan.Missing) > 0 {
+			fmt.Printf("Not in file (kept, use --prune to remove): %d\n", len(plan.Missing))
+		}
+		return
+	}
+
+	if result != nil {
+		fmt.Printf("Deleted: %d, Retired (still referenced by games): %d\n", result.Deleted, result.Retired)
+	} else {
+		fmt.Printf("To remove: %d (referenced destinations are retired, not deleted)\n", len(plan.Missing))
+	}
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := fs.String("format", "", "file format (default: from file extension, json for stdout)")
+	includeRetired := fs.Bool("include-retired", false, "include retired destinations")
+	locale := fs.String("locale", "", "export the translation file for this locale")
+	fs.Parse(args)
+
+	if fs.NArg() > 1 {
+		return fmt.Errorf("export takes at most one file")
+	}
+	path := fs.Arg(0)
+
+	format, err := resolveFormat(*formatName, path)
+	if err != nil {
+		return err
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	destinations, err := database.ListDestinations(*includeRetired)
+	if err != nil {
+		return err
+	}
+
+	var entries []dataset.TranslationEntry
+	if *locale != "" {
+		resolved, err := resolveLocale(*locale, path)
+		if err != nil {
+			return err
+		}
+
+		names, clues, facts, err := database.ListTranslations(resolved)
+		if err != nil {
+			return err
+		}
+		entries = dataset.TranslationEntries(destinations, names, clues, facts)
+	}
+
+	var out io.Writer = os.Stdout
+	if path != "" && path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if *locale != "" {
+		if err := dataset.WriteTranslations(out, format, entries); err != nil {
+			return err
+		}
+		if out != os.Stdout {
+			log.Printf("Exported %d translation entries to %s", len(entries), path)
+		}
+		return nil
+	}
+
+	if err := dataset.Write(out, format, destinations); err != nil {
+		return err
+	}
+
+	if out != os.Stdout {
+		log.Printf("Exported %d destinations to %s", len(destinations), path)
+	}
+	return nil
+}
+
+// resolveLocale validates a --locale flag, taking it from the file name when "auto"
+func resolveLocale(locale, path string) (string, error) {
+	if locale == "auto" {
+		return dataset.LocaleFromPath(path)
+	}
+
+	normalized := i18n.Normalize(locale)
+	if normalized == "" {
+		return "", fmt.Errorf("invalid locale %q", locale)
+	}
+	if normalized == i18n.DefaultLocale {
+		return "", fmt.Errorf("%s is the dataset's own language, import it without --locale", normalized)
+	}
+	return normalized, nil
+}
+
+// importTranslations upserts the translations in a per-locale file
+func importTranslations(path string, format dataset.Format, locale string, dryRun bool) error {
+	locale, err := resolveLocale(locale, path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entries, err := dataset.ReadTranslations(file, format)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	destinations, err := database.ListDestinations(true)
+	if err != nil {
+		return err
+	}
+
+	plan := dataset.MatchTranslations(destinations, entries, locale)
+	for _, warning := range plan.Warnings {
+		fmt.Printf("! %s\n", warning)
+	}
+	fmt.Printf("Locale %s: %d destination names, %d clues, %d facts; %d entries untranslated, %d not matched\n",
+		locale, len(plan.Names), len(plan.Clues), len(plan.Facts), plan.Skipped, len(plan.Warnings))
+
+	if dryRun {
+		fmt.Println("Dry run, no changes written")
+		return nil
+	}
+
+	return database.SaveTranslations(plan.Names, plan.Clues, plan.Facts)
+}
+
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	formatName := fs.String("format", "", "file format (default: from file extension)")
+	output := fs.String("output", "text", "report format: text or json")
+	fs.Parse(args)
+
+	path := dataset.Path()
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	format, err := resolveFormat(*formatName, path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	destinations, err := dataset.Read(file, format)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	issues := dataset.Lint(destinations, dataset.DefaultLintOptions)
+
+	switch *output {
+	case "json":
+		report := struct {
+			File     string          `json:"file"`
+			Errors   int             `json:"errors"`
+			Warnings int             `json:"warnings"`
+			Issues   []dataset.Issue `json:"issues"`
+		}{File: path, Issues: issues}
+		for _, issue := range issues {
+			if issue.Severity == dataset.SeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+		if report.Issues == nil {
+			report.Issues = []dataset.Issue{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	case "text":
+		printIssues(os.Stdout, issues)
+	default:
+		return fmt.Errorf("unknown output %q", *output)
+	}
+
+	if dataset.HasErrors(issues) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// printIssues writes lint issues one per line followed by a count
+func printIssues(w io.Writer, issues []dataset.Issue) {
+	errors := 0
+	for _, issue := range issues {
+		location := fmt.Sprintf("#%d %s, %s", issue.Index, issue.City, issue.Country)
+		if issue.Field != "" {
+			location += " " + issue.Field
+			if issue.Entry > 0 {
+				location += fmt.Sprintf("[%d]", issue.Entry)
+			}
+		}
+		fmt.Fprintf(w, "%s: %s: %s (%s)\n", issue.Severity, location, issue.Message, issue.Code)
+
+		if issue.Severity == dataset.SeverityError {
+			errors++
+		}
+	}
+	fmt.Fprintf(w, "%d errors, %d warnings\n", errors, len(issues)-errors)
+}
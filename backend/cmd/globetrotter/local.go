@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shubhsherl/globetrotter/backend/client"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services"
+)
+
+// player is what the game needs from the API. *client.Client implements it
+// for a server, localPlayer for a database opened by the command itself.
+type player interface {
+	CreateUser(ctx context.Context, username, locale string) (*models.User, error)
+	GetUser(ctx context.Context, username string) (*models.User, error)
+	StartGame(ctx context.Context, username, scoring string) (int, error)
+	NextQuestion(ctx context.Context, gameID int) (*models.NextQuestionResponse, error)
+	SubmitAnswer(ctx context.Context, answer models.SubmitAnswerRequest) (*models.SubmitAnswerResponse, error)
+	GameResult(ctx context.Context, gameID int) (*models.GameResult, error)
+}
+
+var _ player = (*client.Client)(nil)
+
+// localPlayer plays against the services directly, as the API handlers do
+type localPlayer struct {
+	data     *services.DataService
+	accepted []string // Languages asked for with -lang
+}
+
+// CreateUser implements player
+func (p *localPlayer) CreateUser(ctx context.Context, username, locale string) (*models.User, error) {
+	user, err := p.data.CreateUser(username, locale)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUser implements player
+func (p *localPlayer) GetUser(ctx context.Context, username string) (*models.User, error) {
+	user, err := p.data.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// StartGame implements player
+func (p *localPlayer) StartGame(ctx context.Context, username, scoring string) (int, error) {
+	return p.data.CreateGame(username, scoring)
+}
+
+// NextQuestion implements player
+func (p *localPlayer) NextQuestion(ctx context.Context, gameID int) (*models.NextQuestionResponse, error) {
+	return p.data.NextQuestion(gameID, p.data.GameLocales(gameID, p.accepted))
+}
+
+// SubmitAnswer implements player
+func (p *localPlayer) SubmitAnswer(ctx context.Context, answer models.SubmitAnswerRequest) (*models.SubmitAnswerResponse, error) {
+	locales := p.data.GameLocales(answer.GameID, p.accepted)
+	return p.data.SubmitAnswer(answer.GameID, answer.QuestionID, answer.SelectedDestination, answer.Pin, locales)
+}
+
+// GameResult implements player
+func (p *localPlayer) GameResult(ctx context.Context, gameID int) (*models.GameResult, error) {
+	return p.data.GetGameResult(gameID)
+}
+
+// errorCode returns the error code of an API or domain error, or "" for any other error
+func errorCode(err error) string {
+	var domain *services.Error
+	if errors.As(err, &domain) {
+		return domain.Code
+	}
+	return client.Code(err)
+}
+
+// errorMessage returns the message of err to show the player, translating
+// domain errors along locales as the API would
+func errorMessage(err error, locales []string) string {
+	var (
+		apiErr     *client.Error
+		domain     *services.Error
+		validation *services.ValidationError
+	)
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.Message
+	case errors.As(err, &validation):
+		return i18n.T(locales, validation.Message())
+	case errors.As(err, &domain):
+		return i18n.T(locales, domain.Message)
+	default:
+		return err.Error()
+	}
+}
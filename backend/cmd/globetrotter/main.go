@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shubhsherl/globetrotter/backend/client"
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services"
+)
+
+const usage = `Usage:
+  globetrotter [-server URL] [-user NAME] [-scoring MODE] [-lang LANG] [-v]
+  globetrotter -db FILE [-user NAME] [-scoring MODE] [-lang LANG] [-v]
+
+Plays Globetrotter in the terminal. Logs in as NAME, registering the player
+if they don't exist yet, and asks for a name when -user is not given. Each
+question shows a clue and numbered options: answer with an option's number,
+or q to stop. Pin games ask for "latitude, longitude" instead. -scoring is
+standard (default), proximity or pin, and -lang the language of questions
+and answers, such as es or pt-BR.
+
+The game is played against the server at -server, default GLOBETROTTER_URL
+or http://localhost:8080. With -db it is played offline against the SQLite
+file FILE, which is created and seeded from DATASET_PATH (default
+data/data.json) if it doesn't exist. Pass -v to see the log.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	server := flag.String("server", "", "URL of the server")
+	dbPath := flag.String("db", "", "SQLite file to play offline against")
+	username := flag.String("user", "", "player name")
+	scoring := flag.String("scoring", "", "scoring mode: standard, proximity or pin")
+	lang := flag.String("lang", "", "language of questions and answers")
+	verbose := flag.Bool("v", false, "show the log")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() > 0 || (*server != "" && *dbPath != "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	g := &game{
+		ctx:     context.Background(),
+		in:      bufio.NewScanner(os.Stdin),
+		out:     os.Stdout,
+		locales: i18n.Chain(i18n.ParseAcceptLanguage(*lang)...),
+	}
+
+	if *dbPath != "" {
+		database, err := db.Open(*dbPath)
+		if err != nil {
+			fail(err)
+		}
+		defer database.Close()
+		g.player = &localPlayer{data: services.NewDataService(database), accepted: i18n.ParseAcceptLanguage(*lang)}
+	} else {
+		if *server == "" {
+			*server = os.Getenv("GLOBETROTTER_URL")
+		}
+		if *server == "" {
+			*server = "http://localhost:8080"
+		}
+		g.player = client.New(*server, client.WithLanguage(*lang))
+	}
+
+	if err := g.run(*username, *scoring); err != nil {
+		fail(err)
+	}
+}
+
+// fail prints err and exits
+func fail(err error) {
+	log.SetOutput(os.Stderr)
+	log.Fatalf("globetrotter: %v", err)
+}
+
+// game plays one game in the terminal
+type game struct {
+	ctx     context.Context
+	player  player
+	in      *bufio.Scanner
+	out     io.Writer
+	locales []string // For messages of domain errors in offline games
+}
+
+// errQuit is returned when the player stops before the game ends
+var errQuit = errors.New("quit")
+
+// run logs in or registers username, plays a game to the end and prints its result
+func (g *game) run(username, scoring string) error {
+	username, err := g.login(username)
+	if err == errQuit {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	gameID, err := g.player.StartGame(g.ctx, username, scoring)
+	if err != nil {
+		return fmt.Errorf("failed to start a game: %s", errorMessage(err, g.locales))
+	}
+
+	for number := 1; ; number++ {
+		question, err := g.player.NextQuestion(g.ctx, gameID)
+		if errorCode(err) == "game_finished" {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get the next question: %s", errorMessage(err, g.locales))
+		}
+
+		err = g.ask(number, question)
+		if err == errQuit {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := g.player.GameResult(g.ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("failed to get the game result: %s", errorMessage(err, g.locales))
+	}
+	g.printResult(result)
+	return nil
+}
+
+// login returns the name of the player, asking for it if username is
+// empty, and registers the player if they don't exist yet
+func (g *game) login(username string) (string, error) {
+	for username == "" {
+		line, ok := g.prompt("Your name: ")
+		if !ok {
+			return "", errQuit
+		}
+		username = line
+	}
+
+	user, err := g.player.GetUser(g.ctx, username)
+	if errorCode(err) == "user_not_found" {
+		user, err = g.player.CreateUser(g.ctx, username, "")
+		if err != nil {
+			return "", fmt.Errorf("failed to register %s: %s", username, errorMessage(err, g.locales))
+		}
+		fmt.Fprintf(g.out, "Welcome to Globetrotter, %s!\n", user.Username)
+		return user.Username, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to log in as %s: %s", username, errorMessage(err, g.locales))
+	}
+
+	fmt.Fprintf(g.out, "Welcome back, %s!\n", user.Username)
+	return user.Username, nil
+}
+
+// option is an answer shown with a number
+type option struct {
+	id   int
+	name string
+}
+
+// ask shows a question, reads the answer until it is accepted and shows the outcome
+func (g *game) ask(number int, question *models.NextQuestionResponse) error {
+	fmt.Fprintf(g.out, "\nQuestion %d\n  %s\n", number, question.Question)
+
+	// Number the options in a stable order
+	options := make([]option, 0, len(question.OptionsDisplay))
+	for id, name := range question.OptionsDisplay {
+		options = append(options, option{id, name})
+	}
+	sort.Slice(options, func(i, j int) bool { return options[i].name < options[j].name })
+	if len(options) > 0 {
+		fmt.Fprintln(g.out)
+	}
+	for i, opt := range options {
+		fmt.Fprintf(g.out, "  %d) %s\n", i+1, opt.name)
+	}
+
+	for {
+		answer := models.SubmitAnswerRequest{GameID: question.GameID, QuestionID: question.QuestionID}
+
+		var (
+			line string
+			ok   bool
+		)
+		if question.Scoring == models.ScoringPin {
+			line, ok = g.prompt("\nDrop a pin (latitude, longitude), or q to stop: ")
+		} else {
+			line, ok = g.prompt(fmt.Sprintf("\nYour answer (1-%d), or q to stop: ", len(options)))
+		}
+		if !ok || line == "q" || line == "quit" {
+			return errQuit
+		}
+
+		if question.Scoring == models.ScoringPin {
+			pin, ok := parsePin(line)
+			if !ok {
+				fmt.Fprintln(g.out, "Enter a latitude from -90 to 90 and a longitude from -180 to 180, like 48.85, 2.35")
+				continue
+			}
+			answer.Pin = pin
+		} else {
+			choice, err := strconv.Atoi(line)
+			if err != nil || choice < 1 || choice > len(options) {
+				fmt.Fprintf(g.out, "Enter a number from 1 to %d\n", len(options))
+				continue
+			}
+			answer.SelectedDestination = options[choice-1].id
+		}
+
+		result, err := g.player.SubmitAnswer(g.ctx, answer)
+		if errorCode(err) == "invalid_answer" {
+			fmt.Fprintln(g.out, errorMessage(err, g.locales))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to submit the answer: %s", errorMessage(err, g.locales))
+		}
+
+		g.printAnswer(question.Scoring, result)
+		return nil
+	}
+}
+
+// parsePin parses a "latitude, longitude" answer, reporting whether it is a valid location
+func parsePin(line string) (*models.Pin, bool) {
+	parts := strings.Split(line, ",")
+	if len(parts) != 2 {
+		return nil, false
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return nil, false
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return nil, false
+	}
+
+	return &models.Pin{Latitude: latitude, Longitude: longitude}, true
+}
+
+// printAnswer shows the outcome of an answer with its fun fact or trivia
+func (g *game) printAnswer(scoring string, result *models.SubmitAnswerResponse) {
+	if result.Correct {
+		fmt.Fprintf(g.out, "\nCorrect! It's %s, %s.", result.CorrectCity, result.CorrectCountry)
+	} else {
+		fmt.Fprintf(g.out, "\nNot quite. It's %s, %s.", result.CorrectCity, result.CorrectCountry)
+	}
+	if result.DistanceKm != nil {
+		fmt.Fprintf(g.out, " You were %.0f km away.", *result.DistanceKm)
+	}
+	if scoring == models.ScoringStandard || scoring == "" {
+		fmt.Fprintln(g.out)
+	} else {
+		fmt.Fprintf(g.out, " +%d points\n", result.Points)
+	}
+
+	if result.FunFact != "" {
+		fmt.Fprintf(g.out, "Fun fact: %s\n", result.FunFact)
+	}
+	if result.Trivia != "" {
+		fmt.Fprintf(g.out, "Trivia: %s\n", result.Trivia)
+	}
+	if result.ClueSubmittedBy != "" {
+		fmt.Fprintf(g.out, "Clue contributed by %s\n", result.ClueSubmittedBy)
+	}
+}
+
+// printResult shows the totals of a game
+func (g *game) printResult(result *models.GameResult) {
+	answered := result.TotalCorrect + result.TotalIncorrect
+
+	fmt.Fprintf(g.out, "\nGame %d over\n", result.GameID)
+	fmt.Fprintf(g.out, "  Answered:  %d of %d\n", answered, result.TotalQuestions)
+	fmt.Fprintf(g.out, "  Correct:   %d\n", result.TotalCorrect)
+	fmt.Fprintf(g.out, "  Incorrect: %d\n", result.TotalIncorrect)
+	fmt.Fprintf(g.out, "  Score:     %d\n", result.Score)
+}
+
+// prompt prints label and reads a trimmed line, reporting false at the end of the input
+func (g *game) prompt(label string) (string, bool) {
+	fmt.Fprint(g.out, label)
+	if !g.in.Scan() {
+		fmt.Fprintln(g.out)
+		return "", false
+	}
+	return strings.TrimSpace(g.in.Text()), true
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
+)
+
+// keyedPlayer is a localPlayer that remembers the last game started and
+// question asked, so scripts can answer it
+type keyedPlayer struct {
+	*localPlayer
+	database *db.Database
+	gameID   int
+	question *models.NextQuestionResponse
+}
+
+func (p *keyedPlayer) StartGame(ctx context.Context, username, scoring string) (int, error) {
+	gameID, err := p.localPlayer.StartGame(ctx, username, scoring)
+	p.gameID = gameID
+	return gameID, err
+}
+
+func (p *keyedPlayer) NextQuestion(ctx context.Context, gameID int) (*models.NextQuestionResponse, error) {
+	question, err := p.localPlayer.NextQuestion(ctx, gameID)
+	p.question = question
+	return question, err
+}
+
+// correct returns the destination the last question asked about
+func (p *keyedPlayer) correct(t *testing.T) *models.Destination {
+	t.Helper()
+	question, err := p.database.GetQuestionByID(p.question.GameID, p.question.QuestionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest, err := p.database.GetDestinationByID(question.CorrectDestinationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dest
+}
+
+// choice returns the number the game shows next to an option of the last question
+func (p *keyedPlayer) choice(t *testing.T, correct bool) string {
+	t.Helper()
+	want := p.correct(t).ID
+	var names []string
+	for _, name := range p.question.OptionsDisplay {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if (p.question.OptionsDisplay[want] == name) == correct {
+			return fmt.Sprint(i + 1)
+		}
+	}
+	t.Fatalf("question %d has no such option", p.question.QuestionID)
+	return ""
+}
+
+// script is standard input whose lines are written as the game reads them,
+// once it has shown what they answer
+type script func() (string, bool)
+
+func (s script) Read(b []byte) (int, error) {
+	line, ok := s()
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(b, line+"\n"), nil
+}
+
+// newPlayer opens a database seeded from the repository's dataset
+func newPlayer(t *testing.T, lang string) *keyedPlayer {
+	t.Helper()
+	t.Setenv("DATASET_PATH", filepath.Join("..", "..", "data", "data.json"))
+	database, err := db.Open(filepath.Join(t.TempDir(), "globetrotter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return &keyedPlayer{
+		localPlayer: &localPlayer{data: services.NewDataService(database), accepted: i18n.ParseAcceptLanguage(lang)},
+		database:    database,
+	}
+}
+
+// play runs a game of p, reading lines from next, and returns its output
+func play(t *testing.T, p *keyedPlayer, username, scoring string, next script) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	g := &game{
+		ctx:     context.Background(),
+		player:  p,
+		in:      bufio.NewScanner(next),
+		out:     &out,
+		locales: i18n.Chain(p.accepted...),
+	}
+	err := g.run(username, scoring)
+	return out.String(), err
+}
+
+// lines returns a script that reads the given lines before asking answer
+// for each of the rest
+func lines(first []string, answer func() string) script {
+	return func() (string, bool) {
+		if len(first) > 0 {
+			line := first[0]
+			first = first[1:]
+			return line, true
+		}
+		return answer(), true
+	}
+}
+
+// checkOutput reports a failure for each of want that out doesn't contain
+func checkOutput(t *testing.T, out string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("output is missing %q:\n%s", w, out)
+		}
+	}
+}
+
+func TestPlayCorrectAnswers(t *testing.T) {
+	p := newPlayer(t, "")
+	asked := 0
+	out, err := play(t, p, "", models.ScoringStandard, lines([]string{"casey"}, func() string {
+		asked++
+		if asked == 1 {
+			return "0" // Out of range, asked again
+		}
+		return p.choice(t, true)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkOutput(t, out,
+		"Your name: ",
+		"Welcome to Globetrotter, casey!",
+		"Question 1\n", "Question 5\n",
+		"  1) ", "  4) ",
+		"Your answer (1-4), or q to stop: ",
+		"Enter a number from 1 to 4\n",
+		"Correct! It's ",
+		"Fun fact: ",
+		"Answered:  5 of 5\n",
+		"Correct:   5\n",
+		"Incorrect: 0\n",
+		fmt.Sprintf("Score:     %d\n", 5*geo.MaxPoints),
+	)
+	if strings.Contains(out, "Not quite") || strings.Contains(out, "Question 6") {
+		t.Errorf("output has a wrong answer or an extra question:\n%s", out)
+	}
+
+	// The result printed is the one recorded
+	result, err := p.GameResult(context.Background(), p.gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalCorrect != 5 || result.Score != 5*geo.MaxPoints {
+		t.Errorf("recorded result is %+v, want 5 correct answers", result)
+	}
+}
+
+func TestPlayWrongAnswers(t *testing.T) {
+	p := newPlayer(t, "")
+	out, err := play(t, p, "casey", models.ScoringStandard, lines(nil, func() string { return p.choice(t, false) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, out,
+		"Welcome to Globetrotter, casey!",
+		"Not quite. It's ",
+		"Trivia: ",
+		"Answered:  5 of 5\n",
+		"Correct:   0\n",
+		"Incorrect: 5\n",
+		"Score:     0\n",
+	)
+}
+
+func TestPlayPins(t *testing.T) {
+	p := newPlayer(t, "")
+	asked := 0
+	out, err := play(t, p, "casey", models.ScoringPin, lines(nil, func() string {
+		asked++
+		switch asked {
+		case 1:
+			return "somewhere"
+		case 2:
+			return "91, 0"
+		}
+		dest := p.correct(t)
+		return fmt.Sprintf("%f, %f", *dest.Latitude, *dest.Longitude)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, out,
+		"Drop a pin (latitude, longitude), or q to stop: ",
+		"Enter a latitude from -90 to 90 and a longitude from -180 to 180, like 48.85, 2.35\n",
+		"You were 0 km away.",
+		fmt.Sprintf(" +%d points\n", geo.MaxPoints),
+		"Correct:   5\n",
+		fmt.Sprintf("Score:     %d\n", 5*geo.MaxPoints),
+	)
+	if strings.Contains(out, "\n  1) ") {
+		t.Errorf("pin game showed options:\n%s", out)
+	}
+}
+
+func TestPlayQuit(t *testing.T) {
+	p := newPlayer(t, "")
+	if _, err := play(t, p, "casey", models.ScoringStandard, lines([]string{"q"}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A returning player who answers once and then runs out of input
+	answered := false
+	out, err := play(t, p, "casey", models.ScoringStandard, func() (string, bool) {
+		if answered {
+			return "", false
+		}
+		answered = true
+		return p.choice(t, true), true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, out,
+		"Welcome back, casey!",
+		"Question 2\n",
+		"Answered:  1 of 5\n",
+		fmt.Sprintf("Score:     %d\n", geo.MaxPoints),
+	)
+
+	// Stopping at the name prompt plays nothing
+	out, err = play(t, p, "", models.ScoringStandard, func() (string, bool) { return "", false })
+	if err != nil || strings.Contains(out, "Question") {
+		t.Errorf("got %v and output:\n%s", err, out)
+	}
+}
+
+// TestPlayErrors checks that domain errors are shown in the language asked for
+func TestPlayErrors(t *testing.T) {
+	p := newPlayer(t, "es")
+	_, err := play(t, p, "casey", "darts", lines(nil, nil))
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to start a game: ") {
+		t.Fatalf("got %v, want the game not to start", err)
+	}
+	if want := i18n.T([]string{"es"}, services.ErrInvalidScoring.Message); !strings.HasSuffix(err.Error(), want) || want == services.ErrInvalidScoring.Message {
+		t.Errorf("got %q, want the message in Spanish", err)
+	}
+}
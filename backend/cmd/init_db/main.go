@@ -8,25 +8,27 @@ import (
 )
 
 func main() {
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./data/globetrotter.db"
-	}
-	
-	log.Printf("Initializing database at %s...", dbPath)
-	
-	// Remove existing database if it exists
-	if _, err := os.Stat(dbPath); err == nil {
-		if err := os.Remove(dbPath); err != nil {
-			log.Fatalf("Failed to remove existing database: %v", err)
+	dsn := db.DSN()
+
+	log.Printf("Initializing database at %s...", db.Redact(dsn))
+
+	// Remove existing database if it exists. PostgreSQL databases are not
+	// dropped; their missing tables are created and empty ones seeded.
+	if !db.IsPostgres(dsn) {
+		if _, err := os.Stat(dsn); err == nil {
+			if err := os.Remove(dsn); err != nil {
+				log.Fatalf("Failed to remove existing database: %v", err)
+			}
+			log.Println("Removed existing database")
 		}
-		log.Println("Removed existing database")
 	}
-	
+
 	// Initialize database
-	if err := db.InitDB(dbPath); err != nil {
+	database, err := db.Open(dsn)
+	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
-	
+	database.Close()
+
 	log.Println("Database initialized successfully")
-} 
\ No newline at end of file
+}
@@ -1,109 +1,98 @@
 package main
 
 import (
-	"database/sql"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
-	"sort"
-	"strings"
+	"strconv"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/shubhsherl/globetrotter/backend/db"
 )
 
-const (
-	dbPath        = "./data/globetrotter.db"
-	migrationsDir = "./migrations"
-)
+const usage = `Usage: migrate [flags] <command>
 
-func main() {
-	// Ensure data directory exists
-	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
-		log.Fatalf("Failed to create data directory: %v", err)
-	}
+Manages the schema of the database named by DATABASE_URL or DB_PATH.
+The server applies pending migrations itself when it starts.
 
-	// Open database connection
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		log.Fatalf("Failed to open database: %v", err)
-	}
-	defer db.Close()
+Commands:
+  status        List migrations and whether they are applied
+  up            Apply all pending migrations
+  down          Revert the newest applied migration
+  to <version>  Apply or revert migrations until the database is at version
 
-	// Create migrations table if it doesn't exist
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS migrations (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL UNIQUE,
-			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	if err != nil {
-		log.Fatalf("Failed to create migrations table: %v", err)
+Flags:
+`
+
+func main() {
+	dsn := flag.String("dsn", db.DSN(), "database to migrate")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	// Get list of applied migrations
-	rows, err := db.Query("SELECT name FROM migrations")
-	if err != nil {
-		log.Fatalf("Failed to query migrations: %v", err)
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
 	}
-	defer rows.Close()
 
-	appliedMigrations := make(map[string]bool)
-	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
-			log.Fatalf("Failed to scan migration row: %v", err)
-		}
-		appliedMigrations[name] = true
+	if err := run(*dsn, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
 	}
+}
 
-	// Get list of migration files
-	files, err := ioutil.ReadDir(migrationsDir)
+// run executes command against the database named by dsn
+func run(dsn, command string, args []string) error {
+	m, err := db.OpenMigrator(dsn)
 	if err != nil {
-		log.Fatalf("Failed to read migrations directory: %v", err)
+		return err
 	}
-
-	var migrationFiles []string
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			migrationFiles = append(migrationFiles, file.Name())
+	defer m.Close()
+
+	switch command {
+	case "status":
+		return status(m)
+	case "up":
+		if err := m.Up(); err != nil {
+			return err
 		}
-	}
-
-	// Sort migration files by name
-	sort.Strings(migrationFiles)
-
-	// Apply migrations
-	for _, file := range migrationFiles {
-		if appliedMigrations[file] {
-			fmt.Printf("Migration %s already applied\n", file)
-			continue
+	case "down":
+		if err := m.Down(); err != nil {
+			return err
 		}
-
-		fmt.Printf("Applying migration %s...\n", file)
-
-		// Read migration file
-		content, err := ioutil.ReadFile(filepath.Join(migrationsDir, file))
-		if err != nil {
-			log.Fatalf("Failed to read migration file %s: %v", file, err)
+	case "to":
+		if len(args) != 1 {
+			return fmt.Errorf("to needs a version")
 		}
-
-		// Execute migration
-		_, err = db.Exec(string(content))
+		version, err := strconv.Atoi(args[0])
 		if err != nil {
-			log.Fatalf("Failed to execute migration %s: %v", file, err)
+			return fmt.Errorf("invalid version %q", args[0])
 		}
-
-		// Record migration
-		_, err = db.Exec("INSERT INTO migrations (name) VALUES (?)", file)
-		if err != nil {
-			log.Fatalf("Failed to record migration %s: %v", file, err)
+		if err := m.To(version); err != nil {
+			return err
 		}
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
 
-		fmt.Printf("Migration %s applied successfully\n", file)
+	version, err := m.Version()
+	if err != nil {
+		return err
 	}
+	fmt.Printf("%s is at version %d of %d\n", db.Redact(dsn), version, m.Latest())
+	return nil
+}
 
-	fmt.Println("All migrations applied successfully")
+// status prints each migration with when it was applied
+func status(m *db.Migrator) error {
+	statuses, err := m.Status()
+	for _, s := range statuses {
+		applied := "pending"
+		if s.Applied {
+			applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%03d  %-40s %s\n", s.Version, s.Name, applied)
+	}
+	return err
 }
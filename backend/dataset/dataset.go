@@ -0,0 +1,248 @@
+// Package dataset reads, writes and compares destination datasets in JSON, CSV and YAML.
+package dataset
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a dataset file format
+type Format string
+
+// Supported dataset formats
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatYAML Format = "yaml"
+)
+
+// ListSeparator separates multiple clues, fun facts or trivia within one CSV
+// cell. Entries containing it are written with it escaped as \|, and
+// backslashes as \\.
+const ListSeparator = "|"
+
+// csvHeader is the column layout used for CSV files
+var csvHeader = []string{"city", "country", "latitude", "longitude", "clues", "fun_fact", "trivia"}
+
+// optionalCSVColumns may be left out of CSV files written before destinations had coordinates
+var optionalCSVColumns = map[string]bool{"latitude": true, "longitude": true}
+
+// ParseFormat converts a format name to a Format
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", name)
+	}
+}
+
+// FormatFromPath guesses the format from a file's extension
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("cannot determine format of %q, pass it explicitly", path)
+	}
+	return ParseFormat(ext)
+}
+
+// Read parses destinations from r in the given format
+func Read(r io.Reader, format Format) ([]models.Destination, error) {
+	var destinations []models.Destination
+
+	switch format {
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&destinations); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+	case FormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&destinations); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to parse YAML: %v", err)
+		}
+	case FormatCSV:
+		var err error
+		destinations, err = readCSV(r)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	// IDs are assigned by the database, records are matched by city and country
+	for i := range destinations {
+		destinations[i].ID = 0
+	}
+
+	return destinations, nil
+}
+
+// Write serializes destinations to w in the given format
+func Write(w io.Writer, format Format, destinations []models.Destination) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(destinations)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(destinations); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatCSV:
+		return writeCSV(w, destinations)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// readCSV parses a CSV file with a header row, looking columns up by name
+func readCSV(r io.Reader) ([]models.Destination, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// Map column names to positions so spreadsheets can reorder columns
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvHeader {
+		if _, ok := columns[name]; !ok && !optionalCSVColumns[name] {
+			return nil, fmt.Errorf("CSV is missing the %q column", name)
+		}
+	}
+
+	destinations := make([]models.Destination, 0, len(records)-1)
+	for i, record := range records[1:] {
+		cell := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		latitude, err := parseCoordinate(cell("latitude"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid latitude: %v", i+2, err)
+		}
+		longitude, err := parseCoordinate(cell("longitude"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid longitude: %v", i+2, err)
+		}
+
+		destinations = append(destinations, models.Destination{
+			City:      cell("city"),
+			Country:   cell("country"),
+			Latitude:  latitude,
+			Longitude: longitude,
+			Clues:     splitList(cell("clues")),
+			FunFact:   splitList(cell("fun_fact")),
+			Trivia:    splitList(cell("trivia")),
+		})
+	}
+
+	return destinations, nil
+}
+
+// writeCSV writes destinations as CSV with list fields joined by ListSeparator
+func writeCSV(w io.Writer, destinations []models.Destination) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, dest := range destinations {
+		record := []string{
+			dest.City,
+			dest.Country,
+			formatCoordinate(dest.Latitude),
+			formatCoordinate(dest.Longitude),
+			joinList(dest.Clues),
+			joinList(dest.FunFact),
+			joinList(dest.Trivia),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// parseCoordinate parses an optional coordinate cell; empty cells mean no coordinate
+func parseCoordinate(cell string) (*float64, error) {
+	if cell == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// listEscaper escapes the list separator and backslashes in list entries
+var listEscaper = strings.NewReplacer(`\`, `\\`, ListSeparator, `\`+ListSeparator)
+
+// joinList joins entries into a CSV cell, escaping separators within them
+func joinList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = listEscaper.Replace(item)
+	}
+	return strings.Join(escaped, " "+ListSeparator+" ")
+}
+
+// splitList splits a CSV cell into its trimmed, non-empty entries, unescaping
+// separators and backslashes. Other backslashes are kept as they are.
+func splitList(cell string) []string {
+	items := []string{}
+	var item strings.Builder
+	add := func() {
+		if value := strings.TrimSpace(item.String()); value != "" {
+			items = append(items, value)
+		}
+		item.Reset()
+	}
+
+	for i := 0; i < len(cell); i++ {
+		switch {
+		case cell[i] == '\\' && i+1 < len(cell) && (cell[i+1] == '\\' || cell[i+1] == ListSeparator[0]):
+			item.WriteByte(cell[i+1])
+			i++
+		case cell[i] == ListSeparator[0]:
+			add()
+		default:
+			item.WriteByte(cell[i])
+		}
+	}
+	add()
+
+	return items
+}
@@ -0,0 +1,60 @@
+package dataset
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	latitude, longitude := 48.8566, 2.3522
+	destinations := []models.Destination{
+		{
+			City:      "Paris",
+			Country:   "France",
+			Latitude:  &latitude,
+			Longitude: &longitude,
+			Clues:     []string{"A tower of iron | lattice", `A path like C:\Louvre\`},
+			FunFact:   []string{`Ends with a backslash \`, `Escaped already \|`},
+			Trivia:    []string{"Plain"},
+		},
+		{
+			City:    "Nowhere",
+			Country: "Land",
+			Clues:   []string{"|", "||"},
+			FunFact: []string{},
+			Trivia:  []string{},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, destinations); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Read(&buf, FormatCSV)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !reflect.DeepEqual(got, destinations) {
+		t.Errorf("round trip changed the destinations\ngot:  %+v\nwant: %+v", got, destinations)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		cell string
+		want []string
+	}{
+		{"", []string{}},
+		{"a | b |  | c", []string{"a", "b", "c"}},
+		{`a \| b | c`, []string{"a | b", "c"}},
+		{`C:\path | d\\`, []string{`C:\path`, `d\`}},
+	}
+	for _, tt := range tests {
+		if got := splitList(tt.cell); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.cell, got, tt.want)
+		}
+	}
+}
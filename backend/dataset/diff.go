@@ -0,0 +1,244 @@
+package dataset
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// Key returns the natural key of a destination: its city and country, ignoring case and surrounding space
+func Key(city, country string) string {
+	return strings.ToLower(strings.TrimSpace(city)) + "|" + strings.ToLower(strings.TrimSpace(country))
+}
+
+// FieldChange describes how one field of a destination changes
+type FieldChange struct {
+	Field  string
+	Before []string
+	After  []string
+}
+
+// Update pairs an existing destination with the incoming content that replaces it
+type Update struct {
+	Before  models.Destination
+	After   models.Destination
+	Changes []FieldChange
+}
+
+// Plan is the set of changes needed to bring the database in line with a dataset
+type Plan struct {
+	Added     []models.Destination
+	Updated   []Update
+	Unchanged []models.Destination
+	Missing   []models.Destination // In the database but not in the dataset
+	Warnings  []string
+}
+
+// Diff compares the destinations in the database with an incoming dataset,
+// matching records by city and country
+func Diff(existing, incoming []models.Destination) *Plan {
+	plan := &Plan{}
+
+	// Index existing destinations; if the database already holds duplicates, the oldest one
+	// is matched and the rest are treated as missing from the dataset
+	byKey := make(map[string]models.Destination, len(existing))
+	var duplicates []models.Destination
+	for _, dest := range existing {
+		key := Key(dest.City, dest.Country)
+		if _, ok := byKey[key]; ok {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("database has duplicate %s, %s (id %d); it is only removed with --prune", dest.City, dest.Country, dest.ID))
+			duplicates = append(duplicates, dest)
+			continue
+		}
+		byKey[key] = dest
+	}
+
+	// Later entries win when the dataset repeats a destination
+	seen := make(map[string]int)
+	var ordered []models.Destination
+	for _, dest := range incoming {
+		key := Key(dest.City, dest.Country)
+		if idx, ok := seen[key]; ok {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("dataset repeats %s, %s; the later entry is used", dest.City, dest.Country))
+			ordered[idx] = dest
+			continue
+		}
+		seen[key] = len(ordered)
+		ordered = append(ordered, dest)
+	}
+
+	for _, dest := range ordered {
+		current, ok := byKey[Key(dest.City, dest.Country)]
+		if !ok {
+			plan.Added = append(plan.Added, dest)
+			continue
+		}
+
+		dest.ID = current.ID
+		// Files without coordinates (e.g. older CSV exports) keep the stored ones
+		if dest.Latitude == nil && dest.Longitude == nil {
+			dest.Latitude, dest.Longitude = current.Latitude, current.Longitude
+		}
+		changes := compare(current, dest)
+		if len(changes) == 0 {
+			plan.Unchanged = append(plan.Unchanged, current)
+			continue
+		}
+
+		plan.Updated = append(plan.Updated, Update{Before: current, After: dest, Changes: changes})
+	}
+
+	for _, dest := range byKey {
+		if _, ok := seen[Key(dest.City, dest.Country)]; !ok {
+			plan.Missing = append(plan.Missing, dest)
+		}
+	}
+	plan.Missing = append(plan.Missing, duplicates...)
+	sort.Slice(plan.Missing, func(i, j int) bool { return plan.Missing[i].ID < plan.Missing[j].ID })
+
+	return plan
+}
+
+// Changes returns what importing the plan writes: the destinations to add,
+// the new versions of updated ones and, with prune, the IDs of active
+// destinations the dataset no longer has
+func (p *Plan) Changes(prune bool) (added, updated []models.Destination, removedIDs []int) {
+	updated = make([]models.Destination, 0, len(p.Updated))
+	for _, update := range p.Updated {
+		updated = append(updated, update.After)
+	}
+
+	if prune {
+		for _, dest := range p.Missing {
+			if !dest.Retired {
+				removedIDs = append(removedIDs, dest.ID)
+			}
+		}
+	}
+
+	return p.Added, updated, removedIDs
+}
+
+// compare lists the fields that differ between two versions of a destination
+func compare(before, after models.Destination) []FieldChange {
+	var changes []FieldChange
+
+	fields := []struct {
+		name          string
+		before, after []string
+	}{
+		{"city", []string{before.City}, []string{after.City}},
+		{"country", []string{before.Country}, []string{after.Country}},
+		{"latitude", optionalField(formatCoordinate(before.Latitude)), optionalField(formatCoordinate(after.Latitude))},
+		{"longitude", optionalField(formatCoordinate(before.Longitude)), optionalField(formatCoordinate(after.Longitude))},
+		{"clues", before.Clues, after.Clues},
+		{"fun_fact", before.FunFact, after.FunFact},
+		{"trivia", before.Trivia, after.Trivia},
+	}
+
+	for _, f := range fields {
+		if !equalStrings(f.before, f.after) {
+			changes = append(changes, FieldChange{Field: f.name, Before: f.before, After: f.after})
+		}
+	}
+
+	// Importing a retired destination brings it back into rotation
+	if before.Retired && !after.Retired {
+		changes = append(changes, FieldChange{Field: "retired", Before: []string{"true"}, After: []string{"false"}})
+	}
+
+	return changes
+}
+
+// formatCoordinate renders an optional coordinate, or "" when there is none
+func formatCoordinate(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// optionalField lists a field's value for comparison, with no entry when it is empty
+func optionalField(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// equalStrings reports whether two string slices hold the same values in the same order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteDiff prints a human-readable diff of the plan. Missing destinations are
+// only listed when prune is set, since they are otherwise left alone.
+func (p *Plan) WriteDiff(w io.Writer, prune bool) {
+	for _, warning := range p.Warnings {
+		fmt.Fprintf(w, "! %s\n", warning)
+	}
+
+	for _, dest := range p.Added {
+		fmt.Fprintf(w, "+ %s, %s\n", dest.City, dest.Country)
+	}
+
+	for _, update := range p.Updated {
+		fmt.Fprintf(w, "~ %s, %s (id %d)\n", update.Before.City, update.Before.Country, update.Before.ID)
+		for _, change := range update.Changes {
+			for _, line := range change.Before {
+				if !contains(change.After, line) {
+					fmt.Fprintf(w, "    - %s: %s\n", change.Field, line)
+				}
+			}
+			for _, line := range change.After {
+				if !contains(change.Before, line) {
+					fmt.Fprintf(w, "    + %s: %s\n", change.Field, line)
+				}
+			}
+			if sameSet(change.Before, change.After) {
+				fmt.Fprintf(w, "    ~ %s: reordered\n", change.Field)
+			}
+		}
+	}
+
+	if prune {
+		for _, dest := range p.Missing {
+			fmt.Fprintf(w, "- %s, %s (id %d)\n", dest.City, dest.Country, dest.ID)
+		}
+	}
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSet reports whether two lists hold the same values regardless of order
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, item := range a {
+		if !contains(b, item) {
+			return false
+		}
+	}
+	return true
+}
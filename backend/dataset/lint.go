@@ -0,0 +1,380 @@
+package dataset
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Severity is how serious a lint issue is
+type Severity string
+
+// Lint severities; errors make the dataset unfit to load
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue codes reported by Lint
+const (
+	CodeDuplicateDestination = "duplicate_destination"
+	CodeMissingField         = "missing_field"
+	CodeEmptyEntry           = "empty_entry"
+	CodeClueRevealsAnswer    = "clue_reveals_answer"
+	CodeNearDuplicateClue    = "near_duplicate_clue"
+	CodeRepeatedEntry        = "repeated_entry"
+	CodeTooLong              = "too_long"
+	CodeInconsistentCountry  = "inconsistent_country"
+	CodeInvalidCoordinates   = "invalid_coordinates"
+	CodeMissingCoordinates   = "missing_coordinates"
+)
+
+// Issue is a single problem found in a dataset
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Index    int      `json:"index"` // Position of the destination in the dataset
+	City     string   `json:"city"`
+	Country  string   `json:"country"`
+	Field    string   `json:"field,omitempty"`
+	Entry    int      `json:"entry,omitempty"` // Position within the field, 1-based
+	Message  string   `json:"message"`
+}
+
+// LintOptions tunes the thresholds used by Lint
+type LintOptions struct {
+	MaxClueLength       int
+	MaxFactLength       int
+	SimilarityThreshold float64 // Word overlap above which two entries count as near-duplicates
+}
+
+// DefaultLintOptions are the thresholds used by the dataset command
+var DefaultLintOptions = LintOptions{
+	MaxClueLength:       200,
+	MaxFactLength:       300,
+	SimilarityThreshold: 0.8,
+}
+
+// countryAliases maps alternative country names to the spelling the dataset should use
+var countryAliases = map[string]string{
+	"us":                       "usa",
+	"united states":            "usa",
+	"united states of america": "usa",
+	"uk":                       "united kingdom",
+	"great britain":            "united kingdom",
+	"uae":                      "united arab emirates",
+	"czechia":                  "czech republic",
+	"turkiye":                  "turkey",
+	"burma":                    "myanmar",
+	"holland":                  "netherlands",
+	"the netherlands":          "netherlands",
+	"republic of korea":        "south korea",
+	"korea":                    "south korea",
+}
+
+// stopWords are ignored when comparing entries for similarity
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "in": true, "is": true,
+	"it": true, "its": true, "this": true, "to": true, "for": true, "with": true, "on": true,
+	"by": true, "as": true, "at": true, "from": true, "that": true, "city": true, "you": true,
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+// Lint checks a dataset for content problems and returns the issues found, errors first
+func Lint(destinations []models.Destination, opts LintOptions) []Issue {
+	var issues []Issue
+
+	issues = append(issues, lintDuplicates(destinations)...)
+	for i, dest := range destinations {
+		issues = append(issues, lintDestination(i, dest, opts)...)
+	}
+	issues = append(issues, lintSimilarClues(destinations, opts)...)
+	issues = append(issues, lintCountrySpellings(destinations)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity == SeverityError
+		}
+		return issues[i].Index < issues[j].Index
+	})
+
+	return issues
+}
+
+// HasErrors reports whether any issue is an error
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ClueRevealsAnswer reports whether a clue names the destination's city or country
+func ClueRevealsAnswer(clue, city, country string) bool {
+	text := " " + strings.Join(words(clue), " ") + " "
+	for _, name := range []string{city, country} {
+		nameWords := words(name)
+		if len(nameWords) == 0 {
+			continue
+		}
+		if strings.Contains(text, " "+strings.Join(nameWords, " ")+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// lintDuplicates flags destinations that repeat an earlier city and country
+func lintDuplicates(destinations []models.Destination) []Issue {
+	var issues []Issue
+
+	first := make(map[string]int)
+	for i, dest := range destinations {
+		key := Key(dest.City, dest.Country)
+		if prev, ok := first[key]; ok {
+			issues = append(issues, newIssue(SeverityError, CodeDuplicateDestination, i, dest, "", 0,
+				fmt.Sprintf("duplicates destination #%d", prev)))
+			continue
+		}
+		first[key] = i
+	}
+
+	return issues
+}
+
+// lintDestination checks a single destination's fields
+func lintDestination(i int, dest models.Destination, opts LintOptions) []Issue {
+	var issues []Issue
+
+	if strings.TrimSpace(dest.City) == "" {
+		issues = append(issues, newIssue(SeverityError, CodeMissingField, i, dest, "city", 0, "city is empty"))
+	}
+	if strings.TrimSpace(dest.Country) == "" {
+		issues = append(issues, newIssue(SeverityError, CodeMissingField, i, dest, "country", 0, "country is empty"))
+	}
+
+	// Coordinates are optional, but without them wrong answers get no distance feedback
+	switch {
+	case dest.Latitude == nil && dest.Longitude == nil:
+		issues = append(issues, newIssue(SeverityWarning, CodeMissingCoordinates, i, dest, "latitude", 0,
+			"destination has no coordinates, distance feedback is disabled for it"))
+	case dest.Latitude == nil || dest.Longitude == nil:
+		issues = append(issues, newIssue(SeverityError, CodeInvalidCoordinates, i, dest, "latitude", 0,
+			"latitude and longitude must be given together"))
+	case !geo.ValidCoordinates(*dest.Latitude, *dest.Longitude):
+		issues = append(issues, newIssue(SeverityError, CodeInvalidCoordinates, i, dest, "latitude", 0,
+			fmt.Sprintf("coordinates %g, %g are out of range", *dest.Latitude, *dest.Longitude)))
+	}
+
+	fields := []struct {
+		name      string
+		entries   []string
+		maxLength int
+	}{
+		{"clues", dest.Clues, opts.MaxClueLength},
+		{"fun_fact", dest.FunFact, opts.MaxFactLength},
+		{"trivia", dest.Trivia, opts.MaxFactLength},
+	}
+
+	for _, field := range fields {
+		if len(field.entries) == 0 {
+			issues = append(issues, newIssue(SeverityError, CodeMissingField, i, dest, field.name, 0,
+				fmt.Sprintf("%s is missing or empty", field.name)))
+			continue
+		}
+
+		for n, entry := range field.entries {
+			entry = strings.TrimSpace(entry)
+			switch {
+			case entry == "":
+				issues = append(issues, newIssue(SeverityError, CodeEmptyEntry, i, dest, field.name, n+1, "entry is empty"))
+			case field.maxLength > 0 && len([]rune(entry)) > field.maxLength:
+				issues = append(issues, newIssue(SeverityWarning, CodeTooLong, i, dest, field.name, n+1,
+					fmt.Sprintf("entry is %d characters, limit is %d", len([]rune(entry)), field.maxLength)))
+			}
+
+			if field.name == "clues" && ClueRevealsAnswer(entry, dest.City, dest.Country) {
+				issues = append(issues, newIssue(SeverityError, CodeClueRevealsAnswer, i, dest, field.name, n+1,
+					"clue names the city or country it is about"))
+			}
+		}
+	}
+
+	return issues
+}
+
+// similarEntry is an entry of a destination reduced to its significant words
+type similarEntry struct {
+	dest  int
+	field string
+	entry int
+	words map[string]bool
+}
+
+// lintSimilarClues flags clues of different destinations that share most of
+// their words, and entries of one destination that repeat each other, within
+// or across its clues, fun facts and trivia
+func lintSimilarClues(destinations []models.Destination, opts LintOptions) []Issue {
+	var entries []similarEntry
+	for i, dest := range destinations {
+		for _, field := range []struct {
+			name    string
+			entries []string
+		}{
+			{"clues", dest.Clues},
+			{"fun_fact", dest.FunFact},
+			{"trivia", dest.Trivia},
+		} {
+			for n, text := range field.entries {
+				set := make(map[string]bool)
+				for _, word := range words(text) {
+					if !stopWords[word] {
+						set[word] = true
+					}
+				}
+				if len(set) > 0 {
+					entries = append(entries, similarEntry{dest: i, field: field.name, entry: n, words: set})
+				}
+			}
+		}
+	}
+
+	var issues []Issue
+	for a := 0; a < len(entries); a++ {
+		for b := a + 1; b < len(entries); b++ {
+			ea, eb := entries[a], entries[b]
+			sameDest := ea.dest == eb.dest
+			if !sameDest {
+				if ea.field != "clues" || eb.field != "clues" {
+					continue
+				}
+				// Duplicated destinations are already reported as such
+				if Key(destinations[ea.dest].City, destinations[ea.dest].Country) ==
+					Key(destinations[eb.dest].City, destinations[eb.dest].Country) {
+					continue
+				}
+			}
+
+			score := jaccard(ea.words, eb.words)
+			if score < opts.SimilarityThreshold {
+				continue
+			}
+
+			dest := destinations[eb.dest]
+			if sameDest {
+				issues = append(issues, newIssue(SeverityWarning, CodeRepeatedEntry, eb.dest, dest, eb.field, eb.entry+1,
+					fmt.Sprintf("%.0f%% similar to %s %d of the same destination", score*100, ea.field, ea.entry+1)))
+				continue
+			}
+			other := destinations[ea.dest]
+			issues = append(issues, newIssue(SeverityWarning, CodeNearDuplicateClue, eb.dest, dest, "clues", eb.entry+1,
+				fmt.Sprintf("%.0f%% similar to clue %d of %s, %s (#%d)", score*100, ea.entry+1, other.City, other.Country, ea.dest)))
+		}
+	}
+
+	return issues
+}
+
+// lintCountrySpellings flags countries written in more than one way across the dataset
+func lintCountrySpellings(destinations []models.Destination) []Issue {
+	spellings := make(map[string]map[string]int) // canonical name -> spelling -> first index
+
+	for i, dest := range destinations {
+		canonical := canonicalCountry(dest.Country)
+		if canonical == "" {
+			continue
+		}
+		if spellings[canonical] == nil {
+			spellings[canonical] = make(map[string]int)
+		}
+		if _, ok := spellings[canonical][dest.Country]; !ok {
+			spellings[canonical][dest.Country] = i
+		}
+	}
+
+	var issues []Issue
+	for i, dest := range destinations {
+		variants := spellings[canonicalCountry(dest.Country)]
+		if len(variants) < 2 || variants[dest.Country] != i {
+			continue
+		}
+
+		// Report each spelling once, naming the others it conflicts with
+		var others []string
+		for spelling := range variants {
+			if spelling != dest.Country {
+				others = append(others, fmt.Sprintf("%q", spelling))
+			}
+		}
+		sort.Strings(others)
+
+		issues = append(issues, newIssue(SeverityWarning, CodeInconsistentCountry, i, dest, "country", 0,
+			fmt.Sprintf("country %q is also spelled %s", dest.Country, strings.Join(others, ", "))))
+	}
+
+	return issues
+}
+
+// canonicalCountry normalizes a country name so different spellings compare equal
+func canonicalCountry(country string) string {
+	name := strings.Join(words(country), " ")
+	if alias, ok := countryAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// words lowercases text, strips accents and punctuation and splits it into words
+func words(text string) []string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		folded = text
+	}
+
+	found := wordPattern.FindAllString(strings.ToLower(folded), -1)
+	for i, word := range found {
+		found[i] = strings.TrimSuffix(strings.Trim(word, "'"), "'s")
+	}
+	return found
+}
+
+// jaccard returns the overlap between two word sets, from 0 to 1
+func jaccard(a, b map[string]bool) float64 {
+	intersection := 0
+	for word := range a {
+		if b[word] {
+			intersection++
+		}
+	}
+
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// newIssue builds an issue for the destination at index i
+func newIssue(severity Severity, code string, i int, dest models.Destination, field string, entry int, message string) Issue {
+	return Issue{
+		Severity: severity,
+		Code:     code,
+		Index:    i,
+		City:     dest.City,
+		Country:  dest.Country,
+		Field:    field,
+		Entry:    entry,
+		Message:  message,
+	}
+}
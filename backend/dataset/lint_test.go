@@ -0,0 +1,31 @@
+package dataset
+
+import (
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+func TestLintRepeatedEntries(t *testing.T) {
+	destinations := []models.Destination{{
+		City:    "Santorini",
+		Country: "Greece",
+		Clues: []string{
+			"This island is famous for white buildings with blue domes overlooking a caldera.",
+			"It was formed by one of the largest volcanic eruptions in recorded history.",
+			"This island is famous for its white-washed buildings with blue domes overlooking a caldera.",
+		},
+		FunFact: []string{"The entire island is actually the rim of a massive underwater volcano."},
+		Trivia:  []string{"The entire island is the rim of a massive underwater volcano."},
+	}}
+
+	var got []string
+	for _, issue := range Lint(destinations, DefaultLintOptions) {
+		if issue.Code == CodeRepeatedEntry {
+			got = append(got, issue.Field)
+		}
+	}
+	if len(got) != 2 || got[0] != "clues" || got[1] != "trivia" {
+		t.Errorf("repeated entries reported in %v, want [clues trivia]", got)
+	}
+}
@@ -0,0 +1,74 @@
+package dataset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// DefaultPath is the dataset the server seeds and reloads from when
+// DATASET_PATH is not set
+const DefaultPath = "data/data.json"
+
+// Path returns the dataset configured by DATASET_PATH, or DefaultPath
+func Path() string {
+	if path := os.Getenv("DATASET_PATH"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// LocalesDir returns the directory holding the translation files that go
+// with the dataset at path, a locales directory next to it
+func LocalesDir(path string) string {
+	return filepath.Join(filepath.Dir(path), "locales")
+}
+
+// LocaleFiles lists the JSON translation files that go with the dataset at path
+func LocaleFiles(path string) ([]string, error) {
+	return filepath.Glob(filepath.Join(LocalesDir(path), "*.json"))
+}
+
+// ReadFile parses the destinations in the file at path, in the format its
+// extension names
+func ReadFile(path string) ([]models.Destination, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	destinations, err := Read(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return destinations, nil
+}
+
+// ReadTranslationFile parses the translation entries in the JSON file at path
+// and the locale its name gives
+func ReadTranslationFile(path string) (string, []TranslationEntry, error) {
+	locale, err := LocaleFromPath(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	entries, err := ReadTranslations(file, FormatJSON)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return locale, entries, nil
+}
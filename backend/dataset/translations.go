@@ -0,0 +1,260 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Translatable fields of a destination, as used in translation files
+const (
+	FieldCity    = "city"
+	FieldCountry = "country"
+	FieldClues   = "clues"
+	FieldFunFact = "fun_fact"
+	FieldTrivia  = "trivia"
+)
+
+// TranslationEntry is one translated item in a per-locale file. The
+// destination is identified by its English city and country, and clues and
+// facts by their English source text, so files survive reordering and stale
+// translations are detected when the source changes.
+type TranslationEntry struct {
+	City    string `json:"city" yaml:"city"`
+	Country string `json:"country" yaml:"country"`
+	Field   string `json:"field" yaml:"field"`
+	Source  string `json:"source,omitempty" yaml:"source,omitempty"`
+	Text    string `json:"text" yaml:"text"`
+}
+
+// translationCSVHeader is the column layout used for translation CSV files
+var translationCSVHeader = []string{"city", "country", "field", "source", "text"}
+
+// TranslationPlan is what importing a translation file would store
+type TranslationPlan struct {
+	Names    []models.DestinationTranslation
+	Clues    []models.TextTranslation
+	Facts    []models.TextTranslation
+	Skipped  int // Entries left untranslated
+	Warnings []string
+}
+
+// LocaleFromPath takes the locale from a file name such as locales/pt-BR.json
+func LocaleFromPath(path string) (string, error) {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	locale := i18n.Normalize(base)
+	if locale == "" {
+		return "", fmt.Errorf("cannot determine locale of %q, pass it explicitly", path)
+	}
+	return locale, nil
+}
+
+// ReadTranslations parses translation entries from r in the given format
+func ReadTranslations(r io.Reader, format Format) ([]TranslationEntry, error) {
+	var entries []TranslationEntry
+
+	switch format {
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&entries); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+	case FormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&entries); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to parse YAML: %v", err)
+		}
+	case FormatCSV:
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %v", err)
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+
+		columns := make(map[string]int)
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		for _, name := range translationCSVHeader {
+			if _, ok := columns[name]; !ok {
+				return nil, fmt.Errorf("CSV is missing the %q column", name)
+			}
+		}
+
+		for _, record := range records[1:] {
+			cell := func(name string) string {
+				if idx := columns[name]; idx < len(record) {
+					return record[idx]
+				}
+				return ""
+			}
+			entries = append(entries, TranslationEntry{
+				City:    cell("city"),
+				Country: cell("country"),
+				Field:   cell("field"),
+				Source:  cell("source"),
+				Text:    cell("text"),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return entries, nil
+}
+
+// WriteTranslations serializes translation entries to w in the given format
+func WriteTranslations(w io.Writer, format Format, entries []TranslationEntry) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(entries); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(translationCSVHeader); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := writer.Write([]string{entry.City, entry.Country, entry.Field, entry.Source, entry.Text}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// TranslationEntries lists every translatable item of the destinations with
+// its current translation, leaving Text empty where there is none. It is the
+// starting point handed to translators.
+func TranslationEntries(destinations []models.Destination, names []models.DestinationTranslation, clues, facts map[int]string) []TranslationEntry {
+	byID := make(map[int]models.DestinationTranslation, len(names))
+	for _, name := range names {
+		byID[name.DestinationID] = name
+	}
+
+	var entries []TranslationEntry
+	for _, dest := range destinations {
+		add := func(field, source, text string) {
+			entries = append(entries, TranslationEntry{City: dest.City, Country: dest.Country, Field: field, Source: source, Text: text})
+		}
+
+		add(FieldCity, dest.City, byID[dest.ID].City)
+		add(FieldCountry, dest.Country, byID[dest.ID].Country)
+		for i, clue := range dest.Clues {
+			add(FieldClues, clue, clues[dest.ClueIDs[i]])
+		}
+		for i, fact := range dest.FunFact {
+			add(FieldFunFact, fact, facts[dest.FunFactIDs[i]])
+		}
+		for i, fact := range dest.Trivia {
+			add(FieldTrivia, fact, facts[dest.TriviaIDs[i]])
+		}
+	}
+
+	return entries
+}
+
+// MatchTranslations resolves translation entries against stored destinations.
+// Entries whose destination or source text can't be found are reported as
+// warnings; entries without text are counted as skipped.
+func MatchTranslations(destinations []models.Destination, entries []TranslationEntry, locale string) *TranslationPlan {
+	plan := &TranslationPlan{}
+
+	byKey := make(map[string]models.Destination, len(destinations))
+	for _, dest := range destinations {
+		if _, ok := byKey[Key(dest.City, dest.Country)]; !ok {
+			byKey[Key(dest.City, dest.Country)] = dest
+		}
+	}
+
+	names := make(map[int]*models.DestinationTranslation)
+	var order []int
+
+	for i, entry := range entries {
+		text := strings.TrimSpace(entry.Text)
+		if text == "" {
+			plan.Skipped++
+			continue
+		}
+
+		dest, ok := byKey[Key(entry.City, entry.Country)]
+		if !ok {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("entry %d: no destination %s, %s", i+1, entry.City, entry.Country))
+			continue
+		}
+
+		field := strings.ToLower(strings.TrimSpace(entry.Field))
+		switch field {
+		case FieldCity, FieldCountry:
+			name, ok := names[dest.ID]
+			if !ok {
+				name = &models.DestinationTranslation{DestinationID: dest.ID, Locale: locale}
+				names[dest.ID] = name
+				order = append(order, dest.ID)
+			}
+			if field == FieldCity {
+				name.City = text
+			} else {
+				name.Country = text
+			}
+		case FieldClues, FieldFunFact, FieldTrivia:
+			texts, ids := dest.Clues, dest.ClueIDs
+			if field == FieldFunFact {
+				texts, ids = dest.FunFact, dest.FunFactIDs
+			} else if field == FieldTrivia {
+				texts, ids = dest.Trivia, dest.TriviaIDs
+			}
+
+			id := findText(texts, ids, entry.Source)
+			if id == 0 {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("entry %d: %s, %s has no %s %q (changed since the file was exported?)",
+					i+1, dest.City, dest.Country, field, entry.Source))
+				continue
+			}
+
+			translation := models.TextTranslation{TextID: id, Locale: locale, Text: text}
+			if field == FieldClues {
+				plan.Clues = append(plan.Clues, translation)
+			} else {
+				plan.Facts = append(plan.Facts, translation)
+			}
+		default:
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("entry %d: unknown field %q", i+1, entry.Field))
+		}
+	}
+
+	for _, id := range order {
+		plan.Names = append(plan.Names, *names[id])
+	}
+
+	return plan
+}
+
+// findText returns the ID of the entry whose text matches source, or 0
+func findText(texts []string, ids []int, source string) int {
+	source = strings.TrimSpace(source)
+	for i, text := range texts {
+		if text == source && i < len(ids) {
+			return ids[i]
+		}
+	}
+	return 0
+}
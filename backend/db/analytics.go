@@ -0,0 +1,120 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// aggregateDifficulty rebuilds a stats table from the answered multiple-choice
+// questions, grouped by the given game_questions column, and the answers kept
+// in archived_answers for compacted games, grouped by the matching archive
+// expression. Pin answers have no options to choose from and are left out.
+const aggregateDifficulty = `
+	INSERT INTO %[1]s (%[2]s, answered, correct, top_wrong_destination_id, top_wrong_count, updated_at)
+	WITH answers AS (
+		SELECT %[3]s AS item_id, correct_destination_id, selected_destination_id, 1 AS answers
+		FROM game_questions
+		WHERE is_answered = 1 AND selected_destination_id != 0 AND %[3]s IS NOT NULL
+		UNION ALL
+		SELECT %[5]s AS item_id, correct_destination_id, selected_destination_id, answers
+		FROM archived_answers
+		WHERE %[5]s IS NOT NULL
+	),
+	wrong AS (
+		SELECT item_id, selected_destination_id, SUM(answers) AS picks,
+		       ROW_NUMBER() OVER (PARTITION BY item_id ORDER BY SUM(answers) DESC, selected_destination_id) AS pick_rank
+		FROM answers
+		WHERE selected_destination_id != correct_destination_id
+		GROUP BY item_id, selected_destination_id
+	)
+	SELECT a.item_id, %[4]s SUM(a.answers),
+	       SUM(CASE WHEN a.selected_destination_id = a.correct_destination_id THEN a.answers ELSE 0 END),
+	       MAX(w.selected_destination_id), COALESCE(MAX(w.picks), 0), CURRENT_TIMESTAMP
+	FROM answers a
+	LEFT JOIN wrong w ON w.item_id = a.item_id AND w.pick_rank = 1
+	GROUP BY a.item_id
+`
+
+// RefreshDifficultyStats recomputes the destination and clue statistics in a
+// single transaction, so readers never see a half-built table
+func (d *Database) RefreshDifficultyStats() error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []struct {
+		name, key, column, extra, archived string
+	}{
+		{"destination_stats", "destination_id", "correct_destination_id", "", "correct_destination_id"},
+		{"clue_stats", "clue_id, destination_id", "clue_id", "MAX(a.correct_destination_id),", "NULLIF(clue_id, 0)"},
+	} {
+		if _, err := tx.Exec("DELETE FROM " + table.name); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(fmt.Sprintf(aggregateDifficulty, table.name, table.key, table.column, table.extra, table.archived)); err != nil {
+			return fmt.Errorf("failed to aggregate %s: %v", table.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListDestinationStats retrieves the statistics of every destination that has been answered
+func (d *Database) ListDestinationStats() ([]models.DifficultyStats, error) {
+	var stats []models.DifficultyStats
+	err := d.db.Select(&stats, `
+		SELECT s.destination_id, d.city, d.country, s.answered, s.correct,
+		       s.top_wrong_destination_id, COALESCE(w.city || ', ' || w.country, '') AS top_wrong_option,
+		       s.top_wrong_count, s.updated_at
+		FROM destination_stats s
+		JOIN destinations d ON d.id = s.destination_id
+		LEFT JOIN destinations w ON w.id = s.top_wrong_destination_id
+		ORDER BY s.destination_id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// ListClueStats retrieves the statistics of every clue that has been shown in an answered question
+func (d *Database) ListClueStats() ([]models.DifficultyStats, error) {
+	var stats []models.DifficultyStats
+	err := d.db.Select(&stats, `
+		SELECT s.destination_id, d.city, d.country, s.clue_id, c.text AS clue, s.answered, s.correct,
+		       s.top_wrong_destination_id, COALESCE(w.city || ', ' || w.country, '') AS top_wrong_option,
+		       s.top_wrong_count, s.updated_at
+		FROM clue_stats s
+		JOIN destinations d ON d.id = s.destination_id
+		JOIN destination_clues c ON c.id = s.clue_id
+		LEFT JOIN destinations w ON w.id = s.top_wrong_destination_id
+		ORDER BY s.clue_id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// DifficultyStatsUpdatedAt returns when the statistics were last aggregated, or nil if never
+func (d *Database) DifficultyStatsUpdatedAt() (*time.Time, error) {
+	// Selecting the column itself keeps its type, which MAX() would lose in SQLite
+	var updatedAt time.Time
+	err := d.db.Get(&updatedAt, "SELECT updated_at FROM destination_stats ORDER BY updated_at DESC LIMIT 1")
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedAt, nil
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// auditRow is an audit_log row with its states in their stored JSON form
+type auditRow struct {
+	models.AuditEntry
+	BeforeData *string `db:"before_data"`
+	AfterData  *string `db:"after_data"`
+}
+
+// toEntry converts the row to an audit entry model
+func (r auditRow) toEntry() models.AuditEntry {
+	entry := r.AuditEntry
+	if r.BeforeData != nil {
+		entry.Before = json.RawMessage(*r.BeforeData)
+	}
+	if r.AfterData != nil {
+		entry.After = json.RawMessage(*r.AfterData)
+	}
+	return entry
+}
+
+// recordAudit appends an entry to the audit log within the transaction making
+// the change, so the change and its entry are committed or rolled back
+// together. before and after are stored as JSON, with nil stored as NULL.
+func recordAudit(tx *txConn, actor, action, targetType string, targetID int, before, after interface{}) error {
+	states := make([]*string, 2)
+	for i, state := range []interface{}{before, after} {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit state: %v", err)
+		}
+		// A nil pointer marshals to null as well
+		if text := string(data); text != "null" {
+			states[i] = &text
+		}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO audit_log (actor, action, target_type, target_id, before_data, after_data)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, actor, action, targetType, targetID, states[0], states[1])
+	if err != nil {
+		return fmt.Errorf("failed to record %s in the audit log: %v", action, err)
+	}
+	return nil
+}
+
+// ListAuditEntries retrieves the audit log entries matching filter, newest first
+func (d *Database) ListAuditEntries(filter storage.AuditFilter) ([]models.AuditEntry, error) {
+	query := `
+		SELECT id, actor, action, target_type, target_id, before_data, after_data, created_at
+		FROM audit_log
+		WHERE 1 = 1
+	`
+	var args []interface{}
+	if filter.Actor != "" {
+		query += " AND actor = ?"
+		args = append(args, filter.Actor)
+	}
+	if filter.Action != "" {
+		query += " AND action = ?"
+		args = append(args, filter.Action)
+	}
+	if filter.TargetType != "" {
+		query += " AND target_type = ?"
+		args = append(args, filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query += " AND target_id = ?"
+		args = append(args, filter.TargetID)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, timestamp(d.db.isPostgres(), filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		query += " AND created_at < ?"
+		args = append(args, timestamp(d.db.isPostgres(), filter.Until))
+	}
+	if filter.BeforeID != 0 {
+		query += " AND id < ?"
+		args = append(args, filter.BeforeID)
+	}
+	query += " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	var rows []auditRow
+	if err := d.db.Select(&rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, row.toEntry())
+	}
+	return entries, nil
+}
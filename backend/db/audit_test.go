@@ -0,0 +1,43 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+func TestListAuditEntriesTimeBounds(t *testing.T) {
+	d := openTest(t)
+	if _, err := d.db.Exec(`
+		INSERT INTO audit_log (actor, action, target_type, target_id, created_at)
+		VALUES ('audit-test', 'create', 'user', 1, '2026-01-01 12:00:00')
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Since is inclusive and Until exclusive, whatever the bounds' time zone
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	local := time.FixedZone("UTC-5", -5*60*60)
+	for _, tt := range []struct {
+		name         string
+		since, until time.Time
+		want         int
+	}{
+		{"since the second it was created", created, time.Time{}, 1},
+		{"since in another zone", created.In(local), time.Time{}, 1},
+		{"since a second later", created.Add(time.Second), time.Time{}, 0},
+		{"until the second it was created", time.Time{}, created, 0},
+		{"until in another zone", time.Time{}, created.In(local), 0},
+		{"until a second later", time.Time{}, created.Add(time.Second), 1},
+		{"within a second", created, created.Add(time.Second), 1},
+	} {
+		entries, err := d.ListAuditEntries(storage.AuditFilter{Actor: "audit-test", Since: tt.since, Until: tt.until})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != tt.want {
+			t.Errorf("%s: got %d entries, want %d", tt.name, len(entries), tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrBackupUnsupported is returned when backing up a database that isn't a
+// SQLite file; PostgreSQL has its own tools for that
+var ErrBackupUnsupported = errors.New("online backups are only supported for SQLite databases, use pg_dump for PostgreSQL")
+
+// backupRetryDelay is how long Backup waits when a writer holds the database
+const backupRetryDelay = 50 * time.Millisecond
+
+// Backup copies the SQLite database at dsn to a new file at dest with SQLite's
+// online backup API. The copy is consistent even while the server is writing:
+// it is taken in one step under a read lock, retried while a writer holds the
+// database, until ctx is done.
+func Backup(ctx context.Context, dsn, dest string) error {
+	if IsPostgres(dsn) {
+		return ErrBackupUnsupported
+	}
+	// Opening a missing file would create an empty database and back that up
+	if _, err := os.Stat(dsn); err != nil {
+		return fmt.Errorf("no database to back up: %v", err)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+
+	source, err := sql.Open(dialectSQLite, dsn)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := sql.Open(dialectSQLite, dest)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	sourceConn, err := source.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer sourceConn.Close()
+
+	targetConn, err := target.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer targetConn.Close()
+
+	return targetConn.Raw(func(targetDriver interface{}) error {
+		return sourceConn.Raw(func(sourceDriver interface{}) error {
+			backup, err := targetDriver.(*sqlite3.SQLiteConn).Backup("main", sourceDriver.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+
+			for {
+				// Step reports busy and locked databases as not done, without an error
+				done, err := backup.Step(-1)
+				if err != nil {
+					backup.Close()
+					return err
+				}
+				if done {
+					return backup.Finish()
+				}
+
+				select {
+				case <-ctx.Done():
+					backup.Close()
+					return ctx.Err()
+				case <-time.After(backupRetryDelay):
+				}
+			}
+		})
+	})
+}
+
+// CheckIntegrity runs PRAGMA integrity_check on the SQLite file at path
+// without changing it, and returns the problems it finds as an error
+func CheckIntegrity(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	handle, err := sql.Open(dialectSQLite, "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	rows, err := handle.Query("PRAGMA integrity_check")
+	if err != nil {
+		return fmt.Errorf("integrity check failed: %v", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return err
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("integrity check failed: %v", err)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
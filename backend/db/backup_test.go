@@ -0,0 +1,96 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+func TestBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "globetrotter.db")
+	database := open(t, path)
+	if err := database.SaveUser(models.ActorSystem, models.User{Username: "backed-up"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The database is open, as it is while the server runs
+	dest := filepath.Join(dir, "copy.db")
+	if err := db.Backup(context.Background(), path, dest); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if err := db.CheckIntegrity(dest); err != nil {
+		t.Errorf("CheckIntegrity of the copy: %v", err)
+	}
+	copied := open(t, dest)
+	if _, err := copied.GetUserByUsername("backed-up"); err != nil {
+		t.Errorf("user is missing from the copy: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		dsn, dest string
+		want      error
+	}{
+		{"to an existing file", path, dest, nil},
+		{"of a missing database", filepath.Join(dir, "missing.db"), filepath.Join(dir, "missing-copy.db"), nil},
+		{"of PostgreSQL", "postgres://localhost/globetrotter", filepath.Join(dir, "postgres.db"), db.ErrBackupUnsupported},
+	} {
+		err := db.Backup(context.Background(), tt.dsn, tt.dest)
+		if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Errorf("Backup %s: got %v, want an error", tt.name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("Backup of a missing database created it")
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "globetrotter.db")
+	open(t, path).Close()
+	valid, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		content []byte // Nil leaves the file missing
+		ok      bool
+	}{
+		{"valid", valid, true},
+		{"truncated", valid[:len(valid)/2], false},
+		{"not a database", []byte("not a database, just some text that is long enough to have a header"), false},
+		{"empty", []byte{}, true}, // SQLite treats an empty file as an empty database
+		{"missing", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "check.db")
+			if tt.content != nil {
+				if err := os.WriteFile(file, tt.content, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := db.CheckIntegrity(file)
+			if tt.ok && err != nil {
+				t.Errorf("got %v, want no problems", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("got no problems")
+			}
+			if tt.content != nil {
+				after, _ := os.ReadFile(file)
+				if string(after) != string(tt.content) {
+					t.Error("CheckIntegrity changed the file")
+				}
+			}
+		})
+	}
+}
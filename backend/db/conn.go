@@ -0,0 +1,144 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Drivers of the supported dialects
+const (
+	dialectSQLite   = "sqlite3"
+	dialectPostgres = "postgres"
+)
+
+// querier runs read queries; both conn and txConn implement it, so loaders can
+// read through the database or within a transaction
+type querier interface {
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+}
+
+// conn is a database handle whose queries are written with ? placeholders
+// and rebound to the driver's style, so the same SQL runs on every dialect
+type conn struct {
+	*sqlx.DB
+}
+
+// Exec executes a query without returning any rows
+func (c *conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.DB.Exec(c.Rebind(query), args...)
+}
+
+// Query executes a query that returns rows
+func (c *conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.DB.Query(c.Rebind(query), args...)
+}
+
+// QueryRow executes a query that returns at most one row
+func (c *conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.DB.QueryRow(c.Rebind(query), args...)
+}
+
+// Get scans a single row into dest
+func (c *conn) Get(dest interface{}, query string, args ...interface{}) error {
+	return c.DB.Get(dest, c.Rebind(query), args...)
+}
+
+// Select scans all rows into dest
+func (c *conn) Select(dest interface{}, query string, args ...interface{}) error {
+	return c.DB.Select(dest, c.Rebind(query), args...)
+}
+
+// Begin starts a transaction
+func (c *conn) Begin() (*txConn, error) {
+	tx, err := c.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	return &txConn{Tx: tx}, nil
+}
+
+// isPostgres reports whether the handle talks to PostgreSQL
+func (c *conn) isPostgres() bool {
+	return c.DriverName() == dialectPostgres
+}
+
+// hasTable reports whether the database has the given table
+func (c *conn) hasTable(table string) (bool, error) {
+	query := "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	if c.isPostgres() {
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?"
+	}
+	var count int
+	err := c.QueryRow(query, table).Scan(&count)
+	return count > 0, err
+}
+
+// hasColumn reports whether a table has the given column
+func (c *conn) hasColumn(table, column string) (bool, error) {
+	query := "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+	if c.isPostgres() {
+		query = "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?"
+	}
+	var count int
+	err := c.QueryRow(query, table, column).Scan(&count)
+	return count > 0, err
+}
+
+// txConn is a transaction that rebinds its queries like conn
+type txConn struct {
+	*sqlx.Tx
+}
+
+// Exec executes a query without returning any rows
+func (tx *txConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.Exec(tx.Rebind(query), args...)
+}
+
+// Query executes a query that returns rows
+func (tx *txConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.Query(tx.Rebind(query), args...)
+}
+
+// QueryRow executes a query that returns at most one row
+func (tx *txConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRow(tx.Rebind(query), args...)
+}
+
+// Get scans a single row into dest
+func (tx *txConn) Get(dest interface{}, query string, args ...interface{}) error {
+	return tx.Tx.Get(dest, tx.Rebind(query), args...)
+}
+
+// Select scans all rows into dest
+func (tx *txConn) Select(dest interface{}, query string, args ...interface{}) error {
+	return tx.Tx.Select(dest, tx.Rebind(query), args...)
+}
+
+// isPostgres reports whether the transaction runs on PostgreSQL
+func (tx *txConn) isPostgres() bool {
+	return tx.DriverName() == dialectPostgres
+}
+
+// sqliteTimestampLayout is how SQLite stores CURRENT_TIMESTAMP, in UTC
+const sqliteTimestampLayout = "2006-01-02 15:04:05"
+
+// timestamp converts t to the value timestamp columns are compared with.
+// SQLite stores timestamps as text, which only compares correctly with text
+// in the same layout; PostgreSQL compares times.
+func timestamp(postgres bool, t time.Time) interface{} {
+	if postgres {
+		return t.UTC()
+	}
+	return t.UTC().Format(sqliteTimestampLayout)
+}
+
+// flag converts a boolean to the 0 or 1 stored in flag columns such as retired
+func flag(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -4,170 +4,132 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/shubhsherl/globetrotter/backend/dataset"
 	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
 )
 
-var DB *sql.DB
-var DBx *sqlx.DB
+// Database is the SQL storage backend, running on SQLite or PostgreSQL
+type Database struct {
+	db *conn
+}
+
+var _ storage.Store = (*Database)(nil)
+
+// DefaultPath is the SQLite database used when none is configured
+const DefaultPath = "./data/globetrotter.db"
+
+// DSN returns the database configured by the environment: DATABASE_URL if
+// set, otherwise the SQLite file at DB_PATH or DefaultPath
+func DSN() string {
+	if dsn := os.Getenv("DATABASE_URL"); dsn != "" {
+		return dsn
+	}
+	if dbPath := os.Getenv("DB_PATH"); dbPath != "" {
+		return dbPath
+	}
+	return DefaultPath
+}
+
+// Open opens the database named by dsn, applying any pending migrations and
+// seeding destinations when it is empty. A postgres:// or postgresql:// URL
+// selects PostgreSQL; anything else is the path of a SQLite file. It fails
+// with ErrDatabaseAhead if the database was migrated by a newer build.
+func Open(dsn string) (*Database, error) {
+	c, err := connect(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Database{db: c}
+	if err := d.init(); err != nil {
+		c.Close()
+		return nil, err
+	}
 
-// InitDB initializes the SQLite database
-func InitDB(dbPath string) error {
+	log.Println("Database initialized successfully")
+	return d, nil
+}
+
+// connect opens a connection to the database named by dsn
+func connect(dsn string) (*conn, error) {
+	if IsPostgres(dsn) {
+		return connectPostgres(dsn)
+	}
+	return connectSQLite(dsn)
+}
+
+// connectSQLite opens the SQLite database at dbPath, creating its directory if needed
+func connectSQLite(dbPath string) (*conn, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create database directory: %v", err)
+		return nil, fmt.Errorf("failed to create database directory: %v", err)
 	}
 
 	// Open database connection
-	var err error
-	DB, err = sql.Open("sqlite3", dbPath)
+	handle, err := sqlx.Open(dialectSQLite, dbPath)
 	if err != nil {
-		return fmt.Errorf("failed to open database: %v", err)
+		return nil, fmt.Errorf("failed to open database: %v", err)
 	}
 
-	// Initialize sqlx
-	DBx = sqlx.NewDb(DB, "sqlite3")
+	return &conn{DB: handle}, nil
+}
 
-	// Create tables if they don't exist
-	if err := createTables(); err != nil {
-		return fmt.Errorf("failed to create tables: %v", err)
+// init migrates the schema to the latest version and seeds an empty database
+func (d *Database) init() error {
+	m, err := newMigrator(d.db)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil {
+		return err
 	}
 
 	// Check if destinations table is empty, if so, seed data
 	var count int
-	err = DB.QueryRow("SELECT COUNT(*) FROM destinations").Scan(&count)
+	err = d.db.QueryRow("SELECT COUNT(*) FROM destinations").Scan(&count)
 	if err != nil || count == 0 {
-		if err := seedDestinations(); err != nil {
+		if err := d.seedDestinations(); err != nil {
 			return fmt.Errorf("failed to seed destinations: %v", err)
 		}
+		if err := d.seedTranslations(); err != nil {
+			return fmt.Errorf("failed to seed translations: %v", err)
+		}
 	}
 
-	log.Println("Database initialized successfully")
 	return nil
 }
 
-// createTables creates the necessary tables in the database
-func createTables() error {
-	// Create destinations table
-	_, err := DB.Exec(`
-		CREATE TABLE IF NOT EXISTS destinations (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			city TEXT NOT NULL,
-			country TEXT NOT NULL,
-			clues TEXT NOT NULL,
-			fun_facts TEXT NOT NULL,
-			trivia TEXT NOT NULL
-		)
-	`)
+// seedDestinations loads the dataset at dataset.Path and inserts it into the database
+func (d *Database) seedDestinations() error {
+	path := dataset.Path()
+	destinations, err := dataset.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	// Create users table
-	_, err = DB.Exec(`
-		CREATE TABLE IF NOT EXISTS users (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			username TEXT UNIQUE NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	if err != nil {
-		return err
-	}
-
-	// Create games table
-	_, err = DB.Exec(`
-		CREATE TABLE IF NOT EXISTS games (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			user_id INTEGER NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			total_questions INTEGER DEFAULT 5,
-			total_correct INTEGER DEFAULT 0,
-			total_incorrect INTEGER DEFAULT 0,
-			total_answered INTEGER DEFAULT 0,
-			FOREIGN KEY (user_id) REFERENCES users (id)
-		)
-	`)
-	if err != nil {
-		return err
-	}
-
-	// Create game_questions table
-	_, err = DB.Exec(`
-		CREATE TABLE IF NOT EXISTS game_questions (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			game_id INTEGER NOT NULL,
-			question TEXT NOT NULL,
-			options TEXT NOT NULL,
-			correct_destination_id INTEGER NOT NULL,
-			selected_destination_id INTEGER DEFAULT 0,
-			is_answered INTEGER DEFAULT 0,
-			FOREIGN KEY (game_id) REFERENCES games (id),
-			FOREIGN KEY (correct_destination_id) REFERENCES destinations (id)
-		)
-	`)
-
-	return err
-}
-
-// seedDestinations loads destination data from JSON file and inserts into database
-func seedDestinations() error {
-	// Read JSON file
-	data, err := ioutil.ReadFile("data/data.json")
-	if err != nil {
-		return fmt.Errorf("failed to read data.json: %v", err)
-	}
-
-	// Parse JSON
-	var destinations []models.Destination
-	if err := json.Unmarshal(data, &destinations); err != nil {
-		return fmt.Errorf("failed to parse data.json: %v", err)
+	// Surface content problems instead of loading them silently
+	for _, issue := range dataset.Lint(destinations, dataset.DefaultLintOptions) {
+		log.Printf("%s %s: %s, %s: %s (%s)", filepath.Base(path), issue.Severity, issue.City, issue.Country, issue.Message, issue.Code)
 	}
 
 	// Begin transaction
-	tx, err := DB.Begin()
+	tx, err := d.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Prepare statement
-	stmt, err := tx.Prepare(`
-		INSERT INTO destinations (city, country, clues, fun_facts, trivia)
-		VALUES (?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
 	// Insert destinations
 	for _, dest := range destinations {
-		// Convert slices to JSON strings
-		cluesJSON, err := json.Marshal(dest.Clues)
-		if err != nil {
-			return err
-		}
-
-		funFactsJSON, err := json.Marshal(dest.FunFact)
-		if err != nil {
-			return err
-		}
-
-		triviaJSON, err := json.Marshal(dest.Trivia)
-		if err != nil {
-			return err
-		}
-
-		_, err = stmt.Exec(dest.City, dest.Country, cluesJSON, funFactsJSON, triviaJSON)
-		if err != nil {
+		if _, err := insertDestination(tx, models.ActorSystem, dest); err != nil {
 			return err
 		}
 	}
@@ -181,22 +143,36 @@ func seedDestinations() error {
 	return nil
 }
 
-// Database handles database operations
-type Database struct {
-	db  *sql.DB
-	dbx *sqlx.DB
-}
+// seedTranslations loads the per-locale files next to the dataset, if any
+func (d *Database) seedTranslations() error {
+	paths, err := dataset.LocaleFiles(dataset.Path())
+	if err != nil || len(paths) == 0 {
+		return err
+	}
 
-// NewDatabase creates a new database connection
-func NewDatabase(dbPath string) (*Database, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	destinations, err := d.ListDestinations(false)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	dbx := sqlx.NewDb(db, "sqlite3")
+	for _, path := range paths {
+		locale, entries, err := dataset.ReadTranslationFile(path)
+		if err != nil {
+			return err
+		}
 
-	return &Database{db: db, dbx: dbx}, nil
+		plan := dataset.MatchTranslations(destinations, entries, locale)
+		for _, warning := range plan.Warnings {
+			log.Printf("%s: %s", path, warning)
+		}
+		if err := d.SaveTranslations(plan.Names, plan.Clues, plan.Facts); err != nil {
+			return err
+		}
+
+		log.Printf("Seeded %s translations for %d destinations", locale, len(plan.Names))
+	}
+
+	return nil
 }
 
 // Close closes the database connection
@@ -204,60 +180,21 @@ func (d *Database) Close() error {
 	return d.db.Close()
 }
 
-// GetAllDestinations retrieves all destinations from the database
+// GetAllDestinations retrieves all active destinations from the database
 func (d *Database) GetAllDestinations() ([]models.Destination, error) {
-	type DestinationWithJSON struct {
-		ID           int    `db:"id"`
-		City         string `db:"city"`
-		Country      string `db:"country"`
-		CluesJSON    string `db:"clues"`
-		FunFactsJSON string `db:"fun_facts"`
-		TriviaJSON   string `db:"trivia"`
-	}
-
-	var destinationsWithJSON []DestinationWithJSON
-
-	err := d.dbx.Select(&destinationsWithJSON, `
-		SELECT id, city, country, clues, fun_facts, trivia
-		FROM destinations
-	`)
-
-	if err != nil {
-		return nil, err
-	}
-
-	var destinations []models.Destination
-	for _, d := range destinationsWithJSON {
-		dest := models.Destination{
-			ID:      d.ID,
-			City:    d.City,
-			Country: d.Country,
-		}
-
-		// Parse JSON strings
-		if err := json.Unmarshal([]byte(d.CluesJSON), &dest.Clues); err != nil {
-			return nil, fmt.Errorf("failed to parse clues: %v", err)
-		}
-
-		if err := json.Unmarshal([]byte(d.FunFactsJSON), &dest.FunFact); err != nil {
-			return nil, fmt.Errorf("failed to parse fun facts: %v", err)
-		}
-
-		if err := json.Unmarshal([]byte(d.TriviaJSON), &dest.Trivia); err != nil {
-			return nil, fmt.Errorf("failed to parse trivia: %v", err)
-		}
-
-		destinations = append(destinations, dest)
-	}
-
-	return destinations, nil
+	return d.ListDestinations(false)
 }
 
 // GetUserByUsername retrieves a user by username
 func (d *Database) GetUserByUsername(username string) (models.User, error) {
+	return getUser(d.db, username)
+}
+
+// getUser loads a user by username through q
+func getUser(q querier, username string) (models.User, error) {
 	var user models.User
-	err := d.dbx.Get(&user, `
-		SELECT id, username, created_at
+	err := q.Get(&user, `
+		SELECT id, username, locale, created_at
 		FROM users
 		WHERE username = ?
 	`, username)
@@ -265,73 +202,128 @@ func (d *Database) GetUserByUsername(username string) (models.User, error) {
 	return user, err
 }
 
-// SaveUser saves a user to the database
-func (d *Database) SaveUser(user models.User) error {
-	// Check if user exists
-	var count int
-	err := d.db.QueryRow("SELECT COUNT(*) FROM users WHERE username = ?", user.Username).Scan(&count)
+// SaveUser saves a user to the database and records the change in the audit log
+func (d *Database) SaveUser(actor string, user models.User) error {
+	tx, err := d.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	// Check if user exists
+	before, err := getUser(tx, user.Username)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
 
-	if count > 0 {
+	action := models.AuditUserCreate
+	if err == nil {
+		if user.CreatedAt == "" {
+			return nil
+		}
 		// Update existing user
-		_, err = d.db.Exec("UPDATE users SET created_at = ? WHERE username = ?",
+		action = models.AuditUserUpdate
+		_, err = tx.Exec("UPDATE users SET created_at = ? WHERE username = ?",
 			user.CreatedAt, user.Username)
+	} else if user.CreatedAt == "" {
+		// Insert new user, stamped by the database
+		_, err = tx.Exec("INSERT INTO users (username, locale) VALUES (?, ?)",
+			user.Username, user.Locale)
 	} else {
 		// Insert new user
-		_, err = d.db.Exec("INSERT INTO users (username, created_at) VALUES (?, ?)",
-			user.Username, user.CreatedAt)
+		_, err = tx.Exec("INSERT INTO users (username, locale, created_at) VALUES (?, ?, ?)",
+			user.Username, user.Locale, user.CreatedAt)
+	}
+	if err != nil {
+		return err
 	}
 
-	return err
+	if err := auditUser(tx, actor, action, user.Username, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetDB returns the database instance
-func GetDB() *Database {
-	return &Database{db: DB, dbx: DBx}
+// SetUserLocale changes a user's preferred language; an empty locale clears it
+func (d *Database) SetUserLocale(actor, username, locale string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getUser(tx, username)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE users SET locale = ? WHERE username = ?", locale, username); err != nil {
+		return err
+	}
+
+	if err := auditUser(tx, actor, models.AuditUserUpdate, username, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// CreateGame creates a new game for a user
-func (d *Database) CreateGame(userID int, totalQuestions int) (int, error) {
-	result, err := d.db.Exec(`
-		INSERT INTO games (user_id, total_questions)
-		VALUES (?, ?)
-	`, userID, totalQuestions)
+// auditUser records a change to the named user in the audit log. before is
+// the zero user when the change created it.
+func auditUser(tx *txConn, actor, action, username string, before models.User) error {
+	after, err := getUser(tx, username)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	gameID, err := result.LastInsertId()
+	var previous *models.User
+	if before.ID != 0 {
+		previous = &before
+	}
+	return recordAudit(tx, actor, action, models.AuditTargetUser, after.ID, previous, after)
+}
+
+// CreateGame creates a new game for a user with the given scoring mode
+func (d *Database) CreateGame(userID int, totalQuestions int, scoring string) (int, error) {
+	var gameID int
+	err := d.db.QueryRow(`
+		INSERT INTO games (user_id, total_questions, scoring)
+		VALUES (?, ?, ?)
+		RETURNING id
+	`, userID, totalQuestions, scoring).Scan(&gameID)
 	if err != nil {
 		return 0, err
 	}
 
-	return int(gameID), nil
+	return gameID, nil
 }
 
-// AddGameQuestion adds a question to a game
-func (d *Database) AddGameQuestion(gameID int, question string, optionDestinationIDs []int, correctDestinationID int) (int, error) {
+// AddGameQuestion adds a question to a game. clueID records which clue was
+// shown and may be 0 when the question isn't based on a clue.
+func (d *Database) AddGameQuestion(gameID int, question string, optionDestinationIDs []int, correctDestinationID int, clueID int) (int, error) {
 	// Convert options to JSON
 	optionsJSON, err := json.Marshal(optionDestinationIDs)
 	if err != nil {
 		return 0, err
 	}
 
-	result, err := d.db.Exec(`
-		INSERT INTO game_questions (game_id, question, options, correct_destination_id)
-		VALUES (?, ?, ?, ?)
-	`, gameID, question, string(optionsJSON), correctDestinationID)
-	if err != nil {
-		return 0, err
+	var clue sql.NullInt64
+	if clueID != 0 {
+		clue = sql.NullInt64{Int64: int64(clueID), Valid: true}
 	}
 
-	questionID, err := result.LastInsertId()
+	var questionID int
+	err = d.db.QueryRow(`
+		INSERT INTO game_questions (game_id, question, options, correct_destination_id, clue_id)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`, gameID, question, string(optionsJSON), correctDestinationID, clue).Scan(&questionID)
 	if err != nil {
 		return 0, err
 	}
 
-	return int(questionID), nil
+	return questionID, nil
 }
 
 // GetNextQuestion gets the next unanswered question for a game
@@ -343,9 +335,11 @@ func (d *Database) GetNextQuestion(gameID int) (*models.GameQuestionDetail, erro
 
 	var questionWithJSON QuestionWithOptionsJSON
 
-	err := d.dbx.Get(&questionWithJSON, `
+	err := d.db.Get(&questionWithJSON, `
 		SELECT id, game_id, question, options as options_json, 
-		       correct_destination_id, selected_destination_id, is_answered
+		       correct_destination_id, selected_destination_id, is_answered,
+		       COALESCE(clue_id, 0) AS clue_id, points, distance_km,
+		       pin_latitude, pin_longitude, correct_latitude, correct_longitude
 		FROM game_questions
 		WHERE game_id = ? AND is_answered = 0
 		ORDER BY id ASC
@@ -373,10 +367,12 @@ func (d *Database) GetQuestionByID(gameID, questionID int) (*models.GameQuestion
 
 	var questionWithJSON QuestionWithOptionsJSON
 
-	err := d.dbx.Get(&questionWithJSON, `
+	err := d.db.Get(&questionWithJSON, `
 		SELECT id, game_id, question, options as options_json, 
 		       correct_destination_id, selected_destination_id, 
-		       is_answered
+		       is_answered, COALESCE(clue_id, 0) AS clue_id,
+		       points, distance_km, pin_latitude, pin_longitude,
+		       correct_latitude, correct_longitude
 		FROM game_questions
 		WHERE game_id = ? AND id = ?
 	`, gameID, questionID)
@@ -393,60 +389,74 @@ func (d *Database) GetQuestionByID(gameID, questionID int) (*models.GameQuestion
 	return &questionWithJSON.GameQuestionDetail, nil
 }
 
-// SubmitAnswer submits an answer for a question
-func (d *Database) SubmitAnswer(gameID, questionID int, selectedDestinationID int) error {
-	// Update the question
-	_, err := d.db.Exec(`
-		UPDATE game_questions
-		SET selected_destination_id = ?, is_answered = 1
-		WHERE id = ? AND game_id = ?
-	`, selectedDestinationID, questionID, gameID)
-
+// SubmitAnswer records an answer for a question and adds it to the game's
+// stats in a single transaction. Only the first answer to a question is
+// recorded; later ones get storage.ErrAlreadyAnswered.
+func (d *Database) SubmitAnswer(gameID, questionID int, answer storage.Answer) error {
+	tx, err := d.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	// Get the correct destination ID
-	var correctDestinationID int
-	err = d.db.QueryRow(`
-		SELECT correct_destination_id
-		FROM game_questions
-		WHERE id = ? AND game_id = ?
-	`, questionID, gameID).Scan(&correctDestinationID)
-
+	// Update the question, unless another request answered it first
+	result, err := tx.Exec(`
+		UPDATE game_questions
+		SET selected_destination_id = ?, is_answered = 1, points = ?, distance_km = ?,
+		    pin_latitude = ?, pin_longitude = ?, correct_latitude = ?, correct_longitude = ?
+		WHERE id = ? AND game_id = ? AND is_answered = 0
+	`, answer.SelectedDestinationID, answer.Points, answer.DistanceKm,
+		answer.PinLatitude, answer.PinLongitude, answer.CorrectLatitude, answer.CorrectLongitude,
+		questionID, gameID)
 	if err != nil {
 		return err
 	}
 
-	// Check if the answer is correct
-	isCorrect := selectedDestinationID == correctDestinationID
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		var exists int
+		err := tx.QueryRow("SELECT COUNT(*) FROM game_questions WHERE id = ? AND game_id = ?", questionID, gameID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return sql.ErrNoRows
+		}
+		return storage.ErrAlreadyAnswered
+	}
 
 	// Update the game stats
-	if isCorrect {
-		_, err = d.db.Exec(`
+	if answer.Correct {
+		_, err = tx.Exec(`
 			UPDATE games
-			SET total_correct = total_correct + 1, total_answered = total_answered + 1
+			SET total_correct = total_correct + 1, total_answered = total_answered + 1, score = score + ?
 			WHERE id = ?
-		`, gameID)
+		`, answer.Points, gameID)
 	} else {
-		_, err = d.db.Exec(`
+		_, err = tx.Exec(`
 			UPDATE games
-			SET total_incorrect = total_incorrect + 1, total_answered = total_answered + 1
+			SET total_incorrect = total_incorrect + 1, total_answered = total_answered + 1, score = score + ?
 			WHERE id = ?
-		`, gameID)
+		`, answer.Points, gameID)
+	}
+	if err != nil {
+		return err
 	}
 
-	return err
+	return tx.Commit()
 }
 
 // GetGameResult gets the result of a game
 func (d *Database) GetGameResult(gameID int) (*models.GameResult, error) {
 	// Get game info
 	var game models.Game
-	err := d.dbx.Get(&game, `
+	err := d.db.Get(&game, `
 		SELECT id, user_id, total_questions, 
 		       total_correct, total_incorrect, 
-		       total_answered, created_at
+		       total_answered, created_at, scoring, score, compacted_at
 		FROM games
 		WHERE id = ?
 	`, gameID)
@@ -463,10 +473,12 @@ func (d *Database) GetGameResult(gameID int) (*models.GameResult, error) {
 
 	var questionsWithJSON []QuestionWithOptionsJSON
 
-	err = d.dbx.Select(&questionsWithJSON, `
+	err = d.db.Select(&questionsWithJSON, `
 		SELECT id, game_id, question, options as options_json, 
 		       correct_destination_id, selected_destination_id, 
-		       is_answered
+		       is_answered, COALESCE(clue_id, 0) AS clue_id,
+		       points, distance_km, pin_latitude, pin_longitude,
+		       correct_latitude, correct_longitude
 		FROM game_questions
 		WHERE game_id = ?
 	`, gameID)
@@ -475,8 +487,8 @@ func (d *Database) GetGameResult(gameID int) (*models.GameResult, error) {
 		return nil, err
 	}
 
-	// Parse options JSON for each question
-	var questions []models.GameQuestionDetail
+	// Parse options JSON for each question; compacted games have none left
+	questions := make([]models.GameQuestionDetail, 0, len(questionsWithJSON))
 	for _, q := range questionsWithJSON {
 		if err := json.Unmarshal([]byte(q.OptionsJSON), &q.OptionDestinationIDs); err != nil {
 			return nil, err
@@ -494,6 +506,9 @@ func (d *Database) GetGameResult(gameID int) (*models.GameResult, error) {
 		TotalQuestions: game.TotalQuestions,
 		TotalCorrect:   game.TotalCorrect,
 		TotalIncorrect: game.TotalIncorrect,
+		Scoring:        game.Scoring,
+		Score:          game.Score,
+		Compacted:      game.CompactedAt != nil,
 		Questions:      questions,
 	}, nil
 }
@@ -526,58 +541,13 @@ func (d *Database) GetUserIDByUsername(username string) (int, error) {
 	return userID, err
 }
 
-// GetDestinationByID gets a destination by its ID
-func (d *Database) GetDestinationByID(destinationID int) (*models.Destination, error) {
-	type DestinationWithJSON struct {
-		ID           int    `db:"id"`
-		City         string `db:"city"`
-		Country      string `db:"country"`
-		CluesJSON    string `db:"clues"`
-		FunFactsJSON string `db:"fun_facts"`
-		TriviaJSON   string `db:"trivia"`
-	}
-
-	var destWithJSON DestinationWithJSON
-
-	err := d.dbx.Get(&destWithJSON, `
-		SELECT id, city, country, clues, fun_facts, trivia
-		FROM destinations
-		WHERE id = ?
-	`, destinationID)
-
-	if err != nil {
-		return nil, err
-	}
-
-	dest := &models.Destination{
-		ID:      destWithJSON.ID,
-		City:    destWithJSON.City,
-		Country: destWithJSON.Country,
-	}
-
-	// Parse JSON strings
-	if err := json.Unmarshal([]byte(destWithJSON.CluesJSON), &dest.Clues); err != nil {
-		return nil, fmt.Errorf("failed to parse clues: %v", err)
-	}
-
-	if err := json.Unmarshal([]byte(destWithJSON.FunFactsJSON), &dest.FunFact); err != nil {
-		return nil, fmt.Errorf("failed to parse fun facts: %v", err)
-	}
-
-	if err := json.Unmarshal([]byte(destWithJSON.TriviaJSON), &dest.Trivia); err != nil {
-		return nil, fmt.Errorf("failed to parse trivia: %v", err)
-	}
-
-	return dest, nil
-}
-
 // GetGame gets a game by ID
 func (d *Database) GetGame(gameID int) (*models.Game, error) {
 	var game models.Game
-	err := d.dbx.Get(&game, `
+	err := d.db.Get(&game, `
 		SELECT id, user_id, total_questions, 
 		       total_correct, total_incorrect, 
-		       total_answered, created_at
+		       total_answered, created_at, scoring, score, compacted_at
 		FROM games
 		WHERE id = ?
 	`, gameID)
@@ -592,8 +562,8 @@ func (d *Database) GetGame(gameID int) (*models.Game, error) {
 // GetUserByID gets a user by ID
 func (d *Database) GetUserByID(userID int) (*models.User, error) {
 	var user models.User
-	err := d.dbx.Get(&user, `
-		SELECT id, username, created_at
+	err := d.db.Get(&user, `
+		SELECT id, username, locale, created_at
 		FROM users
 		WHERE id = ?
 	`, userID)
@@ -0,0 +1,29 @@
+package db_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+	"github.com/shubhsherl/globetrotter/backend/storage/storagetest"
+)
+
+// open opens the database named by dsn for a test, seeded from the repository's dataset
+func open(t *testing.T, dsn string) *db.Database {
+	t.Helper()
+	t.Setenv("DATASET_PATH", filepath.Join("..", "data", "data.json"))
+
+	database, err := db.Open(dsn)
+	if err != nil {
+		t.Fatalf("opening %s: %v", db.Redact(dsn), err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestSQLiteStore(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Store {
+		return open(t, filepath.Join(t.TempDir(), "globetrotter.db"))
+	})
+}
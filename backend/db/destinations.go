@@ -0,0 +1,630 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// submitterColumn selects the username of the player whose approved
+// submission added a destination or clue row, or an empty string
+const submitterColumn = `COALESCE((
+	SELECT u.username FROM submissions s JOIN users u ON u.id = s.user_id WHERE s.id = submission_id
+), '') AS submitted_by`
+
+// Kinds of rows stored in destination_facts
+const (
+	factKindFunFact = "fun_fact"
+	factKindTrivia  = "trivia"
+)
+
+// ListDestinations retrieves destinations, optionally including retired ones
+func (d *Database) ListDestinations(includeRetired bool) ([]models.Destination, error) {
+	return listDestinations(d.db, includeRetired)
+}
+
+// listDestinations loads destinations with their content through q
+func listDestinations(q querier, includeRetired bool) ([]models.Destination, error) {
+	query := `
+		SELECT id, city, country, retired, latitude, longitude, ` + submitterColumn + `
+		FROM destinations
+	`
+	if !includeRetired {
+		query += " WHERE retired = 0"
+	}
+	query += " ORDER BY id ASC"
+
+	var destinations []models.Destination
+	if err := q.Select(&destinations, query); err != nil {
+		return nil, err
+	}
+
+	if err := loadContent(q, destinations); err != nil {
+		return nil, err
+	}
+
+	return destinations, nil
+}
+
+// DestinationsVersion returns a counter that database triggers bump on every
+// change to destinations, clues and facts, whichever process makes it
+func (d *Database) DestinationsVersion() (int64, error) {
+	var version int64
+	err := d.db.QueryRow("SELECT version FROM destinations_version").Scan(&version)
+	return version, err
+}
+
+// GetDestinationByID gets a destination by its ID, including retired ones
+func (d *Database) GetDestinationByID(destinationID int) (*models.Destination, error) {
+	return getDestination(d.db, destinationID)
+}
+
+// getDestination loads a destination and its active content through q
+func getDestination(q querier, destinationID int) (*models.Destination, error) {
+	var dest models.Destination
+
+	err := q.Get(&dest, `
+		SELECT id, city, country, retired, latitude, longitude, `+submitterColumn+`
+		FROM destinations
+		WHERE id = ?
+	`, destinationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	destinations := []models.Destination{dest}
+	if err := loadContent(q, destinations); err != nil {
+		return nil, err
+	}
+
+	return &destinations[0], nil
+}
+
+// loadContent fills in the active clues, fun facts and trivia of the given destinations
+func loadContent(q querier, destinations []models.Destination) error {
+	if len(destinations) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Destination, len(destinations))
+	ids := make([]int, 0, len(destinations))
+	for i := range destinations {
+		destinations[i].Clues = []string{}
+		destinations[i].ClueIDs = []int{}
+		destinations[i].FunFact = []string{}
+		destinations[i].FunFactIDs = []int{}
+		destinations[i].Trivia = []string{}
+		destinations[i].TriviaIDs = []int{}
+		byID[destinations[i].ID] = &destinations[i]
+		ids = append(ids, destinations[i].ID)
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, destination_id, text
+		FROM destination_clues
+		WHERE retired = 0 AND destination_id IN (?)
+		ORDER BY destination_id, position, id
+	`, ids)
+	if err != nil {
+		return err
+	}
+
+	var clues []models.Clue
+	if err := q.Select(&clues, query, args...); err != nil {
+		return fmt.Errorf("failed to load clues: %v", err)
+	}
+
+	for _, clue := range clues {
+		dest := byID[clue.DestinationID]
+		dest.Clues = append(dest.Clues, clue.Text)
+		dest.ClueIDs = append(dest.ClueIDs, clue.ID)
+	}
+
+	query, args, err = sqlx.In(`
+		SELECT id, destination_id, kind, text
+		FROM destination_facts
+		WHERE retired = 0 AND destination_id IN (?)
+		ORDER BY destination_id, position, id
+	`, ids)
+	if err != nil {
+		return err
+	}
+
+	var facts []struct {
+		ID            int    `db:"id"`
+		DestinationID int    `db:"destination_id"`
+		Kind          string `db:"kind"`
+		Text          string `db:"text"`
+	}
+	if err := q.Select(&facts, query, args...); err != nil {
+		return fmt.Errorf("failed to load facts: %v", err)
+	}
+
+	for _, fact := range facts {
+		dest := byID[fact.DestinationID]
+		if fact.Kind == factKindFunFact {
+			dest.FunFact = append(dest.FunFact, fact.Text)
+			dest.FunFactIDs = append(dest.FunFactIDs, fact.ID)
+		} else {
+			dest.Trivia = append(dest.Trivia, fact.Text)
+			dest.TriviaIDs = append(dest.TriviaIDs, fact.ID)
+		}
+	}
+
+	return nil
+}
+
+// CreateDestination inserts a new destination and returns its ID
+func (d *Database) CreateDestination(actor string, dest models.Destination) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Reject duplicates inside the transaction so two admins can't race each other
+	if err := checkDuplicateDestination(tx, dest.City, dest.Country, 0); err != nil {
+		return 0, err
+	}
+
+	id, err := insertDestination(tx, actor, dest)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// UpdateDestination replaces the content of an existing destination, keeping its ID.
+// Clues and facts whose text is unchanged keep their IDs; removed ones are retired.
+func (d *Database) UpdateDestination(actor string, dest models.Destination) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkDuplicateDestination(tx, dest.City, dest.Country, dest.ID); err != nil {
+		return err
+	}
+
+	if err := updateDestination(tx, actor, dest); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteDestination removes a destination. Destinations referenced by any game
+// question are retired instead so in-flight games and results keep resolving.
+// It reports whether the destination was retired rather than deleted.
+func (d *Database) DeleteDestination(actor string, destinationID int) (bool, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	retired, err := removeDestination(tx, actor, destinationID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return retired, nil
+}
+
+// ImportDestinations applies a dataset import in a single transaction: added
+// destinations are inserted, updated ones replaced by ID, and removed ones
+// deleted or, if games still reference them, retired. The translations
+// returned by translate, if set, are saved in the same transaction.
+func (d *Database) ImportDestinations(actor string, added, updated []models.Destination, removedIDs []int, translate storage.TranslateFunc) (storage.ImportResult, error) {
+	var result storage.ImportResult
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	for _, dest := range added {
+		if _, err := insertDestination(tx, actor, dest); err != nil {
+			return result, fmt.Errorf("failed to add %s, %s: %v", dest.City, dest.Country, err)
+		}
+	}
+
+	for _, dest := range updated {
+		if err := updateDestination(tx, actor, dest); err != nil {
+			return result, fmt.Errorf("failed to update %s, %s: %v", dest.City, dest.Country, err)
+		}
+	}
+
+	for _, id := range removedIDs {
+		retired, err := removeDestination(tx, actor, id)
+		if err != nil {
+			return result, fmt.Errorf("failed to remove destination %d: %v", id, err)
+		}
+		if retired {
+			result.Retired++
+		} else {
+			result.Deleted++
+		}
+	}
+
+	if translate != nil {
+		destinations, err := listDestinations(tx, false)
+		if err != nil {
+			return result, err
+		}
+		names, clues, facts, err := translate(destinations)
+		if err != nil {
+			return result, err
+		}
+		if err := saveTranslations(tx, names, clues, facts); err != nil {
+			return result, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// ListClues retrieves the clues of a destination, optionally including retired ones
+func (d *Database) ListClues(destinationID int, includeRetired bool) ([]models.Clue, error) {
+	query := `
+		SELECT id, destination_id, text, tags, retired, ` + submitterColumn + `
+		FROM destination_clues
+		WHERE destination_id = ?
+	`
+	if !includeRetired {
+		query += " AND retired = 0"
+	}
+	query += " ORDER BY position, id"
+
+	var rows []clueRow
+	if err := d.db.Select(&rows, query, destinationID); err != nil {
+		return nil, err
+	}
+
+	clues := make([]models.Clue, 0, len(rows))
+	for _, row := range rows {
+		clues = append(clues, row.toClue())
+	}
+
+	return clues, nil
+}
+
+// GetClueByID gets a single clue, including retired ones
+func (d *Database) GetClueByID(clueID int) (*models.Clue, error) {
+	return getClue(d.db, clueID)
+}
+
+// getClue loads a single clue through q
+func getClue(q querier, clueID int) (*models.Clue, error) {
+	var row clueRow
+	err := q.Get(&row, `
+		SELECT id, destination_id, text, tags, retired, `+submitterColumn+`
+		FROM destination_clues
+		WHERE id = ?
+	`, clueID)
+	if err != nil {
+		return nil, err
+	}
+
+	clue := row.toClue()
+	return &clue, nil
+}
+
+// UpdateClue changes a clue's text, tags or retired flag in place, keeping its ID.
+// Translations of the clue are dropped when its text changes.
+func (d *Database) UpdateClue(actor string, clue models.Clue) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getClue(tx, clue.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM clue_translations
+		WHERE clue_id = ? AND EXISTS (SELECT 1 FROM destination_clues WHERE id = ? AND text != ?)
+	`, clue.ID, clue.ID, clue.Text)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE destination_clues
+		SET text = ?, tags = ?, retired = ?
+		WHERE id = ?
+	`, clue.Text, strings.Join(clue.Tags, ","), flag(clue.Retired), clue.ID)
+	if err != nil {
+		return err
+	}
+
+	after, err := getClue(tx, clue.ID)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(tx, actor, models.AuditClueUpdate, models.AuditTargetClue, clue.ID, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// clueRow is a destination_clues row with tags in their stored comma-separated form
+type clueRow struct {
+	ID            int    `db:"id"`
+	DestinationID int    `db:"destination_id"`
+	Text          string `db:"text"`
+	Tags          string `db:"tags"`
+	Retired       bool   `db:"retired"`
+	SubmittedBy   string `db:"submitted_by"`
+}
+
+// toClue converts the row to a clue model
+func (r clueRow) toClue() models.Clue {
+	clue := models.Clue{
+		ID:            r.ID,
+		DestinationID: r.DestinationID,
+		Text:          r.Text,
+		Tags:          []string{},
+		Retired:       r.Retired,
+		SubmittedBy:   r.SubmittedBy,
+	}
+	if r.Tags != "" {
+		clue.Tags = strings.Split(r.Tags, ",")
+	}
+	return clue
+}
+
+// insertDestination inserts a destination and its content within a
+// transaction, records it in the audit log and returns its ID
+func insertDestination(tx *txConn, actor string, dest models.Destination) (int, error) {
+	var id int
+	err := tx.QueryRow(`
+		INSERT INTO destinations (city, country, latitude, longitude)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`, dest.City, dest.Country, dest.Latitude, dest.Longitude).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := syncContent(tx, id, dest); err != nil {
+		return 0, err
+	}
+
+	after, err := getDestination(tx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := recordAudit(tx, actor, models.AuditDestinationCreate, models.AuditTargetDestination, id, nil, after); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// updateDestination replaces a destination's content within a transaction
+// and records the change in the audit log
+func updateDestination(tx *txConn, actor string, dest models.Destination) error {
+	before, err := getDestination(tx, dest.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE destinations
+		SET city = ?, country = ?, retired = ?, latitude = ?, longitude = ?
+		WHERE id = ?
+	`, dest.City, dest.Country, flag(dest.Retired), dest.Latitude, dest.Longitude, dest.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := syncContent(tx, dest.ID, dest); err != nil {
+		return err
+	}
+
+	after, err := getDestination(tx, dest.ID)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, actor, models.AuditDestinationUpdate, models.AuditTargetDestination, dest.ID, before, after)
+}
+
+// removeDestination deletes a destination within a transaction, or retires it
+// if any game question references it, and records which in the audit log.
+// It reports whether it was retired.
+func removeDestination(tx *txConn, actor string, destinationID int) (bool, error) {
+	before, err := getDestination(tx, destinationID)
+	if err != nil {
+		return false, err
+	}
+
+	referenced, err := isDestinationReferenced(tx, destinationID)
+	if err != nil {
+		return false, err
+	}
+
+	if referenced {
+		if _, err := tx.Exec("UPDATE destinations SET retired = 1 WHERE id = ?", destinationID); err != nil {
+			return false, err
+		}
+		after, err := getDestination(tx, destinationID)
+		if err != nil {
+			return false, err
+		}
+		return true, recordAudit(tx, actor, models.AuditDestinationRetire, models.AuditTargetDestination, destinationID, before, after)
+	}
+
+	for _, query := range []string{
+		"DELETE FROM clue_translations WHERE clue_id IN (SELECT id FROM destination_clues WHERE destination_id = ?)",
+		"DELETE FROM fact_translations WHERE fact_id IN (SELECT id FROM destination_facts WHERE destination_id = ?)",
+		"DELETE FROM destination_translations WHERE destination_id = ?",
+		"DELETE FROM destination_images WHERE destination_id = ?",
+		"UPDATE submissions SET destination_id = NULL WHERE destination_id = ?",
+		"DELETE FROM clue_stats WHERE destination_id = ?",
+		"DELETE FROM destination_stats WHERE destination_id = ?",
+		"DELETE FROM destination_clues WHERE destination_id = ?",
+		"DELETE FROM destination_facts WHERE destination_id = ?",
+		"DELETE FROM destinations WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, destinationID); err != nil {
+			return false, err
+		}
+	}
+
+	return false, recordAudit(tx, actor, models.AuditDestinationDelete, models.AuditTargetDestination, destinationID, before, nil)
+}
+
+// syncContent makes the destination's active clues, fun facts and trivia match dest
+func syncContent(tx *txConn, destinationID int, dest models.Destination) error {
+	if err := syncTexts(tx, "destination_clues", "", destinationID, dest.Clues); err != nil {
+		return fmt.Errorf("failed to save clues: %v", err)
+	}
+	if err := syncTexts(tx, "destination_facts", factKindFunFact, destinationID, dest.FunFact); err != nil {
+		return fmt.Errorf("failed to save fun facts: %v", err)
+	}
+	if err := syncTexts(tx, "destination_facts", factKindTrivia, destinationID, dest.Trivia); err != nil {
+		return fmt.Errorf("failed to save trivia: %v", err)
+	}
+	return nil
+}
+
+// syncTexts reconciles the rows of a clue or fact table with a list of texts.
+// Rows whose text is still present keep their ID (and are restored if retired),
+// new texts are inserted, and rows no longer listed are retired rather than
+// deleted so game questions that reference them stay valid.
+func syncTexts(tx *txConn, table, kind string, destinationID int, texts []string) error {
+	filter := "destination_id = ?"
+	args := []interface{}{destinationID}
+	if kind != "" {
+		filter += " AND kind = ?"
+		args = append(args, kind)
+	}
+
+	rows, err := tx.Query(fmt.Sprintf("SELECT id, text, retired FROM %s WHERE %s ORDER BY retired, id", table, filter), args...)
+	if err != nil {
+		return err
+	}
+
+	type existingRow struct {
+		id      int
+		retired bool
+	}
+	existing := make(map[string]existingRow)
+	var order []int
+	for rows.Next() {
+		var (
+			id      int
+			text    string
+			retired bool
+		)
+		if err := rows.Scan(&id, &text, &retired); err != nil {
+			rows.Close()
+			return err
+		}
+		// Prefer the active row when the same text was stored more than once
+		if _, ok := existing[text]; !ok {
+			existing[text] = existingRow{id: id, retired: retired}
+		}
+		order = append(order, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	kept := make(map[int]bool)
+	for position, text := range texts {
+		if row, ok := existing[text]; ok && !kept[row.id] {
+			kept[row.id] = true
+			_, err = tx.Exec(fmt.Sprintf("UPDATE %s SET position = ?, retired = 0 WHERE id = ?", table), position, row.id)
+		} else if kind != "" {
+			_, err = tx.Exec(fmt.Sprintf("INSERT INTO %s (destination_id, kind, position, text) VALUES (?, ?, ?, ?)", table),
+				destinationID, kind, position, text)
+		} else {
+			_, err = tx.Exec(fmt.Sprintf("INSERT INTO %s (destination_id, position, text) VALUES (?, ?, ?)", table),
+				destinationID, position, text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range order {
+		if kept[id] {
+			continue
+		}
+		if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET retired = 1 WHERE id = ?", table), id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkDuplicateDestination returns storage.ErrDuplicateDestination if another destination
+// (other than excludeID) has the same city and country, ignoring case
+func checkDuplicateDestination(tx *txConn, city, country string, excludeID int) error {
+	var count int
+	err := tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM destinations
+		WHERE LOWER(city) = LOWER(?) AND LOWER(country) = LOWER(?) AND id != ?
+	`, city, country, excludeID).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return storage.ErrDuplicateDestination
+	}
+
+	return nil
+}
+
+// isDestinationReferenced checks whether any game question uses the destination
+// as its answer or as one of its options, or a compacted game still counts it
+// in its archived answers or shows its photo
+func isDestinationReferenced(tx *txConn, destinationID int) (bool, error) {
+	// Options are a JSON array of destination IDs
+	optionsContain := "EXISTS (SELECT 1 FROM json_each(game_questions.options) WHERE json_each.value = ?)"
+	if tx.isPostgres() {
+		optionsContain = "game_questions.options @> jsonb_build_array(?::integer)"
+	}
+
+	var count int
+	err := tx.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM game_questions WHERE correct_destination_id = ? OR `+optionsContain+`) +
+			(SELECT COUNT(*) FROM archived_answers WHERE correct_destination_id = ? OR selected_destination_id = ?) +
+			(SELECT COUNT(*) FROM games WHERE cover_destination_id = ?)`,
+		destinationID, destinationID, destinationID, destinationID, destinationID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
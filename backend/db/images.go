@@ -0,0 +1,73 @@
+package db
+
+import (
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// GetDestinationImage gets the cached photo of a destination
+func (d *Database) GetDestinationImage(destinationID int) (*models.DestinationImage, error) {
+	var image models.DestinationImage
+	err := d.db.Get(&image, `
+		SELECT destination_id, file, provider, photographer, photographer_url, source_url, alt, fetched_at
+		FROM destination_images
+		WHERE destination_id = ?
+	`, destinationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// GetGameImage gets the cached photo of the first destination of a game that
+// has one, or of the cover destination of a compacted game
+func (d *Database) GetGameImage(gameID int) (*models.DestinationImage, error) {
+	var image models.DestinationImage
+	err := d.db.Get(&image, `
+		SELECT di.destination_id, di.file, di.provider, di.photographer, di.photographer_url, di.source_url, di.alt, di.fetched_at
+		FROM (
+			SELECT id AS position, correct_destination_id AS destination_id FROM game_questions WHERE game_id = ?
+			UNION ALL
+			SELECT 0, cover_destination_id FROM games WHERE id = ? AND cover_destination_id IS NOT NULL
+		) g
+		JOIN destination_images di ON di.destination_id = g.destination_id
+		ORDER BY g.position ASC
+		LIMIT 1
+	`, gameID, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// ListDestinationsWithoutImage lists active destinations that have no cached photo yet
+func (d *Database) ListDestinationsWithoutImage() ([]models.Destination, error) {
+	var destinations []models.Destination
+	err := d.db.Select(&destinations, `
+		SELECT id, city, country, retired, latitude, longitude
+		FROM destinations
+		WHERE retired = 0 AND id NOT IN (SELECT destination_id FROM destination_images)
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	return destinations, nil
+}
+
+// SaveDestinationImage stores or replaces the cached photo of a destination
+func (d *Database) SaveDestinationImage(image models.DestinationImage) error {
+	_, err := d.db.Exec(`
+		INSERT INTO destination_images (destination_id, file, provider, photographer, photographer_url, source_url, alt, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (destination_id) DO UPDATE
+		SET file = excluded.file, provider = excluded.provider, photographer = excluded.photographer,
+		    photographer_url = excluded.photographer_url, source_url = excluded.source_url,
+		    alt = excluded.alt, fetched_at = excluded.fetched_at
+	`, image.DestinationID, image.File, image.Provider, image.Photographer,
+		image.PhotographerURL, image.SourceURL, image.Alt)
+
+	return err
+}
@@ -0,0 +1,348 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/migrations"
+)
+
+// ErrDatabaseAhead is returned when the database has migrations applied that
+// this build doesn't know, meaning it was migrated by a newer version
+var ErrDatabaseAhead = errors.New("database schema is newer than this build")
+
+// downMarker separates a migration's up statements from its down statements
+const downMarker = "-- migrate:down"
+
+// migration is one embedded schema change
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus describes a migration and whether it has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts the embedded migrations of a database
+type Migrator struct {
+	db         *conn
+	migrations []migration
+}
+
+// OpenMigrator connects to the database named by dsn without changing its
+// schema, for inspecting and running migrations by hand
+func OpenMigrator(dsn string) (*Migrator, error) {
+	c, err := connect(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := newMigrator(c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// newMigrator loads the migrations for c's dialect and makes sure the
+// database records which of them are applied
+func newMigrator(c *conn) (*Migrator, error) {
+	dir := "."
+	if c.isPostgres() {
+		dir = "postgres"
+	}
+	list, err := loadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{db: c, migrations: list}
+	if err := m.ensureVersionTable(); err != nil {
+		return nil, fmt.Errorf("failed to prepare schema_migrations: %v", err)
+	}
+	return m, nil
+}
+
+// Close closes the database connection
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// loadMigrations reads the migrations embedded in dir, ordered by version
+func loadMigrations(dir string) ([]migration, error) {
+	files, err := fs.Glob(migrations.FS, path.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	var list []migration
+	seen := make(map[int]string)
+	for _, file := range files {
+		name := path.Base(file)
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with its version", file)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", other, name)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(migrations.FS, file)
+		if err != nil {
+			return nil, err
+		}
+		up, down, ok := strings.Cut(string(content), downMarker)
+		if !ok {
+			return nil, fmt.Errorf("migration %s has no %q section", file, downMarker)
+		}
+		list = append(list, migration{version: version, name: strings.TrimSuffix(name, ".sql"), up: up, down: down})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+	return list, nil
+}
+
+// Latest returns the newest migration version this build knows
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].version
+}
+
+// Version returns the newest migration applied to the database
+func (m *Migrator) Version() (int, error) {
+	var version int
+	err := m.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+// Status lists every migration this build knows with whether it is applied.
+// It fails with ErrDatabaseAhead if the database has newer ones.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		appliedAt, ok := applied[mig.version]
+		statuses = append(statuses, MigrationStatus{Version: mig.version, Name: mig.name, Applied: ok, AppliedAt: appliedAt})
+	}
+	return statuses, m.checkNotAhead(applied)
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	return m.To(m.Latest())
+}
+
+// Down reverts the newest applied migration
+func (m *Migrator) Down() error {
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		return errors.New("no migrations are applied")
+	}
+
+	target := 0
+	for _, mig := range m.migrations {
+		if mig.version < version {
+			target = mig.version
+		}
+	}
+	return m.To(target)
+}
+
+// To applies or reverts migrations until the database is at version. Each
+// migration runs in its own transaction along with its schema_migrations
+// row, so a failure leaves the database at the last version that succeeded.
+func (m *Migrator) To(version int) error {
+	if version < 0 || version > m.Latest() {
+		return fmt.Errorf("unknown migration version %d, latest is %d", version, m.Latest())
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	if err := m.checkNotAhead(applied); err != nil {
+		return err
+	}
+
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.version]; ok || mig.version > version {
+			continue
+		}
+		log.Printf("Applying migration %s...", mig.name)
+		if err := m.run(mig.up, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mig.version, mig.name); err != nil {
+			return fmt.Errorf("migration %s failed: %v", mig.name, err)
+		}
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.version]; !ok || mig.version <= version {
+			continue
+		}
+		log.Printf("Reverting migration %s...", mig.name)
+		if err := m.run(mig.down, "DELETE FROM schema_migrations WHERE version = ?", mig.version); err != nil {
+			return fmt.Errorf("reverting migration %s failed: %v", mig.name, err)
+		}
+	}
+
+	return nil
+}
+
+// run executes a migration's statements and the query recording it in one transaction
+func (m *Migrator) run(statements, record string, args ...interface{}) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Migrations may hold several statements and are not rebound
+	if strings.TrimSpace(statements) != "" {
+		if _, err := tx.Tx.Exec(statements); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// applied returns the versions recorded in schema_migrations and when they were applied
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// checkNotAhead returns ErrDatabaseAhead if applied holds a version this build doesn't have
+func (m *Migrator) checkNotAhead(applied map[int]time.Time) error {
+	known := make(map[int]bool, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.version] = true
+	}
+	for version := range applied {
+		if !known[version] {
+			return fmt.Errorf("%w: it has migration %d applied, this build knows up to %d", ErrDatabaseAhead, version, m.Latest())
+		}
+	}
+	return nil
+}
+
+// ensureVersionTable creates schema_migrations. Databases created before
+// migrations were tracked by version are recorded at the version their
+// schema matches, so only the migrations they lack are applied.
+func (m *Migrator) ensureVersionTable() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil || count > 0 {
+		return err
+	}
+
+	version, err := m.detectVersion()
+	if err != nil || version == 0 {
+		return err
+	}
+
+	log.Printf("Recording existing database schema as migration version %d", version)
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, mig := range m.migrations {
+		if mig.version > version {
+			break
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mig.version, mig.name); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// schemaMarkers are the tables and columns that show a migration was applied,
+// newest first. Migrations without a marker only add indexes or tables that
+// later migrations create again if missing.
+var schemaMarkers = []struct {
+	version       int
+	table, column string
+}{
+	{11, "clue_stats", ""},
+	{10, "submissions", ""},
+	{9, "destination_images", ""},
+	{8, "destination_translations", ""},
+	{7, "game_questions", "pin_latitude"},
+	{6, "destinations", "latitude"},
+	{5, "destination_clues", ""},
+	{4, "destinations", "retired"},
+	{1, "destinations", ""},
+}
+
+// detectVersion returns the version of an untracked schema, or 0 for an empty database
+func (m *Migrator) detectVersion() (int, error) {
+	for _, marker := range schemaMarkers {
+		var found bool
+		var err error
+		if marker.column == "" {
+			found, err = m.db.hasTable(marker.table)
+		} else {
+			found, err = m.db.hasColumn(marker.table, marker.column)
+		}
+		if err != nil || found {
+			return marker.version, err
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// openMigrator opens a migrator on a new SQLite file and returns it with the file's path
+func openMigrator(t *testing.T) (*Migrator, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "globetrotter.db")
+	m, err := OpenMigrator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m, path
+}
+
+// tables returns the number of tables in the database other than schema_migrations
+func tables(t *testing.T, m *Migrator) int {
+	t.Helper()
+	var count int
+	err := m.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master
+		WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')`).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+// checkVersion reports a failure unless the database is at version
+func checkVersion(t *testing.T, m *Migrator, version int) {
+	t.Helper()
+	got, err := m.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != version {
+		t.Errorf("database is at version %d, want %d", got, version)
+	}
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	m, _ := openMigrator(t)
+	if tables(t, m) != 0 {
+		t.Fatal("new database has tables")
+	}
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	checkVersion(t, m, m.Latest())
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("migration %s is not recorded as applied", status.Name)
+		}
+	}
+
+	// Every migration is reverted on its own, newest first
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if err := m.Down(); err != nil {
+			t.Fatalf("Down from %s: %v", m.migrations[i].name, err)
+		}
+		want := 0
+		if i > 0 {
+			want = m.migrations[i-1].version
+		}
+		checkVersion(t, m, want)
+	}
+	if n := tables(t, m); n != 0 {
+		t.Errorf("%d tables are left after reverting every migration", n)
+	}
+	if err := m.Down(); err == nil {
+		t.Error("Down of an empty database succeeded")
+	}
+
+	// The reverted database migrates again, and To goes both ways
+	if err := m.To(m.Latest()); err != nil {
+		t.Fatalf("To(%d) after reverting: %v", m.Latest(), err)
+	}
+	checkVersion(t, m, m.Latest())
+	if err := m.To(5); err != nil {
+		t.Fatalf("To(5): %v", err)
+	}
+	checkVersion(t, m, 5)
+	if err := m.To(m.Latest() + 1); err == nil {
+		t.Error("To a version past the latest succeeded")
+	}
+}
+
+// TestMigrateAdopt checks that a schema created before migrations were
+// recorded is adopted at the version its tables show, and migrated from there
+func TestMigrateAdopt(t *testing.T) {
+	for _, marker := range schemaMarkers {
+		t.Run(fmt.Sprintf("version %d", marker.version), func(t *testing.T) {
+			m, path := openMigrator(t)
+			if err := m.To(marker.version); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := m.db.Exec("DROP TABLE schema_migrations"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := m.db.Exec("INSERT INTO users (username) VALUES ('veteran')"); err != nil {
+				t.Fatal(err)
+			}
+			m.Close()
+
+			adopted, err := OpenMigrator(path)
+			if err != nil {
+				t.Fatalf("opening the untracked schema: %v", err)
+			}
+			defer adopted.Close()
+			checkVersion(t, adopted, marker.version)
+			if err := adopted.Up(); err != nil {
+				t.Fatalf("Up from the adopted version: %v", err)
+			}
+			checkVersion(t, adopted, adopted.Latest())
+
+			var count int
+			if err := adopted.db.QueryRow("SELECT COUNT(*) FROM users WHERE username = 'veteran'").Scan(&count); err != nil || count != 1 {
+				t.Errorf("found %d users from before the adoption, %v; want 1", count, err)
+			}
+		})
+	}
+}
+
+func TestMigrateDatabaseAhead(t *testing.T) {
+	m, path := openMigrator(t)
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	newer := m.Latest() + 1
+	if _, err := m.db.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", newer, "from_a_newer_build"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Status(); !errors.Is(err, ErrDatabaseAhead) {
+		t.Errorf("Status: got %v, want ErrDatabaseAhead", err)
+	}
+	if err := m.Up(); !errors.Is(err, ErrDatabaseAhead) {
+		t.Errorf("Up: got %v, want ErrDatabaseAhead", err)
+	}
+	if err := m.To(1); !errors.Is(err, ErrDatabaseAhead) {
+		t.Errorf("To(1): got %v, want ErrDatabaseAhead", err)
+	}
+	checkVersion(t, m, newer)
+
+	if d, err := Open(path); !errors.Is(err, ErrDatabaseAhead) {
+		if d != nil {
+			d.Close()
+		}
+		t.Errorf("Open: got %v, want ErrDatabaseAhead", err)
+	}
+}
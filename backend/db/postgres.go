@@ -0,0 +1,42 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// IsPostgres reports whether dsn is a PostgreSQL connection URL
+func IsPostgres(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// Redact returns dsn with any password masked, for logging
+func Redact(dsn string) string {
+	if !IsPostgres(dsn) {
+		return dsn
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "postgres://"
+	}
+	return u.Redacted()
+}
+
+// connectPostgres connects to the PostgreSQL database at dsn
+func connectPostgres(dsn string) (*conn, error) {
+	handle, err := sqlx.Open(dialectPostgres, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	if err := handle.Ping(); err != nil {
+		handle.Close()
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	return &conn{DB: handle}, nil
+}
@@ -0,0 +1,54 @@
+package db_test
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/storage"
+	"github.com/shubhsherl/globetrotter/backend/storage/storagetest"
+)
+
+// TestPostgresStore runs against the PostgreSQL database at TEST_DATABASE_URL
+// and is skipped when that is not set. Each check gets a schema of its own,
+// dropped when it ends.
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	storagetest.Run(t, func(t *testing.T) storage.Store {
+		schema := fmt.Sprintf("storagetest_%d", time.Now().UnixNano())
+		if _, err := conn.Exec("CREATE SCHEMA " + schema); err != nil {
+			t.Fatalf("creating schema %s: %v", schema, err)
+		}
+		t.Cleanup(func() {
+			if _, err := conn.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+				t.Errorf("dropping schema %s: %v", schema, err)
+			}
+		})
+		return open(t, withSearchPath(t, dsn, schema))
+	})
+}
+
+// withSearchPath returns the URL dsn with its connections using schema
+func withSearchPath(t *testing.T, dsn, schema string) string {
+	t.Helper()
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("parsing TEST_DATABASE_URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DeleteAbandonedGames deletes the games created before the given time that
+// never had a question answered, along with their questions. They hold no
+// answers, so the statistics don't change.
+func (d *Database) DeleteAbandonedGames(createdBefore time.Time) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	cutoff := timestamp(tx.isPostgres(), createdBefore)
+	abandoned := "SELECT id FROM games WHERE total_answered = 0 AND created_at < ?"
+	if _, err := tx.Exec("DELETE FROM game_questions WHERE game_id IN ("+abandoned+")", cutoff); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("DELETE FROM games WHERE total_answered = 0 AND created_at < ?", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(deleted), tx.Commit()
+}
+
+// CompactGames removes the questions of up to limit finished games created
+// before the given time. Games with questions left are never compacted, so
+// they can still be played. Their multiple-choice answers are added to
+// archived_answers first, so the difficulty statistics stay the same, and
+// the game rows keep their totals and score. It returns how many games were
+// compacted; call it again until that is less than limit.
+func (d *Database) CompactGames(createdBefore time.Time, limit int) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var gameIDs []int
+	err = tx.Select(&gameIDs, `
+		SELECT id
+		FROM games
+		WHERE compacted_at IS NULL AND total_answered > 0 AND total_answered >= total_questions AND created_at < ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, timestamp(tx.isPostgres(), createdBefore), limit)
+	if err != nil || len(gameIDs) == 0 {
+		return 0, err
+	}
+
+	statements := []string{
+		`INSERT INTO archived_answers (correct_destination_id, clue_id, selected_destination_id, answers)
+		 SELECT correct_destination_id, COALESCE(clue_id, 0), selected_destination_id, COUNT(*)
+		 FROM game_questions
+		 WHERE game_id IN (?) AND is_answered = 1 AND selected_destination_id != 0
+		 GROUP BY correct_destination_id, COALESCE(clue_id, 0), selected_destination_id
+		 ON CONFLICT (correct_destination_id, clue_id, selected_destination_id)
+		 DO UPDATE SET answers = archived_answers.answers + excluded.answers`,
+		`UPDATE games
+		 SET compacted_at = CURRENT_TIMESTAMP,
+		     cover_destination_id = (
+		         SELECT correct_destination_id FROM game_questions
+		         WHERE game_questions.game_id = games.id
+		         ORDER BY game_questions.id ASC
+		         LIMIT 1
+		     )
+		 WHERE id IN (?)`,
+		`DELETE FROM game_questions WHERE game_id IN (?)`,
+	}
+	for _, statement := range statements {
+		query, args, err := sqlx.In(statement, gameIDs)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(query, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(gameIDs), tx.Commit()
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// openTest opens a new SQLite database for a test of package internals
+func openTest(t *testing.T) *Database {
+	t.Helper()
+	t.Setenv("DATASET_PATH", filepath.Join("..", "data", "data.json"))
+
+	database, err := Open(filepath.Join(t.TempDir(), "globetrotter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestDeleteAbandonedGamesCutoff(t *testing.T) {
+	d := openTest(t)
+	if err := d.SaveUser(models.ActorSystem, models.User{Username: "idle"}); err != nil {
+		t.Fatal(err)
+	}
+	user, err := d.GetUserByUsername("idle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gameID, err := d.CreateGame(user.ID, 1, models.ScoringStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.db.Exec("UPDATE games SET created_at = ? WHERE id = ?", "2026-01-01 12:00:00", gameID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cutoff is exclusive: a game created at that very second is kept
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		cutoff time.Time
+		want   int
+	}{
+		{created.Add(-time.Second), 0},
+		{created, 0},
+		{created.In(time.FixedZone("UTC+2", 2*60*60)), 0},
+		{created.Add(time.Second), 1},
+	} {
+		deleted, err := d.DeleteAbandonedGames(tt.cutoff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted != tt.want {
+			t.Errorf("DeleteAbandonedGames(%s) deleted %d games, want %d", tt.cutoff, deleted, tt.want)
+		}
+	}
+}
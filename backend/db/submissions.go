@@ -0,0 +1,272 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// submissionRow is a submissions row with its lists in their stored JSON form
+type submissionRow struct {
+	models.Submission
+	CluesJSON   string `db:"clues"`
+	FunFactJSON string `db:"fun_fact"`
+	TriviaJSON  string `db:"trivia"`
+}
+
+// toSubmission decodes the row's lists into a submission model
+func (r submissionRow) toSubmission() (models.Submission, error) {
+	sub := r.Submission
+	for _, list := range []struct {
+		data   string
+		target *[]string
+	}{
+		{r.CluesJSON, &sub.Clues},
+		{r.FunFactJSON, &sub.FunFact},
+		{r.TriviaJSON, &sub.Trivia},
+	} {
+		*list.target = []string{}
+		if err := json.Unmarshal([]byte(list.data), list.target); err != nil {
+			return sub, fmt.Errorf("failed to parse submission %d: %v", r.ID, err)
+		}
+	}
+	return sub, nil
+}
+
+// selectSubmissions is the query submissions are loaded with, joined with their submitter
+const selectSubmissions = `
+	SELECT s.id, s.kind, s.status, s.user_id, u.username, s.destination_id, s.city, s.country,
+	       s.latitude, s.longitude, s.clues, s.fun_fact, s.trivia, s.review_note, s.created_at, s.reviewed_at
+	FROM submissions s
+	JOIN users u ON u.id = s.user_id
+`
+
+// CreateSubmission stores a new pending submission and returns its ID
+func (d *Database) CreateSubmission(sub models.Submission) (int, error) {
+	lists, err := encodeLists(sub)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = d.db.QueryRow(`
+		INSERT INTO submissions (user_id, kind, status, destination_id, city, country, latitude, longitude, clues, fun_fact, trivia)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, sub.UserID, sub.Kind, models.SubmissionPending, sub.DestinationID, sub.City, sub.Country,
+		sub.Latitude, sub.Longitude, lists[0], lists[1], lists[2]).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetSubmission gets a submission by its ID
+func (d *Database) GetSubmission(submissionID int) (*models.Submission, error) {
+	return getSubmission(d.db, submissionID)
+}
+
+// getSubmission loads a submission through q
+func getSubmission(q querier, submissionID int) (*models.Submission, error) {
+	var row submissionRow
+	if err := q.Get(&row, selectSubmissions+" WHERE s.id = ?", submissionID); err != nil {
+		return nil, err
+	}
+
+	sub, err := row.toSubmission()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// ListSubmissions retrieves submissions oldest first, optionally only those
+// with the given status or from the given user (0 for everyone)
+func (d *Database) ListSubmissions(status string, userID int) ([]models.Submission, error) {
+	query := selectSubmissions + " WHERE 1 = 1"
+	var args []interface{}
+	if status != "" {
+		query += " AND s.status = ?"
+		args = append(args, status)
+	}
+	if userID != 0 {
+		query += " AND s.user_id = ?"
+		args = append(args, userID)
+	}
+	query += " ORDER BY s.id ASC"
+
+	var rows []submissionRow
+	if err := d.db.Select(&rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	submissions := make([]models.Submission, 0, len(rows))
+	for _, row := range rows {
+		sub, err := row.toSubmission()
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, sub)
+	}
+
+	return submissions, nil
+}
+
+// ApproveSubmission merges a submission into the destinations table in a
+// single transaction. dest is the destination as it should look afterwards:
+// a new destination when its ID is 0, otherwise an existing one with the
+// submitted content added. The rows the submission adds are credited to it,
+// and the submission is stored with its final, possibly edited, content.
+// It returns the ID of the destination. The review and the destination change
+// are both recorded in the audit log.
+func (d *Database) ApproveSubmission(actor string, sub models.Submission, dest models.Destination) (int, error) {
+	lists, err := encodeLists(sub)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := checkPendingSubmission(tx, sub.ID); err != nil {
+		return 0, err
+	}
+
+	before, err := getSubmission(tx, sub.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := checkDuplicateDestination(tx, dest.City, dest.Country, dest.ID); err != nil {
+		return 0, err
+	}
+
+	// Clues inserted from here on come from this submission
+	var lastClueID int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(id), 0) FROM destination_clues").Scan(&lastClueID); err != nil {
+		return 0, err
+	}
+
+	destinationID := dest.ID
+	if destinationID == 0 {
+		destinationID, err = insertDestination(tx, actor, dest)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE destinations SET submission_id = ? WHERE id = ?", sub.ID, destinationID); err != nil {
+			return 0, err
+		}
+	} else if err := updateDestination(tx, actor, dest); err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE destination_clues SET submission_id = ?
+		WHERE destination_id = ? AND id > ?
+	`, sub.ID, destinationID, lastClueID)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE submissions
+		SET status = ?, destination_id = ?, city = ?, country = ?, latitude = ?, longitude = ?,
+		    clues = ?, fun_fact = ?, trivia = ?, review_note = ?, reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, models.SubmissionApproved, destinationID, sub.City, sub.Country, sub.Latitude, sub.Longitude,
+		lists[0], lists[1], lists[2], sub.ReviewNote, sub.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := auditReview(tx, actor, models.AuditSubmissionApprove, before); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return destinationID, nil
+}
+
+// RejectSubmission marks a pending submission as rejected with the reviewer's note
+func (d *Database) RejectSubmission(actor string, submissionID int, note string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkPendingSubmission(tx, submissionID); err != nil {
+		return err
+	}
+
+	before, err := getSubmission(tx, submissionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE submissions
+		SET status = ?, review_note = ?, reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, models.SubmissionRejected, note, submissionID)
+	if err != nil {
+		return err
+	}
+
+	if err := auditReview(tx, actor, models.AuditSubmissionReject, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// checkPendingSubmission returns sql.ErrNoRows if the submission doesn't exist
+// and storage.ErrSubmissionReviewed if it was already approved or rejected
+func checkPendingSubmission(tx *txConn, submissionID int) error {
+	var status string
+	if err := tx.QueryRow("SELECT status FROM submissions WHERE id = ?", submissionID).Scan(&status); err != nil {
+		return err
+	}
+
+	if status != models.SubmissionPending {
+		return storage.ErrSubmissionReviewed
+	}
+
+	return nil
+}
+
+// auditReview records the review of a submission in the audit log, with the
+// submission as it was before and as the review left it
+func auditReview(tx *txConn, actor, action string, before *models.Submission) error {
+	after, err := getSubmission(tx, before.ID)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, actor, action, models.AuditTargetSubmission, before.ID, before, after)
+}
+
+// encodeLists encodes a submission's clues, fun facts and trivia for storage
+func encodeLists(sub models.Submission) ([3]string, error) {
+	var encoded [3]string
+	for i, list := range [][]string{sub.Clues, sub.FunFact, sub.Trivia} {
+		if list == nil {
+			list = []string{}
+		}
+		data, err := json.Marshal(list)
+		if err != nil {
+			return encoded, err
+		}
+		encoded[i] = string(data)
+	}
+	return encoded, nil
+}
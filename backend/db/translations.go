@@ -0,0 +1,261 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// translationRow is a translated text and the locale it is in
+type translationRow struct {
+	ID     int    `db:"id"`
+	Locale string `db:"locale"`
+	Text   string `db:"text"`
+}
+
+// LocalizeDestinations replaces the names, clues, fun facts and trivia of the
+// given destinations with translations. Each item is taken from the first
+// locale in the fallback chain that has it and left as is otherwise.
+func (d *Database) LocalizeDestinations(destinations []models.Destination, locales []string) error {
+	ranks := localeRanks(locales)
+	if len(ranks) == 0 || len(destinations) == 0 {
+		return nil
+	}
+
+	var destIDs, clueIDs, factIDs []int
+	for _, dest := range destinations {
+		destIDs = append(destIDs, dest.ID)
+		clueIDs = append(clueIDs, dest.ClueIDs...)
+		factIDs = append(factIDs, dest.FunFactIDs...)
+		factIDs = append(factIDs, dest.TriviaIDs...)
+	}
+	chain := rankedLocales(ranks)
+
+	query, args, err := sqlx.In(`
+		SELECT destination_id, locale, city, country
+		FROM destination_translations
+		WHERE destination_id IN (?) AND locale IN (?)
+	`, destIDs, chain)
+	if err != nil {
+		return err
+	}
+
+	var names []models.DestinationTranslation
+	if err := d.db.Select(&names, query, args...); err != nil {
+		return fmt.Errorf("failed to load destination translations: %v", err)
+	}
+
+	// City and country are picked separately so a locale can translate just one
+	cities := make(map[int]translationRow)
+	countries := make(map[int]translationRow)
+	for _, name := range names {
+		pickTranslation(cities, ranks, translationRow{ID: name.DestinationID, Locale: name.Locale, Text: name.City})
+		pickTranslation(countries, ranks, translationRow{ID: name.DestinationID, Locale: name.Locale, Text: name.Country})
+	}
+
+	clues, err := d.loadTextTranslations("clue_translations", "clue_id", clueIDs, chain, ranks)
+	if err != nil {
+		return err
+	}
+	facts, err := d.loadTextTranslations("fact_translations", "fact_id", factIDs, chain, ranks)
+	if err != nil {
+		return err
+	}
+
+	for i := range destinations {
+		dest := &destinations[i]
+		if row, ok := cities[dest.ID]; ok {
+			dest.City = row.Text
+		}
+		if row, ok := countries[dest.ID]; ok {
+			dest.Country = row.Text
+		}
+		dest.Clues = translateTexts(dest.Clues, dest.ClueIDs, clues)
+		dest.FunFact = translateTexts(dest.FunFact, dest.FunFactIDs, facts)
+		dest.Trivia = translateTexts(dest.Trivia, dest.TriviaIDs, facts)
+	}
+
+	return nil
+}
+
+// TranslateClue returns the translation of a clue in the first locale of the
+// chain that has one, and whether one was found
+func (d *Database) TranslateClue(clueID int, locales []string) (string, bool, error) {
+	ranks := localeRanks(locales)
+	if len(ranks) == 0 {
+		return "", false, nil
+	}
+
+	translations, err := d.loadTextTranslations("clue_translations", "clue_id", []int{clueID}, rankedLocales(ranks), ranks)
+	if err != nil {
+		return "", false, err
+	}
+
+	row, ok := translations[clueID]
+	return row.Text, ok, nil
+}
+
+// ListTranslations returns every translation stored for a locale: destination
+// names, and clue and fact texts keyed by their IDs
+func (d *Database) ListTranslations(locale string) ([]models.DestinationTranslation, map[int]string, map[int]string, error) {
+	var names []models.DestinationTranslation
+	err := d.db.Select(&names, `
+		SELECT destination_id, locale, city, country
+		FROM destination_translations
+		WHERE locale = ?
+	`, locale)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	texts := make([]map[int]string, 2)
+	for i, table := range []struct{ name, column string }{
+		{"clue_translations", "clue_id"},
+		{"fact_translations", "fact_id"},
+	} {
+		var rows []translationRow
+		query := fmt.Sprintf("SELECT %s AS id, locale, text FROM %s WHERE locale = ?", table.column, table.name)
+		if err := d.db.Select(&rows, query, locale); err != nil {
+			return nil, nil, nil, err
+		}
+
+		texts[i] = make(map[int]string, len(rows))
+		for _, row := range rows {
+			texts[i][row.ID] = row.Text
+		}
+	}
+
+	return names, texts[0], texts[1], nil
+}
+
+// SaveTranslations upserts translations in a single transaction. Empty
+// destination name fields keep whatever translation is already stored.
+func (d *Database) SaveTranslations(names []models.DestinationTranslation, clues, facts []models.TextTranslation) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveTranslations(tx, names, clues, facts); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveTranslations upserts translations through tx
+func saveTranslations(tx *txConn, names []models.DestinationTranslation, clues, facts []models.TextTranslation) error {
+	for _, name := range names {
+		_, err := tx.Exec(`
+			INSERT INTO destination_translations (destination_id, locale, city, country)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (destination_id, locale) DO UPDATE
+			SET city = COALESCE(NULLIF(excluded.city, ''), destination_translations.city),
+			    country = COALESCE(NULLIF(excluded.country, ''), destination_translations.country)
+		`, name.DestinationID, name.Locale, name.City, name.Country)
+		if err != nil {
+			return fmt.Errorf("failed to save name of destination %d: %v", name.DestinationID, err)
+		}
+	}
+
+	for _, table := range []struct {
+		name, column string
+		rows         []models.TextTranslation
+	}{
+		{"clue_translations", "clue_id", clues},
+		{"fact_translations", "fact_id", facts},
+	} {
+		query := fmt.Sprintf(`
+			INSERT INTO %s (%s, locale, text)
+			VALUES (?, ?, ?)
+			ON CONFLICT (%s, locale) DO UPDATE SET text = excluded.text
+		`, table.name, table.column, table.column)
+
+		for _, row := range table.rows {
+			if _, err := tx.Exec(query, row.TextID, row.Locale, row.Text); err != nil {
+				return fmt.Errorf("failed to save translation of %s %d: %v", table.column, row.TextID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadTextTranslations loads the best translation of each clue or fact ID
+func (d *Database) loadTextTranslations(table, column string, ids []int, chain []string, ranks map[string]int) (map[int]translationRow, error) {
+	best := make(map[int]translationRow)
+	if len(ids) == 0 {
+		return best, nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(`
+		SELECT %s AS id, locale, text
+		FROM %s
+		WHERE %s IN (?) AND locale IN (?)
+	`, column, table, column), ids, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []translationRow
+	if err := d.db.Select(&rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %v", table, err)
+	}
+
+	for _, row := range rows {
+		pickTranslation(best, ranks, row)
+	}
+	return best, nil
+}
+
+// localeRanks maps each locale in the chain that comes before the default
+// locale to its position; the default locale needs no translation
+func localeRanks(locales []string) map[string]int {
+	ranks := make(map[string]int)
+	for i, locale := range locales {
+		if locale == i18n.DefaultLocale {
+			break
+		}
+		if _, ok := ranks[locale]; !ok {
+			ranks[locale] = i
+		}
+	}
+	return ranks
+}
+
+// rankedLocales lists the locales of a rank map
+func rankedLocales(ranks map[string]int) []string {
+	locales := make([]string, 0, len(ranks))
+	for locale := range ranks {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// pickTranslation keeps row if it is non-empty and in a more preferred locale
+// than the one already chosen for its ID
+func pickTranslation(best map[int]translationRow, ranks map[string]int, row translationRow) {
+	if row.Text == "" {
+		return
+	}
+	if current, ok := best[row.ID]; ok && ranks[current.Locale] <= ranks[row.Locale] {
+		return
+	}
+	best[row.ID] = row
+}
+
+// translateTexts returns texts with every entry that has a translation replaced
+func translateTexts(texts []string, ids []int, translations map[int]translationRow) []string {
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = text
+		if i < len(ids) {
+			if row, ok := translations[ids[i]]; ok {
+				translated[i] = row.Text
+			}
+		}
+	}
+	return translated
+}
@@ -0,0 +1,114 @@
+package grpcapi
+
+import (
+	"github.com/shubhsherl/globetrotter/backend/models"
+	pb "github.com/shubhsherl/globetrotter/backend/proto/globetrotter/v1"
+)
+
+// toUser converts a user to its message
+func toUser(user models.User) *pb.User {
+	return &pb.User{
+		Id:        int64(user.ID),
+		Username:  user.Username,
+		Locale:    user.Locale,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// toQuestion converts the next question of a game to its message
+func toQuestion(question *models.NextQuestionResponse) *pb.Question {
+	options := make(map[int64]string, len(question.OptionsDisplay))
+	for id, name := range question.OptionsDisplay {
+		options[int64(id)] = name
+	}
+
+	return &pb.Question{
+		GameId:         int64(question.GameID),
+		QuestionId:     int64(question.QuestionID),
+		Question:       question.Question,
+		OptionsDisplay: options,
+		Scoring:        question.Scoring,
+		HasNext:        question.HasNext,
+	}
+}
+
+// toAnswerResult converts the outcome of an answer to its message
+func toAnswerResult(result *models.SubmitAnswerResponse) *pb.AnswerResult {
+	answer := &pb.AnswerResult{
+		Correct:          result.Correct,
+		FunFact:          result.FunFact,
+		Trivia:           result.Trivia,
+		CorrectCity:      result.CorrectCity,
+		CorrectCountry:   result.CorrectCountry,
+		CorrectOptionId:  int64(result.CorrectOptionID),
+		Points:           int64(result.Points),
+		DistanceKm:       result.DistanceKm,
+		CorrectLatitude:  result.CorrectLatitude,
+		CorrectLongitude: result.CorrectLongitude,
+		ClueSubmittedBy:  result.ClueSubmittedBy,
+	}
+
+	if image := result.Image; image != nil {
+		answer.Image = &pb.DestinationImage{
+			Url:             image.URL,
+			Provider:        image.Provider,
+			Photographer:    image.Photographer,
+			PhotographerUrl: image.PhotographerURL,
+			SourceUrl:       image.SourceURL,
+			Alt:             image.Alt,
+		}
+	}
+
+	return answer
+}
+
+// toGameResult converts the result of a game to its message
+func toGameResult(result *models.GameResult) *pb.GameResult {
+	questions := make([]*pb.QuestionResult, 0, len(result.Questions))
+	for _, question := range result.Questions {
+		options := make([]int64, len(question.OptionDestinationIDs))
+		for i, id := range question.OptionDestinationIDs {
+			options[i] = int64(id)
+		}
+
+		questions = append(questions, &pb.QuestionResult{
+			Id:                    int64(question.ID),
+			Question:              question.Question,
+			Options:               options,
+			CorrectDestinationId:  int64(question.CorrectDestinationID),
+			SelectedDestinationId: int64(question.SelectedDestinationID),
+			Answered:              question.IsAnswered == 1,
+			ClueId:                int64(question.ClueID),
+			Points:                int64(question.Points),
+			DistanceKm:            question.DistanceKm,
+			PinLatitude:           question.PinLatitude,
+			PinLongitude:          question.PinLongitude,
+			CorrectLatitude:       question.CorrectLatitude,
+			CorrectLongitude:      question.CorrectLongitude,
+		})
+	}
+
+	return &pb.GameResult{
+		GameId:         int64(result.GameID),
+		TotalQuestions: int64(result.TotalQuestions),
+		TotalCorrect:   int64(result.TotalCorrect),
+		TotalIncorrect: int64(result.TotalIncorrect),
+		Scoring:        result.Scoring,
+		Score:          int64(result.Score),
+		Compacted:      result.Compacted,
+		Questions:      questions,
+	}
+}
+
+// toGameSummary converts the summary of a game to its message
+func toGameSummary(summary *models.GameSummary) *pb.GameSummary {
+	return &pb.GameSummary{
+		GameId:         int64(summary.GameID),
+		Username:       summary.Username,
+		ImageUrl:       summary.ImageURL,
+		TotalQuestions: int64(summary.TotalQuestions),
+		TotalAnswered:  int64(summary.TotalAnswered),
+		TotalCorrect:   int64(summary.TotalCorrect),
+		CreatedAt:      summary.CreatedAt,
+	}
+}
@@ -0,0 +1,93 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the domain of the ErrorInfo detail of every error
+const errorDomain = "globetrotter"
+
+// Error codes of failures detected by the gRPC API itself, the same as the
+// REST API's. Failures reported by the services carry their own codes.
+const (
+	codeInvalidRequest  = "invalid_request"
+	codeAlreadyAnswered = "already_answered"
+	codeRateLimited     = "rate_limited"
+	codeInternal        = "internal_error"
+)
+
+// newStatus returns a status error with an ErrorInfo detail carrying the
+// error code, followed by details
+func newStatus(c codes.Code, code, message string, details ...protoadapt.MessageV1) error {
+	st := status.New(c, message)
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: code, Domain: errorDomain}}, details...)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// invalidArgument returns the error of a request the handler couldn't use,
+// translating message along locales
+func invalidArgument(locales []string, message string) error {
+	return newStatus(codes.InvalidArgument, codeInvalidRequest, i18n.T(locales, message))
+}
+
+// statusError returns the status error for err, as respondError does for
+// the REST API. Domain errors from the services get the code of their kind
+// and their own error code and message, translated along locales. Anything
+// else is logged and reported as an internal error with failure as its
+// message, so database and other internal details don't reach clients.
+func statusError(ctx context.Context, locales []string, err error, failure string) error {
+	var (
+		validation *services.ValidationError
+		answered   *services.AlreadyAnsweredError
+		domain     *services.Error
+	)
+
+	switch {
+	case errors.As(err, &validation):
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(validation.Problems))
+		for i, problem := range validation.Problems {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Description: problem}
+		}
+		return newStatus(codes.InvalidArgument, validation.Code(), i18n.T(locales, validation.Message()),
+			&errdetails.BadRequest{FieldViolations: violations})
+	case errors.As(err, &answered):
+		// A retried or doubled call gets the outcome of the answer that was recorded
+		return newStatus(codes.AlreadyExists, codeAlreadyAnswered, i18n.T(locales, "Question already answered"),
+			protoadapt.MessageV1Of(toAnswerResult(answered.Answer)))
+	case errors.As(err, &domain):
+		return newStatus(kindCode(domain.Kind), domain.Code, i18n.T(locales, domain.Message))
+	default:
+		method, _ := grpc.Method(ctx)
+		log.Printf("gRPC %s: %s: %v", method, failure, err)
+		return newStatus(codes.Internal, codeInternal, i18n.T(locales, failure))
+	}
+}
+
+// kindCode maps a kind of domain error to its gRPC status code
+func kindCode(kind error) codes.Code {
+	switch kind {
+	case services.ErrNotFound:
+		return codes.NotFound
+	case services.ErrConflict:
+		return codes.AlreadyExists
+	case services.ErrValidation:
+		return codes.InvalidArgument
+	case services.ErrForbidden:
+		return codes.PermissionDenied
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,78 @@
+package grpcapi
+
+import (
+	"context"
+	"math"
+	"net"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	pb "github.com/shubhsherl/globetrotter/backend/proto/globetrotter/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimiter decides whether a call may go on under the limits of a group
+// of REST routes, and when it may not, how long until it may. The call comes
+// from ip and acts for the player named username or, when that is empty,
+// the player of gameID. api.RateLimit is one, so calls count against the
+// same buckets as REST requests.
+type RateLimiter func(ctx context.Context, group, ip, username string, gameID int) (bool, time.Duration)
+
+// limitedMethods maps each rate limited method to the group of REST routes
+// whose limits it shares
+var limitedMethods = map[string]string{
+	pb.Globetrotter_CreateUser_FullMethodName:   "accounts",
+	pb.Globetrotter_UpdateUser_FullMethodName:   "accounts",
+	pb.Globetrotter_StartGame_FullMethodName:    "games",
+	pb.Globetrotter_SubmitAnswer_FullMethodName: "answers",
+}
+
+// LimitRate returns an interceptor that refuses calls beyond the limits of
+// their group with RESOURCE_EXHAUSTED and a RetryInfo detail giving the
+// seconds to wait. Calls are counted per peer IP and per player: the
+// username in the request, or the player of its game. Methods that don't
+// write to the database are not limited.
+func LimitRate(limit RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		group, ok := limitedMethods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		var (
+			username string
+			gameID   int
+		)
+		if r, ok := req.(interface{ GetUsername() string }); ok {
+			username = r.GetUsername()
+		}
+		if r, ok := req.(interface{ GetGameId() int64 }); ok {
+			gameID = int(r.GetGameId())
+		}
+
+		if ok, wait := limit(ctx, group, peerIP(ctx), username, gameID); !ok {
+			delay := time.Duration(math.Ceil(wait.Seconds())) * time.Second
+			return nil, newStatus(codes.ResourceExhausted, codeRateLimited,
+				i18n.T(requestLocales(ctx), "Too many requests, try again later"),
+				&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+		}
+		return handler(ctx, req)
+	}
+}
+
+// peerIP returns the IP address a call came from
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
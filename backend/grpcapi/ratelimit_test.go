@@ -0,0 +1,95 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/shubhsherl/globetrotter/backend/proto/globetrotter/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// limitCall is a call a RateLimiter was asked about
+type limitCall struct {
+	group, ip, username string
+	gameID              int
+}
+
+func TestLimitRate(t *testing.T) {
+	var calls []limitCall
+	refuse := false
+	interceptor := LimitRate(func(ctx context.Context, group, ip, username string, gameID int) (bool, time.Duration) {
+		calls = append(calls, limitCall{group, ip, username, gameID})
+		return !refuse, 1500 * time.Millisecond
+	})
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.9"), Port: 51234}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("accept-language", "fr"))
+	handled := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		handled++
+		return "handled", nil
+	}
+
+	for _, tt := range []struct {
+		method string
+		req    any
+		want   *limitCall // Nil for methods that aren't limited
+	}{
+		{pb.Globetrotter_CreateUser_FullMethodName, &pb.CreateUserRequest{Username: "alice"}, &limitCall{"accounts", "203.0.113.9", "alice", 0}},
+		{pb.Globetrotter_UpdateUser_FullMethodName, &pb.UpdateUserRequest{Username: "alice"}, &limitCall{"accounts", "203.0.113.9", "alice", 0}},
+		{pb.Globetrotter_StartGame_FullMethodName, &pb.StartGameRequest{Username: "alice"}, &limitCall{"games", "203.0.113.9", "alice", 0}},
+		{pb.Globetrotter_SubmitAnswer_FullMethodName, &pb.SubmitAnswerRequest{GameId: 7}, &limitCall{"answers", "203.0.113.9", "", 7}},
+		{pb.Globetrotter_GetUser_FullMethodName, &pb.GetUserRequest{Username: "alice"}, nil},
+		{pb.Globetrotter_GetNextQuestion_FullMethodName, &pb.GetNextQuestionRequest{GameId: 7}, nil},
+	} {
+		for _, refuse = range []bool{false, true} {
+			calls, handled = nil, 0
+			resp, err := interceptor(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			if tt.want == nil {
+				if len(calls) != 0 || handled != 1 || err != nil {
+					t.Errorf("%s was limited: %+v, %v", tt.method, calls, err)
+				}
+				continue
+			}
+			if len(calls) != 1 || calls[0] != *tt.want {
+				t.Errorf("%s asked the limiter about %+v, want %+v", tt.method, calls, *tt.want)
+			}
+			if !refuse {
+				if err != nil || handled != 1 || resp != "handled" {
+					t.Errorf("%s allowed: got %v, %v", tt.method, resp, err)
+				}
+				continue
+			}
+
+			if handled != 0 {
+				t.Errorf("%s refused, but the handler ran", tt.method)
+			}
+			st := status.Convert(err)
+			if st.Code() != codes.ResourceExhausted || st.Message() != "Trop de requêtes, réessayez plus tard" {
+				t.Errorf("%s refused: got %s %q", tt.method, st.Code(), st.Message())
+			}
+			var reason string
+			var delay time.Duration
+			for _, detail := range st.Details() {
+				switch d := detail.(type) {
+				case *errdetails.ErrorInfo:
+					reason = d.GetReason()
+				case *errdetails.RetryInfo:
+					delay = d.GetRetryDelay().AsDuration()
+				}
+			}
+			// The delay is rounded up to seconds, as in Retry-After
+			if reason != codeRateLimited || delay != 2*time.Second {
+				t.Errorf("%s refused: got reason %q and retry delay %s, want %q and 2s", tt.method, reason, delay, codeRateLimited)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package grpcapi serves the gRPC API defined in proto/globetrotter/v1. It
+// mirrors the player routes of the REST API in package api and calls the same
+// services.DataService, so both behave the same way.
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shubhsherl/globetrotter/backend/i18n"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	pb "github.com/shubhsherl/globetrotter/backend/proto/globetrotter/v1"
+	"github.com/shubhsherl/globetrotter/backend/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server implements the Globetrotter gRPC service
+type Server struct {
+	pb.UnimplementedGlobetrotterServer
+	data *services.DataService
+}
+
+// NewServer creates the gRPC service backed by data
+func NewServer(data *services.DataService) *Server {
+	return &Server{data: data}
+}
+
+// New creates a gRPC server with the Globetrotter service, the standard
+// health service and server reflection, so tools like grpcurl can list it
+func New(data *services.DataService, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	pb.RegisterGlobetrotterServer(server, NewServer(data))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	return server
+}
+
+// acceptedLocales returns the languages listed in the call's accept-language metadata
+func acceptedLocales(ctx context.Context) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return i18n.ParseAcceptLanguage(strings.Join(md.Get("accept-language"), ","))
+}
+
+// requestLocales returns the fallback chain for the call's accept-language metadata
+func requestLocales(ctx context.Context) []string {
+	return i18n.Chain(acceptedLocales(ctx)...)
+}
+
+// gameLocales returns the fallback chain for a game, preferring the player's chosen language
+func (s *Server) gameLocales(ctx context.Context, gameID int) []string {
+	return s.data.GameLocales(gameID, acceptedLocales(ctx))
+}
+
+// CreateUser creates a player, optionally with a preferred language
+func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	locales := requestLocales(ctx)
+
+	if req.GetUsername() == "" {
+		return nil, invalidArgument(locales, "Invalid request")
+	}
+
+	user, err := s.data.CreateUser(req.GetUsername(), req.GetLocale())
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to create user")
+	}
+
+	return toUser(user), nil
+}
+
+// GetUser gets a player by username
+func (s *Server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	locales := requestLocales(ctx)
+
+	user, err := s.data.GetUser(req.GetUsername())
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to get user")
+	}
+
+	return toUser(user), nil
+}
+
+// UpdateUser sets a player's preferred language; an empty locale clears it
+func (s *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.User, error) {
+	locales := requestLocales(ctx)
+
+	if req.Locale == nil {
+		return nil, invalidArgument(locales, "Invalid request")
+	}
+
+	user, err := s.data.SetUserLocale(req.GetUsername(), req.GetLocale())
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to update user")
+	}
+
+	return toUser(user), nil
+}
+
+// StartGame starts a game for a player
+func (s *Server) StartGame(ctx context.Context, req *pb.StartGameRequest) (*pb.StartGameResponse, error) {
+	locales := requestLocales(ctx)
+
+	if req.GetUsername() == "" {
+		return nil, invalidArgument(locales, "Invalid request")
+	}
+
+	gameID, err := s.data.CreateGame(req.GetUsername(), req.GetScoring())
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to create game")
+	}
+
+	return &pb.StartGameResponse{GameId: int64(gameID)}, nil
+}
+
+// GetNextQuestion gets the next unanswered question of a game
+func (s *Server) GetNextQuestion(ctx context.Context, req *pb.GetNextQuestionRequest) (*pb.Question, error) {
+	gameID := int(req.GetGameId())
+	locales := s.gameLocales(ctx, gameID)
+
+	question, err := s.data.NextQuestion(gameID, locales)
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to get next question")
+	}
+
+	return toQuestion(question), nil
+}
+
+// SubmitAnswer answers a question with an option, or a pin in pin games
+func (s *Server) SubmitAnswer(ctx context.Context, req *pb.SubmitAnswerRequest) (*pb.AnswerResult, error) {
+	locales := requestLocales(ctx)
+
+	if req.GetGameId() == 0 || req.GetQuestionId() == 0 {
+		return nil, invalidArgument(locales, "Invalid request")
+	}
+
+	gameID := int(req.GetGameId())
+	locales = s.gameLocales(ctx, gameID)
+
+	var pin *models.Pin
+	if req.Pin != nil {
+		pin = &models.Pin{Latitude: req.Pin.GetLatitude(), Longitude: req.Pin.GetLongitude()}
+	}
+
+	result, err := s.data.SubmitAnswer(gameID, int(req.GetQuestionId()), int(req.GetSelectedDestination()), pin, locales)
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to submit answer")
+	}
+
+	return toAnswerResult(result), nil
+}
+
+// GetGameResult gets the totals and every question of a game
+func (s *Server) GetGameResult(ctx context.Context, req *pb.GetGameResultRequest) (*pb.GameResult, error) {
+	locales := requestLocales(ctx)
+
+	result, err := s.data.GetGameResult(int(req.GetGameId()))
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to get game result")
+	}
+
+	return toGameResult(result), nil
+}
+
+// GetGameSummary gets the summary shown on challenge pages
+func (s *Server) GetGameSummary(ctx context.Context, req *pb.GetGameSummaryRequest) (*pb.GameSummary, error) {
+	locales := requestLocales(ctx)
+
+	summary, err := s.data.GetGameSummary(int(req.GetGameId()))
+	if err != nil {
+		return nil, statusError(ctx, locales, err, "Failed to get game summary")
+	}
+
+	return toGameSummary(summary), nil
+}
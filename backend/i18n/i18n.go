@@ -0,0 +1,93 @@
+// Package i18n resolves locale fallback chains and translates API messages.
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the language the dataset and messages are written in
+const DefaultLocale = "en"
+
+// Normalize returns the canonical form of a BCP 47 language tag, such as
+// "pt-BR" for "pt_br", or an empty string if the tag is not valid
+func Normalize(locale string) string {
+	if locale == "" {
+		return ""
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return ""
+	}
+	return tag.String()
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header, most
+// preferred first. Malformed headers yield no locales.
+func ParseAcceptLanguage(header string) []string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	locales := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag != language.Und {
+			locales = append(locales, tag.String())
+		}
+	}
+	return locales
+}
+
+// Chain builds the fallback chain for a list of preferred locales: each locale
+// is followed by its parents, and the chain ends with DefaultLocale. For
+// example "pt-BR", "es" gives pt-BR, pt, es, en. Empty or invalid entries
+// are skipped.
+func Chain(preferred ...string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+
+	for _, locale := range preferred {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+
+		for ; tag != language.Und; tag = tag.Parent() {
+			name := tag.String()
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			chain = append(chain, name)
+
+			// Nothing after the default locale can be used, it always has content
+			if name == DefaultLocale {
+				return chain
+			}
+		}
+	}
+
+	return append(chain, DefaultLocale)
+}
+
+// T translates a message into the first locale of the chain that has it,
+// returning the message unchanged if none does
+func T(locales []string, message string) string {
+	for _, locale := range locales {
+		if locale == DefaultLocale {
+			break
+		}
+		if translated, ok := messages[locale][message]; ok {
+			return translated
+		}
+	}
+	return message
+}
+
+// Sprintf translates a format string like T and then formats it
+func Sprintf(locales []string, format string, args ...interface{}) string {
+	return fmt.Sprintf(T(locales, format), args...)
+}
@@ -0,0 +1,146 @@
+package i18n
+
+// messages holds translations of player-facing API messages, keyed by locale
+// and then by the English message or format string
+var messages = map[string]map[string]string{
+	"es": {
+		"Failed to get random destination":                   "No se pudo obtener un destino aleatorio",
+		"Unknown region":                                     "Región desconocida",
+		"Invalid request":                                    "Solicitud no válida",
+		"Invalid locale":                                     "Idioma no válido",
+		"Username already exists":                            "El nombre de usuario ya existe",
+		"Failed to create user":                              "No se pudo crear el usuario",
+		"Failed to get user":                                 "No se pudo obtener el usuario",
+		"Failed to update user":                              "No se pudo actualizar el usuario",
+		"User not found":                                     "Usuario no encontrado",
+		`Scoring must be "standard", "proximity" or "pin"`:   `La puntuación debe ser "standard", "proximity" o "pin"`,
+		"Not enough destinations to create a game":           "No hay suficientes destinos para crear una partida",
+		"Failed to create game":                              "No se pudo crear la partida",
+		"Invalid game ID":                                    "ID de partida no válido",
+		"Game not found":                                     "Partida no encontrada",
+		"The game has no questions left":                     "La partida no tiene más preguntas",
+		"Failed to get next question":                        "No se pudo obtener la siguiente pregunta",
+		"Game ID mismatch":                                   "El ID de la partida no coincide",
+		"Question not found":                                 "Pregunta no encontrada",
+		"The game was archived and its questions removed":    "La partida se archivó y sus preguntas se eliminaron",
+		"Question already answered":                          "La pregunta ya fue respondida",
+		"Invalid answer":                                     "Respuesta no válida",
+		"Selected destination is not in the list of options": "El destino elegido no está entre las opciones",
+		"Pin games are answered with a pin":                  "En las partidas con mapa se responde con un marcador",
+		"Pin coordinates are out of range":                   "Las coordenadas del marcador están fuera de rango",
+		"Only pin games accept a pin":                        "Solo las partidas con mapa aceptan un marcador",
+		"Failed to submit answer":                            "No se pudo enviar la respuesta",
+		"Failed to get game result":                          "No se pudo obtener el resultado de la partida",
+		"Failed to get game summary":                         "No se pudo obtener el resumen de la partida",
+		"Where is %s located?":                               "¿Dónde está %s?",
+		"Invalid submission":                                 "Propuesta no válida",
+		"Failed to save submission":                          "No se pudo guardar la propuesta",
+		"Failed to list submissions":                         "No se pudieron obtener las propuestas",
+		"Too many requests, try again later":                 "Demasiadas solicitudes, inténtalo de nuevo más tarde",
+	},
+	"fr": {
+		"Failed to get random destination":                   "Impossible d'obtenir une destination aléatoire",
+		"Unknown region":                                     "Région inconnue",
+		"Invalid request":                                    "Requête invalide",
+		"Invalid locale":                                     "Langue invalide",
+		"Username already exists":                            "Ce nom d'utilisateur existe déjà",
+		"Failed to create user":                              "Impossible de créer l'utilisateur",
+		"Failed to get user":                                 "Impossible d'obtenir l'utilisateur",
+		"Failed to update user":                              "Impossible de mettre à jour l'utilisateur",
+		"User not found":                                     "Utilisateur introuvable",
+		`Scoring must be "standard", "proximity" or "pin"`:   `Le mode de score doit être "standard", "proximity" ou "pin"`,
+		"Not enough destinations to create a game":           "Pas assez de destinations pour créer une partie",
+		"Failed to create game":                              "Impossible de créer la partie",
+		"Invalid game ID":                                    "Identifiant de partie invalide",
+		"Game not found":                                     "Partie introuvable",
+		"The game has no questions left":                     "La partie n'a plus de questions",
+		"Failed to get next question":                        "Impossible d'obtenir la question suivante",
+		"Game ID mismatch":                                   "L'identifiant de partie ne correspond pas",
+		"Question not found":                                 "Question introuvable",
+		"The game was archived and its questions removed":    "La partie a été archivée et ses questions supprimées",
+		"Question already answered":                          "Question déjà répondue",
+		"Invalid answer":                                     "Réponse invalide",
+		"Selected destination is not in the list of options": "La destination choisie ne fait pas partie des options",
+		"Pin games are answered with a pin":                  "Les parties sur carte se jouent avec un repère",
+		"Pin coordinates are out of range":                   "Les coordonnées du repère sont hors limites",
+		"Only pin games accept a pin":                        "Seules les parties sur carte acceptent un repère",
+		"Failed to submit answer":                            "Impossible d'envoyer la réponse",
+		"Failed to get game result":                          "Impossible d'obtenir le résultat de la partie",
+		"Failed to get game summary":                         "Impossible d'obtenir le résumé de la partie",
+		"Where is %s located?":                               "Où se trouve %s ?",
+		"Invalid submission":                                 "Proposition invalide",
+		"Failed to save submission":                          "Impossible d'enregistrer la proposition",
+		"Failed to list submissions":                         "Impossible de lister les propositions",
+		"Too many requests, try again later":                 "Trop de requêtes, réessayez plus tard",
+	},
+	"de": {
+		"Failed to get random destination":                   "Zufälliges Reiseziel konnte nicht geladen werden",
+		"Unknown region":                                     "Unbekannte Region",
+		"Invalid request":                                    "Ungültige Anfrage",
+		"Invalid locale":                                     "Ungültige Sprache",
+		"Username already exists":                            "Benutzername existiert bereits",
+		"Failed to create user":                              "Benutzer konnte nicht angelegt werden",
+		"Failed to get user":                                 "Benutzer konnte nicht geladen werden",
+		"Failed to update user":                              "Benutzer konnte nicht aktualisiert werden",
+		"User not found":                                     "Benutzer nicht gefunden",
+		`Scoring must be "standard", "proximity" or "pin"`:   `Die Wertung muss "standard", "proximity" oder "pin" sein`,
+		"Not enough destinations to create a game":           "Nicht genug Reiseziele für ein Spiel",
+		"Failed to create game":                              "Spiel konnte nicht erstellt werden",
+		"Invalid game ID":                                    "Ungültige Spiel-ID",
+		"Game not found":                                     "Spiel nicht gefunden",
+		"The game has no questions left":                     "Das Spiel hat keine Fragen mehr",
+		"Failed to get next question":                        "Nächste Frage konnte nicht geladen werden",
+		"Game ID mismatch":                                   "Spiel-ID stimmt nicht überein",
+		"Question not found":                                 "Frage nicht gefunden",
+		"The game was archived and its questions removed":    "Das Spiel wurde archiviert und seine Fragen entfernt",
+		"Question already answered":                          "Frage wurde bereits beantwortet",
+		"Invalid answer":                                     "Ungültige Antwort",
+		"Selected destination is not in the list of options": "Das gewählte Reiseziel gehört nicht zu den Optionen",
+		"Pin games are answered with a pin":                  "Kartenspiele werden mit einer Markierung beantwortet",
+		"Pin coordinates are out of range":                   "Die Koordinaten der Markierung liegen außerhalb des gültigen Bereichs",
+		"Only pin games accept a pin":                        "Nur Kartenspiele akzeptieren eine Markierung",
+		"Failed to submit answer":                            "Antwort konnte nicht gesendet werden",
+		"Failed to get game result":                          "Spielergebnis konnte nicht geladen werden",
+		"Failed to get game summary":                         "Spielzusammenfassung konnte nicht geladen werden",
+		"Where is %s located?":                               "Wo liegt %s?",
+		"Invalid submission":                                 "Ungültiger Vorschlag",
+		"Failed to save submission":                          "Vorschlag konnte nicht gespeichert werden",
+		"Failed to list submissions":                         "Vorschläge konnten nicht geladen werden",
+		"Too many requests, try again later":                 "Zu viele Anfragen, bitte später erneut versuchen",
+	},
+	"pt": {
+		"Failed to get random destination":                   "Não foi possível obter um destino aleatório",
+		"Unknown region":                                     "Região desconhecida",
+		"Invalid request":                                    "Requisição inválida",
+		"Invalid locale":                                     "Idioma inválido",
+		"Username already exists":                            "O nome de usuário já existe",
+		"Failed to create user":                              "Não foi possível criar o usuário",
+		"Failed to get user":                                 "Não foi possível obter o usuário",
+		"Failed to update user":                              "Não foi possível atualizar o usuário",
+		"User not found":                                     "Usuário não encontrado",
+		`Scoring must be "standard", "proximity" or "pin"`:   `A pontuação deve ser "standard", "proximity" ou "pin"`,
+		"Not enough destinations to create a game":           "Não há destinos suficientes para criar um jogo",
+		"Failed to create game":                              "Não foi possível criar o jogo",
+		"Invalid game ID":                                    "ID de jogo inválido",
+		"Game not found":                                     "Jogo não encontrado",
+		"The game has no questions left":                     "O jogo não tem mais perguntas",
+		"Failed to get next question":                        "Não foi possível obter a próxima pergunta",
+		"Game ID mismatch":                                   "O ID do jogo não confere",
+		"Question not found":                                 "Pergunta não encontrada",
+		"The game was archived and its questions removed":    "A partida foi arquivada e suas perguntas removidas",
+		"Question already answered":                          "A pergunta já foi respondida",
+		"Invalid answer":                                     "Resposta inválida",
+		"Selected destination is not in the list of options": "O destino escolhido não está entre as opções",
+		"Pin games are answered with a pin":                  "Jogos de mapa são respondidos com um marcador",
+		"Pin coordinates are out of range":                   "As coordenadas do marcador estão fora do intervalo",
+		"Only pin games accept a pin":                        "Apenas jogos de mapa aceitam um marcador",
+		"Failed to submit answer":                            "Não foi possível enviar a resposta",
+		"Failed to get game result":                          "Não foi possível obter o resultado do jogo",
+		"Failed to get game summary":                         "Não foi possível obter o resumo do jogo",
+		"Where is %s located?":                               "Onde fica %s?",
+		"Invalid submission":                                 "Sugestão inválida",
+		"Failed to save submission":                          "Não foi possível salvar a sugestão",
+		"Failed to list submissions":                         "Não foi possível listar as sugestões",
+		"Too many requests, try again later":                 "Muitas solicitações, tente novamente mais tarde",
+	},
+}
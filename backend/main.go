@@ -1,43 +1,147 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shubhsherl/globetrotter/backend/api"
+	"github.com/shubhsherl/globetrotter/backend/backup"
 	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/grpcapi"
+	"github.com/shubhsherl/globetrotter/backend/services"
+	"github.com/shubhsherl/globetrotter/backend/services/images"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	log.Println("Starting Globetrotter application...")
 
 	// Set up database
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./data/globetrotter.db"
-	}
-	log.Printf("Using database at: %s", dbPath)
-
-	// Ensure the database directory exists
-	dbDir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		log.Fatalf("Failed to create database directory: %v", err)
-	}
+	dsn := db.DSN()
+	log.Printf("Using database at: %s", db.Redact(dsn))
 
-	if err := db.InitDB(dbPath); err != nil {
+	database, err := db.Open(dsn)
+	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
+	defer database.Close()
 	log.Println("Database initialized successfully")
 
-	// Initialize database instance
-	database := db.GetDB()
-
 	// Initialize API services
 	api.InitServices(database)
 	log.Println("API services initialized")
 
+	// Cache destination photos locally, fetching missing ones in the background
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "./data/media"
+	}
+	fetchInterval := time.Hour
+	if value := os.Getenv("IMAGE_FETCH_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid IMAGE_FETCH_INTERVAL %q", value)
+		}
+		fetchInterval = interval
+	}
+
+	var provider images.Provider
+	if apiKey := os.Getenv("PEXELS_API_KEY"); apiKey != "" {
+		provider = images.NewPexelsProvider(apiKey)
+	} else {
+		log.Println("PEXELS_API_KEY not set, only pre-seeded destination images will be used")
+	}
+	go images.NewFetcher(database, provider, mediaDir).Run(context.Background(), fetchInterval)
+	log.Printf("Serving destination images from: %s", mediaDir)
+
+	// Aggregate clue and destination difficulty in the background
+	analyticsInterval := 15 * time.Minute
+	if value := os.Getenv("ANALYTICS_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid ANALYTICS_INTERVAL %q", value)
+		}
+		analyticsInterval = interval
+	}
+	go services.NewAnalyticsService(database).Run(context.Background(), analyticsInterval)
+
+	// Delete abandoned games and compact old ones in the background
+	retentionInterval := 6 * time.Hour
+	if value := os.Getenv("RETENTION_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid RETENTION_INTERVAL %q", value)
+		}
+		retentionInterval = interval
+	}
+	policy := services.RetentionPolicy{
+		AbandonedAfter: envDays("ABANDONED_GAME_DAYS"),
+		CompactAfter:   envDays("COMPACT_GAME_DAYS"),
+	}
+	if policy.Enabled() {
+		log.Printf("Game retention: %s", policy)
+		go services.NewRetentionService(database, policy).Run(context.Background(), retentionInterval)
+	} else {
+		log.Println("Game retention is off, set ABANDONED_GAME_DAYS or COMPACT_GAME_DAYS to remove old games")
+	}
+
+	// Snapshot the SQLite database in the background when BACKUP_INTERVAL is set
+	if value := os.Getenv("BACKUP_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid BACKUP_INTERVAL %q", value)
+		}
+		opts, err := backup.OptionsFromEnv()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if db.IsPostgres(dsn) {
+			log.Println("BACKUP_INTERVAL is ignored for PostgreSQL, back it up with pg_dump")
+		} else {
+			go backup.Run(context.Background(), dsn, opts, interval)
+			log.Printf("Backing up the database to %s every %s", opts.Dir, interval)
+		}
+	}
+
+	// Reload the destination dataset on SIGHUP; a dataset that fails
+	// validation is logged and the current destinations stay in place
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			log.Println("Received SIGHUP, reloading dataset...")
+			api.ReloadDataset()
+		}
+	}()
+
+	// Serve the gRPC API on its own port, backed by the same services
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port %s: %v", grpcPort, err)
+	}
+	go func() {
+		log.Printf("gRPC server starting on port %s...", grpcPort)
+		// Calls share the REST API's rate limits and buckets
+		server := grpcapi.New(api.Services(), grpc.UnaryInterceptor(grpcapi.LimitRate(api.RateLimit)))
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	// Set up Gin router
 	if gin.Mode() == gin.ReleaseMode {
 		log.Println("Running in release mode")
@@ -46,6 +150,30 @@ func main() {
 	}
 	r := gin.Default()
 
+	// Only trust X-Forwarded-For from known proxies, so clients can't pick
+	// their own IP to get around per-IP rate limits. By default these are
+	// the private networks a hosting platform's proxy connects from, which
+	// clients on the internet can't connect from.
+	proxies := defaultTrustedProxies
+	switch value := os.Getenv("TRUSTED_PROXIES"); value {
+	case "":
+	case "none":
+		proxies = nil
+	default:
+		proxies = nil
+		for _, proxy := range strings.Split(value, ",") {
+			proxies = append(proxies, strings.TrimSpace(proxy))
+		}
+	}
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES %q: %v", os.Getenv("TRUSTED_PROXIES"), err)
+	}
+	if len(proxies) > 0 {
+		log.Printf("Trusting X-Forwarded-For from: %s", strings.Join(proxies, ", "))
+	} else {
+		log.Println("Ignoring X-Forwarded-For, client IPs are the connecting addresses")
+	}
+
 	// Setup routes
 	api.SetupRoutes(r)
 	log.Println("API routes configured")
@@ -59,6 +187,9 @@ func main() {
 
 	// Serve static files for production
 	r.Static("/static", filepath.Join(webappPath, "static"))
+	media := gin.WrapH(http.StripPrefix(images.MediaRoute, images.Handler(mediaDir)))
+	r.GET(images.MediaRoute+"/*filepath", media)
+	r.HEAD(images.MediaRoute+"/*filepath", media)
 	r.StaticFile("/favicon.ico", filepath.Join(webappPath, "favicon.ico"))
 	r.StaticFile("/", filepath.Join(webappPath, "index.html"))
 
@@ -84,3 +215,24 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// defaultTrustedProxies are the proxies trusted when TRUSTED_PROXIES is
+// unset: loopback and the private IPv4 and IPv6 ranges
+var defaultTrustedProxies = []string{
+	"127.0.0.0/8", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10",
+	"::1/128", "fc00::/7",
+}
+
+// envDays reads a number of days from the environment variable name, 0 if
+// it is unset
+func envDays(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		log.Fatalf("Invalid %s %q", name, value)
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
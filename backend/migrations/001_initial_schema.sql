@@ -40,4 +40,11 @@ CREATE TABLE IF NOT EXISTS game_questions (
     selected_destination_id INTEGER,
     is_answered INTEGER DEFAULT 0,
     FOREIGN KEY (game_id) REFERENCES games (id)
-); 
\ No newline at end of file
+);
+
+-- migrate:down
+
+DROP TABLE IF EXISTS game_questions;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS destinations;
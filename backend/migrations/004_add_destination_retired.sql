@@ -0,0 +1,9 @@
+-- Migration: 004_add_destination_retired.sql
+-- Description: Allow destinations to be retired instead of deleted
+
+-- Retired destinations stay resolvable for existing games but are not used in new ones
+ALTER TABLE destinations ADD COLUMN retired INTEGER NOT NULL DEFAULT 0;
+
+-- migrate:down
+
+ALTER TABLE destinations DROP COLUMN retired;
@@ -0,0 +1,97 @@
+-- Migration: 005_normalize_destination_content.sql
+-- Description: Move clues, fun facts and trivia from JSON columns into their own tables
+
+CREATE TABLE IF NOT EXISTS destination_clues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    destination_id INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    text TEXT NOT NULL,
+    tags TEXT NOT NULL DEFAULT '',
+    retired INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+
+-- Holds both fun facts (kind = 'fun_fact') and trivia (kind = 'trivia')
+CREATE TABLE IF NOT EXISTS destination_facts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    destination_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    text TEXT NOT NULL,
+    retired INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_destination_clues_destination_id ON destination_clues(destination_id);
+CREATE INDEX IF NOT EXISTS idx_destination_facts_destination_id ON destination_facts(destination_id);
+
+INSERT INTO destination_clues (destination_id, position, text)
+SELECT d.id, j.key, j.value FROM destinations d, json_each(d.clues) j;
+
+INSERT INTO destination_facts (destination_id, kind, position, text)
+SELECT d.id, 'fun_fact', j.key, j.value FROM destinations d, json_each(d.fun_facts) j;
+
+INSERT INTO destination_facts (destination_id, kind, position, text)
+SELECT d.id, 'trivia', j.key, j.value FROM destinations d, json_each(d.trivia) j;
+
+-- Record which clue each question showed
+ALTER TABLE game_questions ADD COLUMN clue_id INTEGER REFERENCES destination_clues (id);
+
+UPDATE game_questions
+SET clue_id = (
+    SELECT c.id FROM destination_clues c
+    WHERE c.destination_id = game_questions.correct_destination_id AND c.text = game_questions.question
+    ORDER BY c.id LIMIT 1
+);
+
+ALTER TABLE destinations DROP COLUMN clues;
+ALTER TABLE destinations DROP COLUMN fun_facts;
+ALTER TABLE destinations DROP COLUMN trivia;
+
+-- migrate:down
+
+-- Retired clues and facts are not put back; older versions had no way to hide them
+ALTER TABLE destinations ADD COLUMN clues TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE destinations ADD COLUMN fun_facts TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE destinations ADD COLUMN trivia TEXT NOT NULL DEFAULT '[]';
+
+UPDATE destinations SET
+    clues = (SELECT json_group_array(text) FROM (
+        SELECT text FROM destination_clues c
+        WHERE c.destination_id = destinations.id AND c.retired = 0
+        ORDER BY c.position, c.id
+    )),
+    fun_facts = (SELECT json_group_array(text) FROM (
+        SELECT text FROM destination_facts f
+        WHERE f.destination_id = destinations.id AND f.kind = 'fun_fact' AND f.retired = 0
+        ORDER BY f.position, f.id
+    )),
+    trivia = (SELECT json_group_array(text) FROM (
+        SELECT text FROM destination_facts f
+        WHERE f.destination_id = destinations.id AND f.kind = 'trivia' AND f.retired = 0
+        ORDER BY f.position, f.id
+    ));
+
+-- clue_id may be named in a table foreign key, which SQLite can't drop, so the table is rebuilt
+CREATE TABLE game_questions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    game_id INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    options TEXT NOT NULL,
+    correct_destination_id INTEGER NOT NULL,
+    selected_destination_id INTEGER DEFAULT 0,
+    is_answered INTEGER DEFAULT 0,
+    FOREIGN KEY (game_id) REFERENCES games (id)
+);
+
+INSERT INTO game_questions_old (id, game_id, question, options, correct_destination_id, selected_destination_id, is_answered)
+SELECT id, game_id, question, options, correct_destination_id, selected_destination_id, is_answered FROM game_questions;
+
+DROP TABLE game_questions;
+ALTER TABLE game_questions_old RENAME TO game_questions;
+CREATE INDEX IF NOT EXISTS idx_game_questions_game_id ON game_questions(game_id);
+
+DROP INDEX IF EXISTS idx_destination_facts_destination_id;
+DROP INDEX IF EXISTS idx_destination_clues_destination_id;
+DROP TABLE IF EXISTS destination_facts;
+DROP TABLE IF EXISTS destination_clues;
@@ -0,0 +1,23 @@
+-- Migration: 006_add_coordinates_and_scoring.sql
+-- Description: Store destination coordinates and score answers by distance
+
+-- Coordinates are optional; destinations without them get no distance feedback
+ALTER TABLE destinations ADD COLUMN latitude REAL;
+ALTER TABLE destinations ADD COLUMN longitude REAL;
+
+-- 'standard' games only score correct answers, 'proximity' games also score near misses
+ALTER TABLE games ADD COLUMN scoring TEXT NOT NULL DEFAULT 'standard';
+ALTER TABLE games ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+
+-- Points earned by each answer and, for wrong answers, how far off it was
+ALTER TABLE game_questions ADD COLUMN points INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE game_questions ADD COLUMN distance_km REAL;
+
+-- migrate:down
+
+ALTER TABLE game_questions DROP COLUMN distance_km;
+ALTER TABLE game_questions DROP COLUMN points;
+ALTER TABLE games DROP COLUMN score;
+ALTER TABLE games DROP COLUMN scoring;
+ALTER TABLE destinations DROP COLUMN longitude;
+ALTER TABLE destinations DROP COLUMN latitude;
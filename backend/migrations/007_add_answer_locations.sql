@@ -0,0 +1,15 @@
+-- Migration: 007_add_answer_locations.sql
+-- Description: Record where pin answers landed and where the right answer was
+
+-- Pins are only set in pin games; the correct location is set for any answered question
+ALTER TABLE game_questions ADD COLUMN pin_latitude REAL;
+ALTER TABLE game_questions ADD COLUMN pin_longitude REAL;
+ALTER TABLE game_questions ADD COLUMN correct_latitude REAL;
+ALTER TABLE game_questions ADD COLUMN correct_longitude REAL;
+
+-- migrate:down
+
+ALTER TABLE game_questions DROP COLUMN correct_longitude;
+ALTER TABLE game_questions DROP COLUMN correct_latitude;
+ALTER TABLE game_questions DROP COLUMN pin_longitude;
+ALTER TABLE game_questions DROP COLUMN pin_latitude;
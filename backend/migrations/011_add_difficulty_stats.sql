@@ -0,0 +1,28 @@
+-- Migration: 011_add_difficulty_stats.sql
+-- Description: Store per-destination and per-clue answer statistics, rebuilt from game_questions in the background
+
+CREATE TABLE IF NOT EXISTS destination_stats (
+    destination_id INTEGER PRIMARY KEY,
+    answered INTEGER NOT NULL DEFAULT 0,
+    correct INTEGER NOT NULL DEFAULT 0,
+    top_wrong_destination_id INTEGER,
+    top_wrong_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+
+CREATE TABLE IF NOT EXISTS clue_stats (
+    clue_id INTEGER PRIMARY KEY,
+    destination_id INTEGER NOT NULL,
+    answered INTEGER NOT NULL DEFAULT 0,
+    correct INTEGER NOT NULL DEFAULT 0,
+    top_wrong_destination_id INTEGER,
+    top_wrong_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (clue_id) REFERENCES destination_clues (id)
+);
+
+-- migrate:down
+
+DROP TABLE IF EXISTS clue_stats;
+DROP TABLE IF EXISTS destination_stats;
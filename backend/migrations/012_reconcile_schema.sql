@@ -0,0 +1,53 @@
+-- Migration: 012_reconcile_schema.sql
+-- Description: Bring databases built by earlier migrations in line with those the server created itself
+
+-- Unanswered questions had a NULL selected_destination_id here but 0 in databases
+-- the server created, and only 0 can be read back. SQLite can't change a column
+-- default, so the table is rebuilt, which also adds the missing foreign keys.
+CREATE TABLE game_questions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    game_id INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    options TEXT NOT NULL,
+    correct_destination_id INTEGER NOT NULL,
+    selected_destination_id INTEGER DEFAULT 0,
+    is_answered INTEGER DEFAULT 0,
+    clue_id INTEGER,
+    points INTEGER NOT NULL DEFAULT 0,
+    distance_km REAL,
+    pin_latitude REAL,
+    pin_longitude REAL,
+    correct_latitude REAL,
+    correct_longitude REAL,
+    FOREIGN KEY (game_id) REFERENCES games (id),
+    FOREIGN KEY (correct_destination_id) REFERENCES destinations (id),
+    FOREIGN KEY (clue_id) REFERENCES destination_clues (id)
+);
+
+INSERT INTO game_questions_new (
+    id, game_id, question, options, correct_destination_id, selected_destination_id, is_answered,
+    clue_id, points, distance_km, pin_latitude, pin_longitude, correct_latitude, correct_longitude
+)
+SELECT id, game_id, question, options, correct_destination_id, COALESCE(selected_destination_id, 0), is_answered,
+       clue_id, points, distance_km, pin_latitude, pin_longitude, correct_latitude, correct_longitude
+FROM game_questions;
+
+DROP TABLE game_questions;
+ALTER TABLE game_questions_new RENAME TO game_questions;
+
+-- Databases the server created never got the indexes from 003
+CREATE INDEX IF NOT EXISTS idx_games_user_id ON games(user_id);
+CREATE INDEX IF NOT EXISTS idx_game_questions_game_id ON game_questions(game_id);
+CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
+
+-- Applied migrations are now tracked by version in schema_migrations
+DROP TABLE IF EXISTS migrations;
+
+-- migrate:down
+
+-- The column default and indexes are compatible with older versions and stay
+CREATE TABLE IF NOT EXISTS migrations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
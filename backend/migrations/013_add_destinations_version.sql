@@ -0,0 +1,67 @@
+-- Migration: 013_add_destinations_version.sql
+-- Description: Count changes to destinations and their content, so servers caching them can tell when to reload
+
+-- A single row, bumped by every change whether it comes from the server, an import or another server
+CREATE TABLE IF NOT EXISTS destinations_version (
+    version INTEGER NOT NULL
+);
+
+INSERT INTO destinations_version (version) SELECT 0 WHERE NOT EXISTS (SELECT 1 FROM destinations_version);
+
+CREATE TRIGGER IF NOT EXISTS destinations_insert_bump_version AFTER INSERT ON destinations
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destinations_update_bump_version AFTER UPDATE ON destinations
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destinations_delete_bump_version AFTER DELETE ON destinations
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destination_clues_insert_bump_version AFTER INSERT ON destination_clues
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destination_clues_update_bump_version AFTER UPDATE ON destination_clues
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destination_clues_delete_bump_version AFTER DELETE ON destination_clues
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destination_facts_insert_bump_version AFTER INSERT ON destination_facts
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destination_facts_update_bump_version AFTER UPDATE ON destination_facts
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS destination_facts_delete_bump_version AFTER DELETE ON destination_facts
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+END;
+
+-- migrate:down
+
+DROP TRIGGER IF EXISTS destinations_insert_bump_version;
+DROP TRIGGER IF EXISTS destinations_update_bump_version;
+DROP TRIGGER IF EXISTS destinations_delete_bump_version;
+DROP TRIGGER IF EXISTS destination_clues_insert_bump_version;
+DROP TRIGGER IF EXISTS destination_clues_update_bump_version;
+DROP TRIGGER IF EXISTS destination_clues_delete_bump_version;
+DROP TRIGGER IF EXISTS destination_facts_insert_bump_version;
+DROP TRIGGER IF EXISTS destination_facts_update_bump_version;
+DROP TRIGGER IF EXISTS destination_facts_delete_bump_version;
+DROP TABLE IF EXISTS destinations_version;
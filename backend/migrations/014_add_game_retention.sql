@@ -0,0 +1,32 @@
+-- Migration: 014_add_game_retention.sql
+-- Description: Let old games drop their questions while their answers keep counting in the statistics
+
+-- Set when a game's questions were removed; the game row keeps its totals and score
+ALTER TABLE games ADD COLUMN compacted_at TIMESTAMP;
+
+-- The destination whose photo a compacted game's summary and challenge page show
+ALTER TABLE games ADD COLUMN cover_destination_id INTEGER;
+
+-- Answers of compacted games, counted per answer, clue and picked option.
+-- clue_id is 0 for questions asked without a clue.
+CREATE TABLE IF NOT EXISTS archived_answers (
+    correct_destination_id INTEGER NOT NULL,
+    clue_id INTEGER NOT NULL DEFAULT 0,
+    selected_destination_id INTEGER NOT NULL,
+    answers INTEGER NOT NULL,
+    PRIMARY KEY (correct_destination_id, clue_id, selected_destination_id),
+    FOREIGN KEY (correct_destination_id) REFERENCES destinations (id),
+    FOREIGN KEY (selected_destination_id) REFERENCES destinations (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_answers_selected ON archived_answers(selected_destination_id);
+CREATE INDEX IF NOT EXISTS idx_games_created_at ON games(created_at);
+
+-- migrate:down
+
+-- Compacted games stay without their questions
+DROP INDEX IF EXISTS idx_games_created_at;
+DROP INDEX IF EXISTS idx_archived_answers_selected;
+DROP TABLE IF EXISTS archived_answers;
+ALTER TABLE games DROP COLUMN cover_destination_id;
+ALTER TABLE games DROP COLUMN compacted_at;
@@ -0,0 +1,13 @@
+// Package migrations embeds the SQL migrations that build the database
+// schema. Each file is named NNN_description.sql, where NNN is its version,
+// and holds the statements that apply it followed by a "-- migrate:down"
+// line and the statements that revert it. The SQLite migrations are at the
+// top level and the PostgreSQL ones, with the same versions, in postgres/.
+package migrations
+
+import "embed"
+
+// FS holds the migration files of both dialects
+//
+//go:embed *.sql postgres/*.sql
+var FS embed.FS
@@ -0,0 +1,48 @@
+-- Migration: 001_initial_schema.sql
+-- Description: Initial database schema
+
+-- Create destinations table
+CREATE TABLE IF NOT EXISTS destinations (
+    id SERIAL PRIMARY KEY,
+    city TEXT NOT NULL,
+    country TEXT NOT NULL,
+    clues JSONB NOT NULL,
+    fun_facts JSONB NOT NULL,
+    trivia JSONB NOT NULL
+);
+
+-- Create users table
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create games table
+CREATE TABLE IF NOT EXISTS games (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    total_questions INTEGER DEFAULT 5,
+    total_correct INTEGER DEFAULT 0,
+    total_incorrect INTEGER DEFAULT 0,
+    total_answered INTEGER DEFAULT 0
+);
+
+-- Create game_questions table
+CREATE TABLE IF NOT EXISTS game_questions (
+    id SERIAL PRIMARY KEY,
+    game_id INTEGER NOT NULL REFERENCES games (id),
+    question TEXT NOT NULL,
+    options JSONB NOT NULL,
+    correct_destination_id INTEGER NOT NULL,
+    selected_destination_id INTEGER,
+    is_answered INTEGER DEFAULT 0
+);
+
+-- migrate:down
+
+DROP TABLE IF EXISTS game_questions;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS destinations;
@@ -0,0 +1,79 @@
+-- Migration: 005_normalize_destination_content.sql
+-- Description: Move clues, fun facts and trivia from JSON columns into their own tables
+
+CREATE TABLE IF NOT EXISTS destination_clues (
+    id SERIAL PRIMARY KEY,
+    destination_id INTEGER NOT NULL REFERENCES destinations (id),
+    position INTEGER NOT NULL DEFAULT 0,
+    text TEXT NOT NULL,
+    tags TEXT NOT NULL DEFAULT '',
+    retired INTEGER NOT NULL DEFAULT 0
+);
+
+-- Holds both fun facts (kind = 'fun_fact') and trivia (kind = 'trivia')
+CREATE TABLE IF NOT EXISTS destination_facts (
+    id SERIAL PRIMARY KEY,
+    destination_id INTEGER NOT NULL REFERENCES destinations (id),
+    kind TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    text TEXT NOT NULL,
+    retired INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_destination_clues_destination_id ON destination_clues(destination_id);
+CREATE INDEX IF NOT EXISTS idx_destination_facts_destination_id ON destination_facts(destination_id);
+
+-- Positions are zero-based, like the keys json_each gives in SQLite
+INSERT INTO destination_clues (destination_id, position, text)
+SELECT d.id, j.position - 1, j.text
+FROM destinations d, jsonb_array_elements_text(d.clues) WITH ORDINALITY AS j(text, position);
+
+INSERT INTO destination_facts (destination_id, kind, position, text)
+SELECT d.id, 'fun_fact', j.position - 1, j.text
+FROM destinations d, jsonb_array_elements_text(d.fun_facts) WITH ORDINALITY AS j(text, position);
+
+INSERT INTO destination_facts (destination_id, kind, position, text)
+SELECT d.id, 'trivia', j.position - 1, j.text
+FROM destinations d, jsonb_array_elements_text(d.trivia) WITH ORDINALITY AS j(text, position);
+
+-- Record which clue each question showed
+ALTER TABLE game_questions ADD COLUMN IF NOT EXISTS clue_id INTEGER REFERENCES destination_clues (id);
+
+UPDATE game_questions
+SET clue_id = (
+    SELECT c.id FROM destination_clues c
+    WHERE c.destination_id = game_questions.correct_destination_id AND c.text = game_questions.question
+    ORDER BY c.id LIMIT 1
+);
+
+ALTER TABLE destinations DROP COLUMN clues;
+ALTER TABLE destinations DROP COLUMN fun_facts;
+ALTER TABLE destinations DROP COLUMN trivia;
+
+-- migrate:down
+
+-- Retired clues and facts are not put back; older versions had no way to hide them
+ALTER TABLE destinations ADD COLUMN clues JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE destinations ADD COLUMN fun_facts JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE destinations ADD COLUMN trivia JSONB NOT NULL DEFAULT '[]';
+
+UPDATE destinations SET
+    clues = COALESCE((
+        SELECT jsonb_agg(c.text ORDER BY c.position, c.id) FROM destination_clues c
+        WHERE c.destination_id = destinations.id AND c.retired = 0
+    ), '[]'),
+    fun_facts = COALESCE((
+        SELECT jsonb_agg(f.text ORDER BY f.position, f.id) FROM destination_facts f
+        WHERE f.destination_id = destinations.id AND f.kind = 'fun_fact' AND f.retired = 0
+    ), '[]'),
+    trivia = COALESCE((
+        SELECT jsonb_agg(f.text ORDER BY f.position, f.id) FROM destination_facts f
+        WHERE f.destination_id = destinations.id AND f.kind = 'trivia' AND f.retired = 0
+    ), '[]');
+
+ALTER TABLE game_questions DROP COLUMN clue_id;
+
+DROP INDEX IF EXISTS idx_destination_facts_destination_id;
+DROP INDEX IF EXISTS idx_destination_clues_destination_id;
+DROP TABLE IF EXISTS destination_facts;
+DROP TABLE IF EXISTS destination_clues;
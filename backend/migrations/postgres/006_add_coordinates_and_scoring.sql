@@ -0,0 +1,23 @@
+-- Migration: 006_add_coordinates_and_scoring.sql
+-- Description: Store destination coordinates and score answers by distance
+
+-- Coordinates are optional; destinations without them get no distance feedback
+ALTER TABLE destinations ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE destinations ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+-- 'standard' games only score correct answers, 'proximity' games also score near misses
+ALTER TABLE games ADD COLUMN IF NOT EXISTS scoring TEXT NOT NULL DEFAULT 'standard';
+ALTER TABLE games ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+
+-- Points earned by each answer and, for wrong answers, how far off it was
+ALTER TABLE game_questions ADD COLUMN IF NOT EXISTS points INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE game_questions ADD COLUMN IF NOT EXISTS distance_km DOUBLE PRECISION;
+
+-- migrate:down
+
+ALTER TABLE game_questions DROP COLUMN distance_km;
+ALTER TABLE game_questions DROP COLUMN points;
+ALTER TABLE games DROP COLUMN score;
+ALTER TABLE games DROP COLUMN scoring;
+ALTER TABLE destinations DROP COLUMN longitude;
+ALTER TABLE destinations DROP COLUMN latitude;
@@ -0,0 +1,15 @@
+-- Migration: 007_add_answer_locations.sql
+-- Description: Record where pin answers landed and where the right answer was
+
+-- Pins are only set in pin games; the correct location is set for any answered question
+ALTER TABLE game_questions ADD COLUMN IF NOT EXISTS pin_latitude DOUBLE PRECISION;
+ALTER TABLE game_questions ADD COLUMN IF NOT EXISTS pin_longitude DOUBLE PRECISION;
+ALTER TABLE game_questions ADD COLUMN IF NOT EXISTS correct_latitude DOUBLE PRECISION;
+ALTER TABLE game_questions ADD COLUMN IF NOT EXISTS correct_longitude DOUBLE PRECISION;
+
+-- migrate:down
+
+ALTER TABLE game_questions DROP COLUMN correct_longitude;
+ALTER TABLE game_questions DROP COLUMN correct_latitude;
+ALTER TABLE game_questions DROP COLUMN pin_longitude;
+ALTER TABLE game_questions DROP COLUMN pin_latitude;
@@ -0,0 +1,38 @@
+-- Migration: 008_add_translations.sql
+-- Description: Store translations of destinations, clues and facts, and users' preferred language
+
+-- Empty city or country means that part of the name is not translated
+CREATE TABLE IF NOT EXISTS destination_translations (
+    destination_id INTEGER NOT NULL,
+    locale TEXT NOT NULL,
+    city TEXT NOT NULL DEFAULT '',
+    country TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (destination_id, locale),
+    FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+
+CREATE TABLE IF NOT EXISTS clue_translations (
+    clue_id INTEGER NOT NULL,
+    locale TEXT NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (clue_id, locale),
+    FOREIGN KEY (clue_id) REFERENCES destination_clues (id)
+);
+
+CREATE TABLE IF NOT EXISTS fact_translations (
+    fact_id INTEGER NOT NULL,
+    locale TEXT NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (fact_id, locale),
+    FOREIGN KEY (fact_id) REFERENCES destination_facts (id)
+);
+
+-- Empty means the user follows the Accept-Language header
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE users DROP COLUMN locale;
+DROP TABLE IF EXISTS fact_translations;
+DROP TABLE IF EXISTS clue_translations;
+DROP TABLE IF EXISTS destination_translations;
@@ -0,0 +1,19 @@
+-- Migration: 009_add_destination_images.sql
+-- Description: Cache one photo per destination on local disk, with its attribution
+
+-- file is relative to the media directory the server is started with
+CREATE TABLE IF NOT EXISTS destination_images (
+    destination_id INTEGER PRIMARY KEY,
+    file TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    photographer TEXT NOT NULL DEFAULT '',
+    photographer_url TEXT NOT NULL DEFAULT '',
+    source_url TEXT NOT NULL DEFAULT '',
+    alt TEXT NOT NULL DEFAULT '',
+    fetched_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+
+-- migrate:down
+
+DROP TABLE IF EXISTS destination_images;
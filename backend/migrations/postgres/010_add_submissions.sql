@@ -0,0 +1,37 @@
+-- Migration: 010_add_submissions.sql
+-- Description: Store destinations and clues proposed by players until they are reviewed, and credit approved ones
+
+-- clues, fun_fact and trivia hold JSONB arrays of strings
+CREATE TABLE IF NOT EXISTS submissions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    destination_id INTEGER,
+    city TEXT NOT NULL DEFAULT '',
+    country TEXT NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    clues JSONB NOT NULL DEFAULT '[]',
+    fun_fact JSONB NOT NULL DEFAULT '[]',
+    trivia JSONB NOT NULL DEFAULT '[]',
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_submissions_status ON submissions(status);
+CREATE INDEX IF NOT EXISTS idx_submissions_user_id ON submissions(user_id);
+
+ALTER TABLE destinations ADD COLUMN IF NOT EXISTS submission_id INTEGER REFERENCES submissions (id);
+ALTER TABLE destination_clues ADD COLUMN IF NOT EXISTS submission_id INTEGER REFERENCES submissions (id);
+
+-- migrate:down
+
+ALTER TABLE destination_clues DROP COLUMN submission_id;
+ALTER TABLE destinations DROP COLUMN submission_id;
+DROP INDEX IF EXISTS idx_submissions_user_id;
+DROP INDEX IF EXISTS idx_submissions_status;
+DROP TABLE IF EXISTS submissions;
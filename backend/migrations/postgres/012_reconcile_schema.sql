@@ -0,0 +1,24 @@
+-- Migration: 012_reconcile_schema.sql
+-- Description: Bring databases built by earlier migrations in line with those the server created itself
+
+-- Unanswered questions had a NULL selected_destination_id here but 0 in databases
+-- the server created, and only 0 can be read back
+UPDATE game_questions SET selected_destination_id = 0 WHERE selected_destination_id IS NULL;
+ALTER TABLE game_questions ALTER COLUMN selected_destination_id SET DEFAULT 0;
+
+-- Databases the server created never got the indexes from 003
+CREATE INDEX IF NOT EXISTS idx_games_user_id ON games(user_id);
+CREATE INDEX IF NOT EXISTS idx_game_questions_game_id ON game_questions(game_id);
+CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
+
+-- Applied migrations are now tracked by version in schema_migrations
+DROP TABLE IF EXISTS migrations;
+
+-- migrate:down
+
+-- The column default and indexes are compatible with older versions and stay
+CREATE TABLE IF NOT EXISTS migrations (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
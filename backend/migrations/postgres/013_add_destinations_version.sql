@@ -0,0 +1,33 @@
+-- Migration: 013_add_destinations_version.sql
+-- Description: Count changes to destinations and their content, so servers caching them can tell when to reload
+
+-- A single row, bumped by every change whether it comes from the server, an import or another server
+CREATE TABLE IF NOT EXISTS destinations_version (
+    version BIGINT NOT NULL
+);
+
+INSERT INTO destinations_version (version) SELECT 0 WHERE NOT EXISTS (SELECT 1 FROM destinations_version);
+
+CREATE OR REPLACE FUNCTION bump_destinations_version() RETURNS trigger AS $$
+BEGIN
+    UPDATE destinations_version SET version = version + 1;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER destinations_bump_version AFTER INSERT OR UPDATE OR DELETE ON destinations
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_destinations_version();
+
+CREATE TRIGGER destination_clues_bump_version AFTER INSERT OR UPDATE OR DELETE ON destination_clues
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_destinations_version();
+
+CREATE TRIGGER destination_facts_bump_version AFTER INSERT OR UPDATE OR DELETE ON destination_facts
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_destinations_version();
+
+-- migrate:down
+
+DROP TRIGGER IF EXISTS destinations_bump_version ON destinations;
+DROP TRIGGER IF EXISTS destination_clues_bump_version ON destination_clues;
+DROP TRIGGER IF EXISTS destination_facts_bump_version ON destination_facts;
+DROP FUNCTION IF EXISTS bump_destinations_version();
+DROP TABLE IF EXISTS destinations_version;
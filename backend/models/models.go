@@ -8,18 +8,103 @@ import (
 
 // Destination represents a location in the game
 type Destination struct {
-	ID      int      `json:"id,omitempty" db:"id"`
-	City    string   `json:"city" db:"city"`
-	Country string   `json:"country" db:"country"`
-	Clues   []string `json:"clues" db:"-"`
-	FunFact []string `json:"fun_fact" db:"-"`
-	Trivia  []string `json:"trivia" db:"-"`
+	ID          int      `json:"id,omitempty" yaml:"id,omitempty" db:"id"`
+	City        string   `json:"city" yaml:"city" db:"city"`
+	Country     string   `json:"country" yaml:"country" db:"country"`
+	Latitude    *float64 `json:"latitude,omitempty" yaml:"latitude,omitempty" db:"latitude"`
+	Longitude   *float64 `json:"longitude,omitempty" yaml:"longitude,omitempty" db:"longitude"`
+	Clues       []string `json:"clues" yaml:"clues" db:"-"`
+	FunFact     []string `json:"fun_fact" yaml:"fun_fact" db:"-"`
+	Trivia      []string `json:"trivia" yaml:"trivia" db:"-"`
+	Retired     bool     `json:"retired,omitempty" yaml:"retired,omitempty" db:"retired"` // Retired destinations are kept for existing games but excluded from new ones
+	ClueIDs     []int    `json:"-" yaml:"-" db:"-"`                                       // IDs of Clues, in the same order
+	FunFactIDs  []int    `json:"-" yaml:"-" db:"-"`                                       // IDs of FunFact, in the same order
+	TriviaIDs   []int    `json:"-" yaml:"-" db:"-"`                                       // IDs of Trivia, in the same order
+	SubmittedBy string   `json:"submitted_by,omitempty" yaml:"-" db:"submitted_by"`       // Player whose submission added the destination
+}
+
+// DestinationTranslation holds a destination's name in another language.
+// Empty fields are not translated.
+type DestinationTranslation struct {
+	DestinationID int    `json:"destination_id" db:"destination_id"`
+	Locale        string `json:"locale" db:"locale"`
+	City          string `json:"city" db:"city"`
+	Country       string `json:"country" db:"country"`
+}
+
+// TextTranslation holds a clue, fun fact or trivia entry in another language
+type TextTranslation struct {
+	TextID int    `json:"text_id" db:"text_id"` // ID of the clue or fact
+	Locale string `json:"locale" db:"locale"`
+	Text   string `json:"text" db:"text"`
+}
+
+// Clue is a single clue about a destination
+type Clue struct {
+	ID            int      `json:"id" db:"id"`
+	DestinationID int      `json:"destination_id" db:"destination_id"`
+	Text          string   `json:"text" db:"text"`
+	Tags          []string `json:"tags" db:"-"`
+	Retired       bool     `json:"retired" db:"retired"`                     // Retired clues are no longer used in new games
+	SubmittedBy   string   `json:"submitted_by,omitempty" db:"submitted_by"` // Player whose submission added the clue
+}
+
+// DestinationImage is a locally cached photo of a destination and its attribution
+type DestinationImage struct {
+	DestinationID   int       `json:"-" db:"destination_id"`
+	File            string    `json:"-" db:"file"` // Path relative to the media directory
+	URL             string    `json:"url" db:"-"`  // Where the file is served from
+	Provider        string    `json:"provider" db:"provider"`
+	Photographer    string    `json:"photographer,omitempty" db:"photographer"`
+	PhotographerURL string    `json:"photographer_url,omitempty" db:"photographer_url"`
+	SourceURL       string    `json:"source_url,omitempty" db:"source_url"` // Page the photo was taken from
+	Alt             string    `json:"alt,omitempty" db:"alt"`
+	FetchedAt       time.Time `json:"-" db:"fetched_at"`
+}
+
+// Submission kinds
+const (
+	SubmissionDestination = "destination" // A new destination
+	SubmissionClues       = "clues"       // More clues, fun facts or trivia for an existing destination
+)
+
+// Submission statuses
+const (
+	SubmissionPending  = "pending"
+	SubmissionApproved = "approved"
+	SubmissionRejected = "rejected"
+)
+
+// Submission is content proposed by a player, waiting for or past review
+type Submission struct {
+	ID            int        `json:"id" db:"id"`
+	Kind          string     `json:"kind" db:"kind"`
+	Status        string     `json:"status" db:"status"`
+	UserID        int        `json:"-" db:"user_id"`
+	Username      string     `json:"username" db:"username"`
+	DestinationID *int       `json:"destination_id,omitempty" db:"destination_id"` // Destination the clues are for, or the one an approved submission was merged into
+	City          string     `json:"city,omitempty" db:"city"`
+	Country       string     `json:"country,omitempty" db:"country"`
+	Latitude      *float64   `json:"latitude,omitempty" db:"latitude"`
+	Longitude     *float64   `json:"longitude,omitempty" db:"longitude"`
+	Clues         []string   `json:"clues" db:"-"`
+	FunFact       []string   `json:"fun_fact" db:"-"`
+	Trivia        []string   `json:"trivia" db:"-"`
+	ReviewNote    string     `json:"review_note,omitempty" db:"review_note"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+
+	// Filled in for the review queue
+	DuplicateOf       *int     `json:"duplicate_of,omitempty" db:"-"`       // Existing destination with the same city and country
+	PendingDuplicates []int    `json:"pending_duplicates,omitempty" db:"-"` // Other pending submissions for the same destination
+	DuplicateClues    []string `json:"duplicate_clues,omitempty" db:"-"`    // Submitted clues the destination already has
 }
 
 // User represents a player in the game
 type User struct {
 	ID        int    `json:"id,omitempty" db:"id"`
 	Username  string `json:"username" db:"username"`
+	Locale    string `json:"locale,omitempty" db:"locale"` // Preferred language, empty to follow Accept-Language
 	CreatedAt string `json:"created_at,omitempty" db:"created_at"`
 }
 
@@ -32,26 +117,43 @@ type GameQuestion struct {
 	CorrectAnswer string   `json:"correct_answer" db:"correct_answer"`
 }
 
+// Scoring modes for a game
+const (
+	ScoringStandard  = "standard"  // Points only for correct answers
+	ScoringProximity = "proximity" // Wrong answers earn partial points the closer they are
+	ScoringPin       = "pin"       // No options; players drop a pin scored by its distance
+)
+
 // Game represents a game session
 type Game struct {
-	ID             int       `json:"id,omitempty" db:"id"`
-	UserID         int       `json:"user_id" db:"user_id"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	TotalQuestions int       `json:"total_questions" db:"total_questions"`
-	TotalCorrect   int       `json:"total_correct" db:"total_correct"`
-	TotalIncorrect int       `json:"total_incorrect" db:"total_incorrect"`
-	TotalAnswered  int       `json:"total_answered" db:"total_answered"`
+	ID             int        `json:"id,omitempty" db:"id"`
+	UserID         int        `json:"user_id" db:"user_id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	TotalQuestions int        `json:"total_questions" db:"total_questions"`
+	TotalCorrect   int        `json:"total_correct" db:"total_correct"`
+	TotalIncorrect int        `json:"total_incorrect" db:"total_incorrect"`
+	TotalAnswered  int        `json:"total_answered" db:"total_answered"`
+	Scoring        string     `json:"scoring" db:"scoring"`
+	Score          int        `json:"score" db:"score"`
+	CompactedAt    *time.Time `json:"compacted_at,omitempty" db:"compacted_at"` // When the game's questions were removed by the retention job
 }
 
 // GameQuestionDetail represents a question in a game
 type GameQuestionDetail struct {
-	ID                    int    `json:"id,omitempty" db:"id"`
-	GameID                int    `json:"game_id" db:"game_id"`
-	Question              string `json:"question" db:"question"`
-	OptionDestinationIDs  []int  `json:"options" db:"-"`                                               // Changed from []string to []int to store destination IDs
-	CorrectDestinationID  int    `json:"correct_destination_id,omitempty" db:"correct_destination_id"` // Not sent to client during game
-	SelectedDestinationID int    `json:"selected_destination_id,omitempty" db:"selected_destination_id"`
-	IsAnswered            int    `json:"is_answered" db:"is_answered"` // 0 = false, 1 = true
+	ID                    int      `json:"id,omitempty" db:"id"`
+	GameID                int      `json:"game_id" db:"game_id"`
+	Question              string   `json:"question" db:"question"`
+	OptionDestinationIDs  []int    `json:"options" db:"-"`                                               // Changed from []string to []int to store destination IDs
+	CorrectDestinationID  int      `json:"correct_destination_id,omitempty" db:"correct_destination_id"` // Not sent to client during game
+	SelectedDestinationID int      `json:"selected_destination_id,omitempty" db:"selected_destination_id"`
+	IsAnswered            int      `json:"is_answered" db:"is_answered"`   // 0 = false, 1 = true
+	ClueID                int      `json:"clue_id,omitempty" db:"clue_id"` // Clue shown as the question, 0 for fallback questions
+	Points                int      `json:"points" db:"points"`
+	DistanceKm            *float64 `json:"distance_km,omitempty" db:"distance_km"`   // Distance from the answer to the correct destination
+	PinLatitude           *float64 `json:"pin_latitude,omitempty" db:"pin_latitude"` // Pin dropped in pin games
+	PinLongitude          *float64 `json:"pin_longitude,omitempty" db:"pin_longitude"`
+	CorrectLatitude       *float64 `json:"correct_latitude,omitempty" db:"correct_latitude"` // Location of the correct destination, recorded once answered
+	CorrectLongitude      *float64 `json:"correct_longitude,omitempty" db:"correct_longitude"`
 }
 
 // NextQuestionResponse represents the response for the next question API
@@ -61,24 +163,39 @@ type NextQuestionResponse struct {
 	Question       string         `json:"question" db:"question"`
 	Options        []string       `json:"options" db:"-"`
 	OptionsDisplay map[int]string `json:"options_display" db:"-"`
+	Scoring        string         `json:"scoring" db:"scoring"` // "pin" games expect a pin instead of an option
 	HasNext        bool           `json:"has_next" db:"has_next"`
 }
 
-// SubmitAnswerRequest represents the request for submitting an answer
+// Pin is a location dropped on the map by the player
+type Pin struct {
+	Latitude  float64 `json:"latitude" db:"latitude"`
+	Longitude float64 `json:"longitude" db:"longitude"`
+}
+
+// SubmitAnswerRequest represents the request for submitting an answer.
+// Multiple choice games send SelectedDestination, pin games send Pin.
 type SubmitAnswerRequest struct {
-	GameID              int `json:"game_id" binding:"required" db:"game_id"`
-	QuestionID          int `json:"question_id" binding:"required" db:"question_id"`
-	SelectedDestination int `json:"selected_destination" binding:"required" db:"selected_destination"`
+	GameID              int  `json:"game_id" binding:"required" db:"game_id"`
+	QuestionID          int  `json:"question_id" binding:"required" db:"question_id"`
+	SelectedDestination int  `json:"selected_destination,omitempty" db:"selected_destination"`
+	Pin                 *Pin `json:"pin,omitempty" db:"-"`
 }
 
 // SubmitAnswerResponse represents the response for submitting an answer
 type SubmitAnswerResponse struct {
-	Correct         bool   `json:"correct" db:"correct"`
-	FunFact         string `json:"fun_fact,omitempty" db:"fun_fact"` // Sent when answer is correct
-	Trivia          string `json:"trivia,omitempty" db:"trivia"`     // Sent when answer is incorrect
-	CorrectCity     string `json:"correct_city" db:"correct_city"`
-	CorrectCountry  string `json:"correct_country" db:"correct_country"`
-	CorrectOptionID int    `json:"correct_option_id" db:"correct_option_id"`
+	Correct          bool              `json:"correct" db:"correct"`
+	FunFact          string            `json:"fun_fact,omitempty" db:"fun_fact"` // Sent when answer is correct
+	Trivia           string            `json:"trivia,omitempty" db:"trivia"`     // Sent when answer is incorrect
+	CorrectCity      string            `json:"correct_city" db:"correct_city"`
+	CorrectCountry   string            `json:"correct_country" db:"correct_country"`
+	CorrectOptionID  int               `json:"correct_option_id" db:"correct_option_id"`
+	Points           int               `json:"points" db:"points"`
+	DistanceKm       *float64          `json:"distance_km,omitempty" db:"distance_km"` // Sent when the answer is incorrect and both locations are known
+	CorrectLatitude  *float64          `json:"correct_latitude,omitempty" db:"correct_latitude"`
+	CorrectLongitude *float64          `json:"correct_longitude,omitempty" db:"correct_longitude"`
+	Image            *DestinationImage `json:"image,omitempty" db:"-"`             // Photo of the correct destination, when one is cached
+	ClueSubmittedBy  string            `json:"clue_submitted_by,omitempty" db:"-"` // Player who contributed the clue
 }
 
 // GameResult represents the result of a completed game
@@ -87,41 +204,12 @@ type GameResult struct {
 	TotalQuestions int                  `json:"total_questions" db:"total_questions"`
 	TotalCorrect   int                  `json:"total_correct" db:"total_correct"`
 	TotalIncorrect int                  `json:"total_incorrect" db:"total_incorrect"`
+	Scoring        string               `json:"scoring" db:"scoring"`
+	Score          int                  `json:"score" db:"score"`
+	Compacted      bool                 `json:"compacted,omitempty" db:"-"` // The questions were removed by the retention job; the totals remain
 	Questions      []GameQuestionDetail `json:"questions" db:"-"`
 }
 
-// DBDestination is used for database operations
-type DBDestination struct {
-	ID       int    `db:"id"`
-	City     string `db:"city"`
-	Country  string `db:"country"`
-	Clues    string `db:"clues"`     // JSON string
-	FunFacts string `db:"fun_facts"` // JSON string
-	Trivia   string `db:"trivia"`    // JSON string
-}
-
-// ToDestination converts a DBDestination to a Destination
-func (d *DBDestination) ToDestination() (*Destination, error) {
-	dest := &Destination{
-		ID:      d.ID,
-		City:    d.City,
-		Country: d.Country,
-	}
-
-	// Unmarshal JSON strings to slices
-	if err := json.Unmarshal([]byte(d.Clues), &dest.Clues); err != nil {
-		return nil, err
-	}
-	if err := json.Unmarshal([]byte(d.FunFacts), &dest.FunFact); err != nil {
-		return nil, err
-	}
-	if err := json.Unmarshal([]byte(d.Trivia), &dest.Trivia); err != nil {
-		return nil, err
-	}
-
-	return dest, nil
-}
-
 // GenerateOptions creates multiple choice options for a destination
 func GenerateOptions(correctDest *Destination, allDests []*Destination) []string {
 	// Create correct answer
@@ -177,3 +265,103 @@ type GameSummary struct {
 	TotalCorrect   int    `json:"total_correct" db:"total_correct"`
 	CreatedAt      string `json:"created_at" db:"created_at"`
 }
+
+// DifficultyStats is how players fared on a destination, or on one of its clues
+type DifficultyStats struct {
+	DestinationID         int       `json:"destination_id" db:"destination_id"`
+	City                  string    `json:"city" db:"city"`
+	Country               string    `json:"country" db:"country"`
+	ClueID                int       `json:"clue_id,omitempty" db:"clue_id"`
+	Clue                  string    `json:"clue,omitempty" db:"clue"`
+	Answered              int       `json:"answered" db:"answered"` // Sample size
+	Correct               int       `json:"correct" db:"correct"`
+	CorrectRate           float64   `json:"correct_rate" db:"-"`
+	TopWrongDestinationID *int      `json:"top_wrong_destination_id,omitempty" db:"top_wrong_destination_id"` // Wrong option picked most often
+	TopWrongOption        string    `json:"top_wrong_option,omitempty" db:"top_wrong_option"`
+	TopWrongCount         int       `json:"top_wrong_count" db:"top_wrong_count"`
+	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DifficultyReport lists the destinations and clues whose answers look off
+type DifficultyReport struct {
+	UpdatedAt              *time.Time        `json:"updated_at"`  // When the statistics were last aggregated
+	MinSamples             int               `json:"min_samples"` // Answers needed before an item is flagged
+	TooEasyClues           []DifficultyStats `json:"too_easy_clues"`
+	MisleadingDestinations []DifficultyStats `json:"misleading_destinations"` // A wrong option is picked more often than the answer
+	MisleadingClues        []DifficultyStats `json:"misleading_clues"`
+}
+
+// DatasetReload reports what reloading the destination dataset changed
+type DatasetReload struct {
+	Path      string    `json:"path"`
+	Added     int       `json:"added"`
+	Updated   int       `json:"updated"`
+	Unchanged int       `json:"unchanged"`
+	Missing   int       `json:"missing"` // Stored destinations the dataset no longer has
+	Deleted   int       `json:"deleted"` // Missing destinations removed by a prune
+	Retired   int       `json:"retired"` // Missing destinations retired by a prune because games used them
+	Locales   []string  `json:"locales"` // Translation files applied
+	Warnings  []string  `json:"warnings"`
+	LoadedAt  time.Time `json:"loaded_at"`
+}
+
+// RetentionResult reports what one run of the game retention job removed
+type RetentionResult struct {
+	DeletedGames   int `json:"deleted_games"`   // Never answered, removed entirely
+	CompactedGames int `json:"compacted_games"` // Answered, questions removed and totals kept
+}
+
+// Actors recorded in the audit log for changes not made by a player
+const (
+	ActorAdmin  = "admin"  // The admin API, when the request doesn't name the admin
+	ActorSystem = "system" // The server itself, such as a dataset reload on SIGHUP
+	ActorCLI    = "cli"    // Command-line tools such as cmd/dataset
+)
+
+// AdminActor is the audit log actor of the admin who made a change through
+// the admin API, or ActorAdmin if the admin isn't named
+func AdminActor(name string) string {
+	if name == "" {
+		return ActorAdmin
+	}
+	return ActorAdmin + ":" + name
+}
+
+// UserActor is the audit log actor of a player changing their own account
+func UserActor(username string) string {
+	return "user:" + username
+}
+
+// Audit log actions
+const (
+	AuditDestinationCreate = "destination.create"
+	AuditDestinationUpdate = "destination.update"
+	AuditDestinationDelete = "destination.delete"
+	AuditDestinationRetire = "destination.retire" // Deleting a destination games use retires it instead
+	AuditClueUpdate        = "clue.update"
+	AuditSubmissionApprove = "submission.approve"
+	AuditSubmissionReject  = "submission.reject"
+	AuditUserCreate        = "user.create"
+	AuditUserUpdate        = "user.update"
+)
+
+// Audit log target types
+const (
+	AuditTargetDestination = "destination"
+	AuditTargetClue        = "clue"
+	AuditTargetSubmission  = "submission"
+	AuditTargetUser        = "user"
+)
+
+// AuditEntry records one change: who made it, to what, and the target before
+// and after it. Before is null for creations and After for deletions.
+type AuditEntry struct {
+	ID         int             `json:"id" db:"id"`
+	Actor      string          `json:"actor" db:"actor"`
+	Action     string          `json:"action" db:"action"`
+	TargetType string          `json:"target_type" db:"target_type"`
+	TargetID   int             `json:"target_id" db:"target_id"`
+	Before     json.RawMessage `json:"before" db:"-"`
+	After      json.RawMessage `json:"after" db:"-"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
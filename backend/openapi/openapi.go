@@ -0,0 +1,218 @@
+// Package openapi holds the OpenAPI document of the HTTP API and checks
+// requests and responses against it.
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+func init() {
+	// Report where a value doesn't match its schema on one line, without
+	// printing the schema and the value
+	openapi3.SchemaErrorDetailsDisabled = true
+}
+
+var (
+	loadOnce sync.Once
+	spec     *openapi3.T
+	loadErr  error
+)
+
+// Load parses and validates the OpenAPI document. The document is embedded
+// in the binary, so it is only parsed once.
+func Load() (*openapi3.T, error) {
+	loadOnce.Do(func() {
+		loader := openapi3.NewLoader()
+		doc, err := loader.LoadFromData(specYAML)
+		if err != nil {
+			loadErr = fmt.Errorf("failed to parse the OpenAPI document: %v", err)
+			return
+		}
+		if err := doc.Validate(loader.Context); err != nil {
+			loadErr = fmt.Errorf("invalid OpenAPI document: %v", err)
+			return
+		}
+		spec = doc
+	})
+	return spec, loadErr
+}
+
+// Kinds of mismatches between the API and its document
+const (
+	MismatchRoute    = "route"    // The route is not in the document
+	MismatchRequest  = "request"  // The request doesn't match its operation
+	MismatchResponse = "response" // The response doesn't match its operation
+)
+
+// Mismatch is a request or response that doesn't match the OpenAPI document
+type Mismatch struct {
+	Kind        string
+	Method      string
+	Path        string
+	OperationID string // Empty for route mismatches
+	Status      int    // Response status, 0 for rejected requests
+	Problems    []string
+}
+
+// String describes the mismatch on one line
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s %s (%d): %s does not match the API specification: %s",
+		m.Method, m.Path, m.Status, m.Kind, strings.Join(m.Problems, "; "))
+}
+
+// Validator checks requests and responses of the API against the OpenAPI
+// document. Requests that don't match are rejected by Reject, with 400 by
+// default, unless ReportOnly is set; every mismatch is passed to OnMismatch,
+// which logs it by default.
+type Validator struct {
+	router     routers.Router
+	ReportOnly bool
+	OnMismatch func(Mismatch)
+	Reject     func(c *gin.Context, problems []string)
+}
+
+// NewValidator creates a validator for the document
+func NewValidator(doc *openapi3.T) (*Validator, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route the OpenAPI document: %v", err)
+	}
+	return &Validator{
+		router: router,
+		OnMismatch: func(m Mismatch) {
+			log.Printf("OpenAPI: %s", m)
+		},
+		Reject: func(c *gin.Context, problems []string) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":    "Request does not match the API specification",
+				"problems": problems,
+			})
+		},
+	}, nil
+}
+
+// options are the checks made on requests and responses. Authentication is
+// left to the handlers, and undocumented response statuses are mismatches.
+var options = &openapi3filter.Options{
+	AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	IncludeResponseStatus: true,
+	MultiError:            true,
+}
+
+// Handle validates the request, runs the rest of the handlers and validates
+// their response. It is meant to be used as gin middleware.
+func (v *Validator) Handle(c *gin.Context) {
+	req := c.Request
+	route, pathParams, err := v.router.FindRoute(req)
+	if err != nil {
+		v.OnMismatch(Mismatch{
+			Kind:     MismatchRoute,
+			Method:   req.Method,
+			Path:     req.URL.Path,
+			Problems: []string{err.Error()},
+		})
+		c.Next()
+		return
+	}
+
+	// The handlers parse request bodies as JSON whatever their Content-Type
+	// says, so validate them as JSON rather than reject them for it
+	if route.Operation.RequestBody != nil && req.Body != nil && req.Body != http.NoBody {
+		if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType != "application/json" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    options,
+	}
+	if err := openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+		problems := Problems(err)
+		v.OnMismatch(Mismatch{
+			Kind:        MismatchRequest,
+			Method:      req.Method,
+			Path:        req.URL.Path,
+			OperationID: route.Operation.OperationID,
+			Problems:    problems,
+		})
+		if !v.ReportOnly {
+			v.Reject(c, problems)
+			return
+		}
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+
+	status := recorder.Status()
+	err = openapi3filter.ValidateResponse(req.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 recorder.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options:                options,
+	})
+	if err != nil {
+		v.OnMismatch(Mismatch{
+			Kind:        MismatchResponse,
+			Method:      req.Method,
+			Path:        req.URL.Path,
+			OperationID: route.Operation.OperationID,
+			Status:      status,
+			Problems:    Problems(err),
+		})
+	}
+}
+
+// Problems lists the messages of a validation error, one per problem
+func Problems(err error) []string {
+	// Only the top level is split, nested errors keep the context of the
+	// parameter or body they are about
+	multi, ok := err.(openapi3.MultiError)
+	if !ok {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	for _, err := range multi {
+		problems = append(problems, Problems(err)...)
+	}
+	return problems
+}
+
+// responseRecorder keeps a copy of the response body for validation
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,1453 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: globetrotter/v1/globetrotter.proto
+
+// The gRPC API of Globetrotter, served next to the REST API
+
+package globetrotterv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type User struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	// Preferred language, empty to follow accept-language
+	Locale        string `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	CreatedAt     string `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *User) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// Optional preferred language, e.g. "es" or "pt-BR"
+	Locale        string `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type UpdateUserRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// Required; empty clears the preferred language
+	Locale        *string `protobuf:"bytes,2,opt,name=locale,proto3,oneof" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetLocale() string {
+	if x != nil && x.Locale != nil {
+		return *x.Locale
+	}
+	return ""
+}
+
+type StartGameRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// "standard" (default), "proximity" or "pin"
+	Scoring       string `protobuf:"bytes,2,opt,name=scoring,proto3" json:"scoring,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartGameRequest) Reset() {
+	*x = StartGameRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartGameRequest) ProtoMessage() {}
+
+func (x *StartGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartGameRequest.ProtoReflect.Descriptor instead.
+func (*StartGameRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StartGameRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *StartGameRequest) GetScoring() string {
+	if x != nil {
+		return x.Scoring
+	}
+	return ""
+}
+
+type StartGameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartGameResponse) Reset() {
+	*x = StartGameResponse{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartGameResponse) ProtoMessage() {}
+
+func (x *StartGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartGameResponse.ProtoReflect.Descriptor instead.
+func (*StartGameResponse) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StartGameResponse) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+type GetNextQuestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNextQuestionRequest) Reset() {
+	*x = GetNextQuestionRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNextQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNextQuestionRequest) ProtoMessage() {}
+
+func (x *GetNextQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNextQuestionRequest.ProtoReflect.Descriptor instead.
+func (*GetNextQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetNextQuestionRequest) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+type Question struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	GameId     int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	QuestionId int64                  `protobuf:"varint,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Question   string                 `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	// Names of the options by destination ID, empty in pin games
+	OptionsDisplay map[int64]string `protobuf:"bytes,4,rep,name=options_display,json=optionsDisplay,proto3" json:"options_display,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// "pin" games expect a pin instead of an option
+	Scoring       string `protobuf:"bytes,5,opt,name=scoring,proto3" json:"scoring,omitempty"`
+	HasNext       bool   `protobuf:"varint,6,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Question) Reset() {
+	*x = Question{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Question) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Question) ProtoMessage() {}
+
+func (x *Question) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Question.ProtoReflect.Descriptor instead.
+func (*Question) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Question) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *Question) GetQuestionId() int64 {
+	if x != nil {
+		return x.QuestionId
+	}
+	return 0
+}
+
+func (x *Question) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *Question) GetOptionsDisplay() map[int64]string {
+	if x != nil {
+		return x.OptionsDisplay
+	}
+	return nil
+}
+
+func (x *Question) GetScoring() string {
+	if x != nil {
+		return x.Scoring
+	}
+	return ""
+}
+
+func (x *Question) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+type Pin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pin) Reset() {
+	*x = Pin{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pin) ProtoMessage() {}
+
+func (x *Pin) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pin.ProtoReflect.Descriptor instead.
+func (*Pin) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Pin) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Pin) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type SubmitAnswerRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	GameId     int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	QuestionId int64                  `protobuf:"varint,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	// Destination ID of the chosen option, in standard and proximity games
+	SelectedDestination int64 `protobuf:"varint,3,opt,name=selected_destination,json=selectedDestination,proto3" json:"selected_destination,omitempty"`
+	// Location dropped on the map, in pin games
+	Pin           *Pin `protobuf:"bytes,4,opt,name=pin,proto3" json:"pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitAnswerRequest) Reset() {
+	*x = SubmitAnswerRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitAnswerRequest) ProtoMessage() {}
+
+func (x *SubmitAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitAnswerRequest.ProtoReflect.Descriptor instead.
+func (*SubmitAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SubmitAnswerRequest) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *SubmitAnswerRequest) GetQuestionId() int64 {
+	if x != nil {
+		return x.QuestionId
+	}
+	return 0
+}
+
+func (x *SubmitAnswerRequest) GetSelectedDestination() int64 {
+	if x != nil {
+		return x.SelectedDestination
+	}
+	return 0
+}
+
+func (x *SubmitAnswerRequest) GetPin() *Pin {
+	if x != nil {
+		return x.Pin
+	}
+	return nil
+}
+
+type DestinationImage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Url             string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Provider        string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Photographer    string                 `protobuf:"bytes,3,opt,name=photographer,proto3" json:"photographer,omitempty"`
+	PhotographerUrl string                 `protobuf:"bytes,4,opt,name=photographer_url,json=photographerUrl,proto3" json:"photographer_url,omitempty"`
+	SourceUrl       string                 `protobuf:"bytes,5,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"`
+	Alt             string                 `protobuf:"bytes,6,opt,name=alt,proto3" json:"alt,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DestinationImage) Reset() {
+	*x = DestinationImage{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationImage) ProtoMessage() {}
+
+func (x *DestinationImage) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationImage.ProtoReflect.Descriptor instead.
+func (*DestinationImage) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DestinationImage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DestinationImage) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *DestinationImage) GetPhotographer() string {
+	if x != nil {
+		return x.Photographer
+	}
+	return ""
+}
+
+func (x *DestinationImage) GetPhotographerUrl() string {
+	if x != nil {
+		return x.PhotographerUrl
+	}
+	return ""
+}
+
+func (x *DestinationImage) GetSourceUrl() string {
+	if x != nil {
+		return x.SourceUrl
+	}
+	return ""
+}
+
+func (x *DestinationImage) GetAlt() string {
+	if x != nil {
+		return x.Alt
+	}
+	return ""
+}
+
+type AnswerResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Correct bool                   `protobuf:"varint,1,opt,name=correct,proto3" json:"correct,omitempty"`
+	// Sent when the answer is correct
+	FunFact string `protobuf:"bytes,2,opt,name=fun_fact,json=funFact,proto3" json:"fun_fact,omitempty"`
+	// Sent when the answer is incorrect
+	Trivia          string `protobuf:"bytes,3,opt,name=trivia,proto3" json:"trivia,omitempty"`
+	CorrectCity     string `protobuf:"bytes,4,opt,name=correct_city,json=correctCity,proto3" json:"correct_city,omitempty"`
+	CorrectCountry  string `protobuf:"bytes,5,opt,name=correct_country,json=correctCountry,proto3" json:"correct_country,omitempty"`
+	CorrectOptionId int64  `protobuf:"varint,6,opt,name=correct_option_id,json=correctOptionId,proto3" json:"correct_option_id,omitempty"`
+	Points          int64  `protobuf:"varint,7,opt,name=points,proto3" json:"points,omitempty"`
+	// Sent when the answer is incorrect and both locations are known
+	DistanceKm       *float64 `protobuf:"fixed64,8,opt,name=distance_km,json=distanceKm,proto3,oneof" json:"distance_km,omitempty"`
+	CorrectLatitude  *float64 `protobuf:"fixed64,9,opt,name=correct_latitude,json=correctLatitude,proto3,oneof" json:"correct_latitude,omitempty"`
+	CorrectLongitude *float64 `protobuf:"fixed64,10,opt,name=correct_longitude,json=correctLongitude,proto3,oneof" json:"correct_longitude,omitempty"`
+	// Photo of the correct destination, when one is cached
+	Image *DestinationImage `protobuf:"bytes,11,opt,name=image,proto3" json:"image,omitempty"`
+	// Player who contributed the clue
+	ClueSubmittedBy string `protobuf:"bytes,12,opt,name=clue_submitted_by,json=clueSubmittedBy,proto3" json:"clue_submitted_by,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AnswerResult) Reset() {
+	*x = AnswerResult{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnswerResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnswerResult) ProtoMessage() {}
+
+func (x *AnswerResult) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnswerResult.ProtoReflect.Descriptor instead.
+func (*AnswerResult) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AnswerResult) GetCorrect() bool {
+	if x != nil {
+		return x.Correct
+	}
+	return false
+}
+
+func (x *AnswerResult) GetFunFact() string {
+	if x != nil {
+		return x.FunFact
+	}
+	return ""
+}
+
+func (x *AnswerResult) GetTrivia() string {
+	if x != nil {
+		return x.Trivia
+	}
+	return ""
+}
+
+func (x *AnswerResult) GetCorrectCity() string {
+	if x != nil {
+		return x.CorrectCity
+	}
+	return ""
+}
+
+func (x *AnswerResult) GetCorrectCountry() string {
+	if x != nil {
+		return x.CorrectCountry
+	}
+	return ""
+}
+
+func (x *AnswerResult) GetCorrectOptionId() int64 {
+	if x != nil {
+		return x.CorrectOptionId
+	}
+	return 0
+}
+
+func (x *AnswerResult) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *AnswerResult) GetDistanceKm() float64 {
+	if x != nil && x.DistanceKm != nil {
+		return *x.DistanceKm
+	}
+	return 0
+}
+
+func (x *AnswerResult) GetCorrectLatitude() float64 {
+	if x != nil && x.CorrectLatitude != nil {
+		return *x.CorrectLatitude
+	}
+	return 0
+}
+
+func (x *AnswerResult) GetCorrectLongitude() float64 {
+	if x != nil && x.CorrectLongitude != nil {
+		return *x.CorrectLongitude
+	}
+	return 0
+}
+
+func (x *AnswerResult) GetImage() *DestinationImage {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *AnswerResult) GetClueSubmittedBy() string {
+	if x != nil {
+		return x.ClueSubmittedBy
+	}
+	return ""
+}
+
+type GetGameResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGameResultRequest) Reset() {
+	*x = GetGameResultRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGameResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGameResultRequest) ProtoMessage() {}
+
+func (x *GetGameResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGameResultRequest.ProtoReflect.Descriptor instead.
+func (*GetGameResultRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetGameResultRequest) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+type QuestionResult struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Question string                 `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	// Destination IDs of the options
+	Options               []int64 `protobuf:"varint,3,rep,packed,name=options,proto3" json:"options,omitempty"`
+	CorrectDestinationId  int64   `protobuf:"varint,4,opt,name=correct_destination_id,json=correctDestinationId,proto3" json:"correct_destination_id,omitempty"`
+	SelectedDestinationId int64   `protobuf:"varint,5,opt,name=selected_destination_id,json=selectedDestinationId,proto3" json:"selected_destination_id,omitempty"`
+	Answered              bool    `protobuf:"varint,6,opt,name=answered,proto3" json:"answered,omitempty"`
+	// Clue shown as the question, 0 for fallback questions
+	ClueId           int64    `protobuf:"varint,7,opt,name=clue_id,json=clueId,proto3" json:"clue_id,omitempty"`
+	Points           int64    `protobuf:"varint,8,opt,name=points,proto3" json:"points,omitempty"`
+	DistanceKm       *float64 `protobuf:"fixed64,9,opt,name=distance_km,json=distanceKm,proto3,oneof" json:"distance_km,omitempty"`
+	PinLatitude      *float64 `protobuf:"fixed64,10,opt,name=pin_latitude,json=pinLatitude,proto3,oneof" json:"pin_latitude,omitempty"`
+	PinLongitude     *float64 `protobuf:"fixed64,11,opt,name=pin_longitude,json=pinLongitude,proto3,oneof" json:"pin_longitude,omitempty"`
+	CorrectLatitude  *float64 `protobuf:"fixed64,12,opt,name=correct_latitude,json=correctLatitude,proto3,oneof" json:"correct_latitude,omitempty"`
+	CorrectLongitude *float64 `protobuf:"fixed64,13,opt,name=correct_longitude,json=correctLongitude,proto3,oneof" json:"correct_longitude,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QuestionResult) Reset() {
+	*x = QuestionResult{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuestionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionResult) ProtoMessage() {}
+
+func (x *QuestionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionResult.ProtoReflect.Descriptor instead.
+func (*QuestionResult) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QuestionResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *QuestionResult) GetOptions() []int64 {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *QuestionResult) GetCorrectDestinationId() int64 {
+	if x != nil {
+		return x.CorrectDestinationId
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetSelectedDestinationId() int64 {
+	if x != nil {
+		return x.SelectedDestinationId
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetAnswered() bool {
+	if x != nil {
+		return x.Answered
+	}
+	return false
+}
+
+func (x *QuestionResult) GetClueId() int64 {
+	if x != nil {
+		return x.ClueId
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetPoints() int64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetDistanceKm() float64 {
+	if x != nil && x.DistanceKm != nil {
+		return *x.DistanceKm
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetPinLatitude() float64 {
+	if x != nil && x.PinLatitude != nil {
+		return *x.PinLatitude
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetPinLongitude() float64 {
+	if x != nil && x.PinLongitude != nil {
+		return *x.PinLongitude
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetCorrectLatitude() float64 {
+	if x != nil && x.CorrectLatitude != nil {
+		return *x.CorrectLatitude
+	}
+	return 0
+}
+
+func (x *QuestionResult) GetCorrectLongitude() float64 {
+	if x != nil && x.CorrectLongitude != nil {
+		return *x.CorrectLongitude
+	}
+	return 0
+}
+
+type GameResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	GameId         int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	TotalQuestions int64                  `protobuf:"varint,2,opt,name=total_questions,json=totalQuestions,proto3" json:"total_questions,omitempty"`
+	TotalCorrect   int64                  `protobuf:"varint,3,opt,name=total_correct,json=totalCorrect,proto3" json:"total_correct,omitempty"`
+	TotalIncorrect int64                  `protobuf:"varint,4,opt,name=total_incorrect,json=totalIncorrect,proto3" json:"total_incorrect,omitempty"`
+	Scoring        string                 `protobuf:"bytes,5,opt,name=scoring,proto3" json:"scoring,omitempty"`
+	Score          int64                  `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`
+	// The questions were removed by the retention job; the totals remain
+	Compacted     bool              `protobuf:"varint,7,opt,name=compacted,proto3" json:"compacted,omitempty"`
+	Questions     []*QuestionResult `protobuf:"bytes,8,rep,name=questions,proto3" json:"questions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameResult) Reset() {
+	*x = GameResult{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameResult) ProtoMessage() {}
+
+func (x *GameResult) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameResult.ProtoReflect.Descriptor instead.
+func (*GameResult) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GameResult) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *GameResult) GetTotalQuestions() int64 {
+	if x != nil {
+		return x.TotalQuestions
+	}
+	return 0
+}
+
+func (x *GameResult) GetTotalCorrect() int64 {
+	if x != nil {
+		return x.TotalCorrect
+	}
+	return 0
+}
+
+func (x *GameResult) GetTotalIncorrect() int64 {
+	if x != nil {
+		return x.TotalIncorrect
+	}
+	return 0
+}
+
+func (x *GameResult) GetScoring() string {
+	if x != nil {
+		return x.Scoring
+	}
+	return ""
+}
+
+func (x *GameResult) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *GameResult) GetCompacted() bool {
+	if x != nil {
+		return x.Compacted
+	}
+	return false
+}
+
+func (x *GameResult) GetQuestions() []*QuestionResult {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+type GetGameSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGameSummaryRequest) Reset() {
+	*x = GetGameSummaryRequest{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGameSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGameSummaryRequest) ProtoMessage() {}
+
+func (x *GetGameSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGameSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetGameSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetGameSummaryRequest) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+type GameSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	GameId         int64                  `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Username       string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	ImageUrl       string                 `protobuf:"bytes,3,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	TotalQuestions int64                  `protobuf:"varint,4,opt,name=total_questions,json=totalQuestions,proto3" json:"total_questions,omitempty"`
+	TotalAnswered  int64                  `protobuf:"varint,5,opt,name=total_answered,json=totalAnswered,proto3" json:"total_answered,omitempty"`
+	TotalCorrect   int64                  `protobuf:"varint,6,opt,name=total_correct,json=totalCorrect,proto3" json:"total_correct,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GameSummary) Reset() {
+	*x = GameSummary{}
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameSummary) ProtoMessage() {}
+
+func (x *GameSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_globetrotter_v1_globetrotter_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameSummary.ProtoReflect.Descriptor instead.
+func (*GameSummary) Descriptor() ([]byte, []int) {
+	return file_globetrotter_v1_globetrotter_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GameSummary) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *GameSummary) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *GameSummary) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *GameSummary) GetTotalQuestions() int64 {
+	if x != nil {
+		return x.TotalQuestions
+	}
+	return 0
+}
+
+func (x *GameSummary) GetTotalAnswered() int64 {
+	if x != nil {
+		return x.TotalAnswered
+	}
+	return 0
+}
+
+func (x *GameSummary) GetTotalCorrect() int64 {
+	if x != nil {
+		return x.TotalCorrect
+	}
+	return 0
+}
+
+func (x *GameSummary) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+var File_globetrotter_v1_globetrotter_proto protoreflect.FileDescriptor
+
+const file_globetrotter_v1_globetrotter_proto_rawDesc = "" +
+	"\n" +
+	"\"globetrotter/v1/globetrotter.proto\x12\x0fglobetrotter.v1\"i\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"G\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\",\n" +
+	"\x0eGetUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"W\n" +
+	"\x11UpdateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1b\n" +
+	"\x06locale\x18\x02 \x01(\tH\x00R\x06locale\x88\x01\x01B\t\n" +
+	"\a_locale\"H\n" +
+	"\x10StartGameRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x18\n" +
+	"\ascoring\x18\x02 \x01(\tR\ascoring\",\n" +
+	"\x11StartGameResponse\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\"1\n" +
+	"\x16GetNextQuestionRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\"\xb0\x02\n" +
+	"\bQuestion\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\x03R\n" +
+	"questionId\x12\x1a\n" +
+	"\bquestion\x18\x03 \x01(\tR\bquestion\x12V\n" +
+	"\x0foptions_display\x18\x04 \x03(\v2-.globetrotter.v1.Question.OptionsDisplayEntryR\x0eoptionsDisplay\x12\x18\n" +
+	"\ascoring\x18\x05 \x01(\tR\ascoring\x12\x19\n" +
+	"\bhas_next\x18\x06 \x01(\bR\ahasNext\x1aA\n" +
+	"\x13OptionsDisplayEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"?\n" +
+	"\x03Pin\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xaa\x01\n" +
+	"\x13SubmitAnswerRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\x03R\n" +
+	"questionId\x121\n" +
+	"\x14selected_destination\x18\x03 \x01(\x03R\x13selectedDestination\x12&\n" +
+	"\x03pin\x18\x04 \x01(\v2\x14.globetrotter.v1.PinR\x03pin\"\xc0\x01\n" +
+	"\x10DestinationImage\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\"\n" +
+	"\fphotographer\x18\x03 \x01(\tR\fphotographer\x12)\n" +
+	"\x10photographer_url\x18\x04 \x01(\tR\x0fphotographerUrl\x12\x1d\n" +
+	"\n" +
+	"source_url\x18\x05 \x01(\tR\tsourceUrl\x12\x10\n" +
+	"\x03alt\x18\x06 \x01(\tR\x03alt\"\x93\x04\n" +
+	"\fAnswerResult\x12\x18\n" +
+	"\acorrect\x18\x01 \x01(\bR\acorrect\x12\x19\n" +
+	"\bfun_fact\x18\x02 \x01(\tR\afunFact\x12\x16\n" +
+	"\x06trivia\x18\x03 \x01(\tR\x06trivia\x12!\n" +
+	"\fcorrect_city\x18\x04 \x01(\tR\vcorrectCity\x12'\n" +
+	"\x0fcorrect_country\x18\x05 \x01(\tR\x0ecorrectCountry\x12*\n" +
+	"\x11correct_option_id\x18\x06 \x01(\x03R\x0fcorrectOptionId\x12\x16\n" +
+	"\x06points\x18\a \x01(\x03R\x06points\x12$\n" +
+	"\vdistance_km\x18\b \x01(\x01H\x00R\n" +
+	"distanceKm\x88\x01\x01\x12.\n" +
+	"\x10correct_latitude\x18\t \x01(\x01H\x01R\x0fcorrectLatitude\x88\x01\x01\x120\n" +
+	"\x11correct_longitude\x18\n" +
+	" \x01(\x01H\x02R\x10correctLongitude\x88\x01\x01\x127\n" +
+	"\x05image\x18\v \x01(\v2!.globetrotter.v1.DestinationImageR\x05image\x12*\n" +
+	"\x11clue_submitted_by\x18\f \x01(\tR\x0fclueSubmittedByB\x0e\n" +
+	"\f_distance_kmB\x13\n" +
+	"\x11_correct_latitudeB\x14\n" +
+	"\x12_correct_longitude\"/\n" +
+	"\x14GetGameResultRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\"\xc9\x04\n" +
+	"\x0eQuestionResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\bquestion\x18\x02 \x01(\tR\bquestion\x12\x18\n" +
+	"\aoptions\x18\x03 \x03(\x03R\aoptions\x124\n" +
+	"\x16correct_destination_id\x18\x04 \x01(\x03R\x14correctDestinationId\x126\n" +
+	"\x17selected_destination_id\x18\x05 \x01(\x03R\x15selectedDestinationId\x12\x1a\n" +
+	"\banswered\x18\x06 \x01(\bR\banswered\x12\x17\n" +
+	"\aclue_id\x18\a \x01(\x03R\x06clueId\x12\x16\n" +
+	"\x06points\x18\b \x01(\x03R\x06points\x12$\n" +
+	"\vdistance_km\x18\t \x01(\x01H\x00R\n" +
+	"distanceKm\x88\x01\x01\x12&\n" +
+	"\fpin_latitude\x18\n" +
+	" \x01(\x01H\x01R\vpinLatitude\x88\x01\x01\x12(\n" +
+	"\rpin_longitude\x18\v \x01(\x01H\x02R\fpinLongitude\x88\x01\x01\x12.\n" +
+	"\x10correct_latitude\x18\f \x01(\x01H\x03R\x0fcorrectLatitude\x88\x01\x01\x120\n" +
+	"\x11correct_longitude\x18\r \x01(\x01H\x04R\x10correctLongitude\x88\x01\x01B\x0e\n" +
+	"\f_distance_kmB\x0f\n" +
+	"\r_pin_latitudeB\x10\n" +
+	"\x0e_pin_longitudeB\x13\n" +
+	"\x11_correct_latitudeB\x14\n" +
+	"\x12_correct_longitude\"\xa9\x02\n" +
+	"\n" +
+	"GameResult\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\x12'\n" +
+	"\x0ftotal_questions\x18\x02 \x01(\x03R\x0etotalQuestions\x12#\n" +
+	"\rtotal_correct\x18\x03 \x01(\x03R\ftotalCorrect\x12'\n" +
+	"\x0ftotal_incorrect\x18\x04 \x01(\x03R\x0etotalIncorrect\x12\x18\n" +
+	"\ascoring\x18\x05 \x01(\tR\ascoring\x12\x14\n" +
+	"\x05score\x18\x06 \x01(\x03R\x05score\x12\x1c\n" +
+	"\tcompacted\x18\a \x01(\bR\tcompacted\x12=\n" +
+	"\tquestions\x18\b \x03(\v2\x1f.globetrotter.v1.QuestionResultR\tquestions\"0\n" +
+	"\x15GetGameSummaryRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\"\xf3\x01\n" +
+	"\vGameSummary\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x03R\x06gameId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\timage_url\x18\x03 \x01(\tR\bimageUrl\x12'\n" +
+	"\x0ftotal_questions\x18\x04 \x01(\x03R\x0etotalQuestions\x12%\n" +
+	"\x0etotal_answered\x18\x05 \x01(\x03R\rtotalAnswered\x12#\n" +
+	"\rtotal_correct\x18\x06 \x01(\x03R\ftotalCorrect\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt2\x90\x05\n" +
+	"\fGlobetrotter\x12G\n" +
+	"\n" +
+	"CreateUser\x12\".globetrotter.v1.CreateUserRequest\x1a\x15.globetrotter.v1.User\x12A\n" +
+	"\aGetUser\x12\x1f.globetrotter.v1.GetUserRequest\x1a\x15.globetrotter.v1.User\x12G\n" +
+	"\n" +
+	"UpdateUser\x12\".globetrotter.v1.UpdateUserRequest\x1a\x15.globetrotter.v1.User\x12R\n" +
+	"\tStartGame\x12!.globetrotter.v1.StartGameRequest\x1a\".globetrotter.v1.StartGameResponse\x12U\n" +
+	"\x0fGetNextQuestion\x12'.globetrotter.v1.GetNextQuestionRequest\x1a\x19.globetrotter.v1.Question\x12S\n" +
+	"\fSubmitAnswer\x12$.globetrotter.v1.SubmitAnswerRequest\x1a\x1d.globetrotter.v1.AnswerResult\x12S\n" +
+	"\rGetGameResult\x12%.globetrotter.v1.GetGameResultRequest\x1a\x1b.globetrotter.v1.GameResult\x12V\n" +
+	"\x0eGetGameSummary\x12&.globetrotter.v1.GetGameSummaryRequest\x1a\x1c.globetrotter.v1.GameSummaryBQZOgithub.com/shubhsherl/globetrotter/backend/proto/globetrotter/v1;globetrotterv1b\x06proto3"
+
+var (
+	file_globetrotter_v1_globetrotter_proto_rawDescOnce sync.Once
+	file_globetrotter_v1_globetrotter_proto_rawDescData []byte
+)
+
+func file_globetrotter_v1_globetrotter_proto_rawDescGZIP() []byte {
+	file_globetrotter_v1_globetrotter_proto_rawDescOnce.Do(func() {
+		file_globetrotter_v1_globetrotter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_globetrotter_v1_globetrotter_proto_rawDesc), len(file_globetrotter_v1_globetrotter_proto_rawDesc)))
+	})
+	return file_globetrotter_v1_globetrotter_proto_rawDescData
+}
+
+var file_globetrotter_v1_globetrotter_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_globetrotter_v1_globetrotter_proto_goTypes = []any{
+	(*User)(nil),                   // 0: globetrotter.v1.User
+	(*CreateUserRequest)(nil),      // 1: globetrotter.v1.CreateUserRequest
+	(*GetUserRequest)(nil),         // 2: globetrotter.v1.GetUserRequest
+	(*UpdateUserRequest)(nil),      // 3: globetrotter.v1.UpdateUserRequest
+	(*StartGameRequest)(nil),       // 4: globetrotter.v1.StartGameRequest
+	(*StartGameResponse)(nil),      // 5: globetrotter.v1.StartGameResponse
+	(*GetNextQuestionRequest)(nil), // 6: globetrotter.v1.GetNextQuestionRequest
+	(*Question)(nil),               // 7: globetrotter.v1.Question
+	(*Pin)(nil),                    // 8: globetrotter.v1.Pin
+	(*SubmitAnswerRequest)(nil),    // 9: globetrotter.v1.SubmitAnswerRequest
+	(*DestinationImage)(nil),       // 10: globetrotter.v1.DestinationImage
+	(*AnswerResult)(nil),           // 11: globetrotter.v1.AnswerResult
+	(*GetGameResultRequest)(nil),   // 12: globetrotter.v1.GetGameResultRequest
+	(*QuestionResult)(nil),         // 13: globetrotter.v1.QuestionResult
+	(*GameResult)(nil),             // 14: globetrotter.v1.GameResult
+	(*GetGameSummaryRequest)(nil),  // 15: globetrotter.v1.GetGameSummaryRequest
+	(*GameSummary)(nil),            // 16: globetrotter.v1.GameSummary
+	nil,                            // 17: globetrotter.v1.Question.OptionsDisplayEntry
+}
+var file_globetrotter_v1_globetrotter_proto_depIdxs = []int32{
+	17, // 0: globetrotter.v1.Question.options_display:type_name -> globetrotter.v1.Question.OptionsDisplayEntry
+	8,  // 1: globetrotter.v1.SubmitAnswerRequest.pin:type_name -> globetrotter.v1.Pin
+	10, // 2: globetrotter.v1.AnswerResult.image:type_name -> globetrotter.v1.DestinationImage
+	13, // 3: globetrotter.v1.GameResult.questions:type_name -> globetrotter.v1.QuestionResult
+	1,  // 4: globetrotter.v1.Globetrotter.CreateUser:input_type -> globetrotter.v1.CreateUserRequest
+	2,  // 5: globetrotter.v1.Globetrotter.GetUser:input_type -> globetrotter.v1.GetUserRequest
+	3,  // 6: globetrotter.v1.Globetrotter.UpdateUser:input_type -> globetrotter.v1.UpdateUserRequest
+	4,  // 7: globetrotter.v1.Globetrotter.StartGame:input_type -> globetrotter.v1.StartGameRequest
+	6,  // 8: globetrotter.v1.Globetrotter.GetNextQuestion:input_type -> globetrotter.v1.GetNextQuestionRequest
+	9,  // 9: globetrotter.v1.Globetrotter.SubmitAnswer:input_type -> globetrotter.v1.SubmitAnswerRequest
+	12, // 10: globetrotter.v1.Globetrotter.GetGameResult:input_type -> globetrotter.v1.GetGameResultRequest
+	15, // 11: globetrotter.v1.Globetrotter.GetGameSummary:input_type -> globetrotter.v1.GetGameSummaryRequest
+	0,  // 12: globetrotter.v1.Globetrotter.CreateUser:output_type -> globetrotter.v1.User
+	0,  // 13: globetrotter.v1.Globetrotter.GetUser:output_type -> globetrotter.v1.User
+	0,  // 14: globetrotter.v1.Globetrotter.UpdateUser:output_type -> globetrotter.v1.User
+	5,  // 15: globetrotter.v1.Globetrotter.StartGame:output_type -> globetrotter.v1.StartGameResponse
+	7,  // 16: globetrotter.v1.Globetrotter.GetNextQuestion:output_type -> globetrotter.v1.Question
+	11, // 17: globetrotter.v1.Globetrotter.SubmitAnswer:output_type -> globetrotter.v1.AnswerResult
+	14, // 18: globetrotter.v1.Globetrotter.GetGameResult:output_type -> globetrotter.v1.GameResult
+	16, // 19: globetrotter.v1.Globetrotter.GetGameSummary:output_type -> globetrotter.v1.GameSummary
+	12, // [12:20] is the sub-list for method output_type
+	4,  // [4:12] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_globetrotter_v1_globetrotter_proto_init() }
+func file_globetrotter_v1_globetrotter_proto_init() {
+	if File_globetrotter_v1_globetrotter_proto != nil {
+		return
+	}
+	file_globetrotter_v1_globetrotter_proto_msgTypes[3].OneofWrappers = []any{}
+	file_globetrotter_v1_globetrotter_proto_msgTypes[11].OneofWrappers = []any{}
+	file_globetrotter_v1_globetrotter_proto_msgTypes[13].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_globetrotter_v1_globetrotter_proto_rawDesc), len(file_globetrotter_v1_globetrotter_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_globetrotter_v1_globetrotter_proto_goTypes,
+		DependencyIndexes: file_globetrotter_v1_globetrotter_proto_depIdxs,
+		MessageInfos:      file_globetrotter_v1_globetrotter_proto_msgTypes,
+	}.Build()
+	File_globetrotter_v1_globetrotter_proto = out.File
+	file_globetrotter_v1_globetrotter_proto_goTypes = nil
+	file_globetrotter_v1_globetrotter_proto_depIdxs = nil
+}
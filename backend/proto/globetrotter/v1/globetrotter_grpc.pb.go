@@ -0,0 +1,429 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: globetrotter/v1/globetrotter.proto
+
+// The gRPC API of Globetrotter, served next to the REST API
+
+package globetrotterv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Globetrotter_CreateUser_FullMethodName      = "/globetrotter.v1.Globetrotter/CreateUser"
+	Globetrotter_GetUser_FullMethodName         = "/globetrotter.v1.Globetrotter/GetUser"
+	Globetrotter_UpdateUser_FullMethodName      = "/globetrotter.v1.Globetrotter/UpdateUser"
+	Globetrotter_StartGame_FullMethodName       = "/globetrotter.v1.Globetrotter/StartGame"
+	Globetrotter_GetNextQuestion_FullMethodName = "/globetrotter.v1.Globetrotter/GetNextQuestion"
+	Globetrotter_SubmitAnswer_FullMethodName    = "/globetrotter.v1.Globetrotter/SubmitAnswer"
+	Globetrotter_GetGameResult_FullMethodName   = "/globetrotter.v1.Globetrotter/GetGameResult"
+	Globetrotter_GetGameSummary_FullMethodName  = "/globetrotter.v1.Globetrotter/GetGameSummary"
+)
+
+// GlobetrotterClient is the client API for Globetrotter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Globetrotter mirrors the player routes of the REST API and is backed by the
+// same services, so both behave the same way.
+//
+// Send an "accept-language" metadata entry to choose the language of
+// questions, answers and error messages, as with the Accept-Language header.
+//
+// Errors carry a google.rpc.ErrorInfo detail whose reason is the stable error
+// code of the REST API, such as "game_not_found". Validation failures add a
+// google.rpc.BadRequest detail listing the problems, and SubmitAnswer fails
+// with ALREADY_EXISTS and the recorded AnswerResult as a detail when the
+// question was answered before.
+type GlobetrotterClient interface {
+	// Creates a player, optionally with a preferred language
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// Gets a player by username
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	// Sets a player's preferred language; an empty locale clears it
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// Starts a game for a player
+	StartGame(ctx context.Context, in *StartGameRequest, opts ...grpc.CallOption) (*StartGameResponse, error)
+	// Gets the next unanswered question of a game
+	GetNextQuestion(ctx context.Context, in *GetNextQuestionRequest, opts ...grpc.CallOption) (*Question, error)
+	// Answers a question with an option, or a pin in pin games
+	SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*AnswerResult, error)
+	// Gets the totals and every question of a game
+	GetGameResult(ctx context.Context, in *GetGameResultRequest, opts ...grpc.CallOption) (*GameResult, error)
+	// Gets the summary shown on challenge pages
+	GetGameSummary(ctx context.Context, in *GetGameSummaryRequest, opts ...grpc.CallOption) (*GameSummary, error)
+}
+
+type globetrotterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGlobetrotterClient(cc grpc.ClientConnInterface) GlobetrotterClient {
+	return &globetrotterClient{cc}
+}
+
+func (c *globetrotterClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Globetrotter_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *globetrotterClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Globetrotter_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *globetrotterClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Globetrotter_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *globetrotterClient) StartGame(ctx context.Context, in *StartGameRequest, opts ...grpc.CallOption) (*StartGameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartGameResponse)
+	err := c.cc.Invoke(ctx, Globetrotter_StartGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *globetrotterClient) GetNextQuestion(ctx context.Context, in *GetNextQuestionRequest, opts ...grpc.CallOption) (*Question, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Question)
+	err := c.cc.Invoke(ctx, Globetrotter_GetNextQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *globetrotterClient) SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*AnswerResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnswerResult)
+	err := c.cc.Invoke(ctx, Globetrotter_SubmitAnswer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *globetrotterClient) GetGameResult(ctx context.Context, in *GetGameResultRequest, opts ...grpc.CallOption) (*GameResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameResult)
+	err := c.cc.Invoke(ctx, Globetrotter_GetGameResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *globetrotterClient) GetGameSummary(ctx context.Context, in *GetGameSummaryRequest, opts ...grpc.CallOption) (*GameSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameSummary)
+	err := c.cc.Invoke(ctx, Globetrotter_GetGameSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GlobetrotterServer is the server API for Globetrotter service.
+// All implementations must embed UnimplementedGlobetrotterServer
+// for forward compatibility.
+//
+// Globetrotter mirrors the player routes of the REST API and is backed by the
+// same services, so both behave the same way.
+//
+// Send an "accept-language" metadata entry to choose the language of
+// questions, answers and error messages, as with the Accept-Language header.
+//
+// Errors carry a google.rpc.ErrorInfo detail whose reason is the stable error
+// code of the REST API, such as "game_not_found". Validation failures add a
+// google.rpc.BadRequest detail listing the problems, and SubmitAnswer fails
+// with ALREADY_EXISTS and the recorded AnswerResult as a detail when the
+// question was answered before.
+type GlobetrotterServer interface {
+	// Creates a player, optionally with a preferred language
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	// Gets a player by username
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	// Sets a player's preferred language; an empty locale clears it
+	UpdateUser(context.Context, *UpdateUserRequest) (*User, error)
+	// Starts a game for a player
+	StartGame(context.Context, *StartGameRequest) (*StartGameResponse, error)
+	// Gets the next unanswered question of a game
+	GetNextQuestion(context.Context, *GetNextQuestionRequest) (*Question, error)
+	// Answers a question with an option, or a pin in pin games
+	SubmitAnswer(context.Context, *SubmitAnswerRequest) (*AnswerResult, error)
+	// Gets the totals and every question of a game
+	GetGameResult(context.Context, *GetGameResultRequest) (*GameResult, error)
+	// Gets the summary shown on challenge pages
+	GetGameSummary(context.Context, *GetGameSummaryRequest) (*GameSummary, error)
+	mustEmbedUnimplementedGlobetrotterServer()
+}
+
+// UnimplementedGlobetrotterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGlobetrotterServer struct{}
+
+func (UnimplementedGlobetrotterServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedGlobetrotterServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedGlobetrotterServer) UpdateUser(context.Context, *UpdateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedGlobetrotterServer) StartGame(context.Context, *StartGameRequest) (*StartGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartGame not implemented")
+}
+func (UnimplementedGlobetrotterServer) GetNextQuestion(context.Context, *GetNextQuestionRequest) (*Question, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNextQuestion not implemented")
+}
+func (UnimplementedGlobetrotterServer) SubmitAnswer(context.Context, *SubmitAnswerRequest) (*AnswerResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitAnswer not implemented")
+}
+func (UnimplementedGlobetrotterServer) GetGameResult(context.Context, *GetGameResultRequest) (*GameResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGameResult not implemented")
+}
+func (UnimplementedGlobetrotterServer) GetGameSummary(context.Context, *GetGameSummaryRequest) (*GameSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGameSummary not implemented")
+}
+func (UnimplementedGlobetrotterServer) mustEmbedUnimplementedGlobetrotterServer() {}
+func (UnimplementedGlobetrotterServer) testEmbeddedByValue()                      {}
+
+// UnsafeGlobetrotterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GlobetrotterServer will
+// result in compilation errors.
+type UnsafeGlobetrotterServer interface {
+	mustEmbedUnimplementedGlobetrotterServer()
+}
+
+func RegisterGlobetrotterServer(s grpc.ServiceRegistrar, srv GlobetrotterServer) {
+	// If the following call pancis, it indicates UnimplementedGlobetrotterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Globetrotter_ServiceDesc, srv)
+}
+
+func _Globetrotter_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Globetrotter_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Globetrotter_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Globetrotter_StartGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).StartGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_StartGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).StartGame(ctx, req.(*StartGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Globetrotter_GetNextQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNextQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).GetNextQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_GetNextQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).GetNextQuestion(ctx, req.(*GetNextQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Globetrotter_SubmitAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).SubmitAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_SubmitAnswer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).SubmitAnswer(ctx, req.(*SubmitAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Globetrotter_GetGameResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGameResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).GetGameResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_GetGameResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).GetGameResult(ctx, req.(*GetGameResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Globetrotter_GetGameSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGameSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GlobetrotterServer).GetGameSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Globetrotter_GetGameSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GlobetrotterServer).GetGameSummary(ctx, req.(*GetGameSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Globetrotter_ServiceDesc is the grpc.ServiceDesc for Globetrotter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Globetrotter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "globetrotter.v1.Globetrotter",
+	HandlerType: (*GlobetrotterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _Globetrotter_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _Globetrotter_GetUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _Globetrotter_UpdateUser_Handler,
+		},
+		{
+			MethodName: "StartGame",
+			Handler:    _Globetrotter_StartGame_Handler,
+		},
+		{
+			MethodName: "GetNextQuestion",
+			Handler:    _Globetrotter_GetNextQuestion_Handler,
+		},
+		{
+			MethodName: "SubmitAnswer",
+			Handler:    _Globetrotter_SubmitAnswer_Handler,
+		},
+		{
+			MethodName: "GetGameResult",
+			Handler:    _Globetrotter_GetGameResult_Handler,
+		},
+		{
+			MethodName: "GetGameSummary",
+			Handler:    _Globetrotter_GetGameSummary_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "globetrotter/v1/globetrotter.proto",
+}
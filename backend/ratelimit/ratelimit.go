@@ -0,0 +1,181 @@
+// Package ratelimit implements token-bucket rate limits. A Limit describes a
+// bucket, and a Store keeps the state of the buckets, in memory by default
+// or in a shared store when several servers must enforce the same limits.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket holding Burst tokens, Rate of which are added back
+// every Per. Each request takes a token and is refused when none is left.
+// The zero Limit allows everything.
+type Limit struct {
+	Rate  int
+	Per   time.Duration
+	Burst int // Requests allowed at once, Rate if 0
+}
+
+// Enabled reports whether the limit refuses anything
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Per > 0
+}
+
+// Interval returns the time it takes to add one token back
+func (l Limit) Interval() time.Duration {
+	return l.Per / time.Duration(l.Rate)
+}
+
+// Size returns the number of tokens the bucket holds
+func (l Limit) Size() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// String returns the limit in the form ParseLimit reads
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+
+	var per string
+	switch l.Per {
+	case time.Second:
+		per = "s"
+	case time.Minute:
+		per = "m"
+	case time.Hour:
+		per = "h"
+	default:
+		per = l.Per.String()
+	}
+
+	s := fmt.Sprintf("%d/%s", l.Rate, per)
+	if l.Burst > 0 && l.Burst != l.Rate {
+		s += ":" + strconv.Itoa(l.Burst)
+	}
+	return s
+}
+
+// ParseLimit parses a limit written as RATE/PERIOD[:BURST], where PERIOD is
+// s, m, h or a duration such as 10m: "30/m" allows 30 requests a minute,
+// "100/h:10" 100 an hour but no more than 10 at once. "off" or "0" is the
+// zero Limit.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+
+	rate, rest, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: want RATE/PERIOD, such as 30/m", s)
+	}
+	period, burst, hasBurst := strings.Cut(rest, ":")
+
+	var l Limit
+	var err error
+	if l.Rate, err = strconv.Atoi(rate); err != nil || l.Rate < 1 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: the rate must be a positive number", s)
+	}
+
+	switch period {
+	case "s":
+		l.Per = time.Second
+	case "m":
+		l.Per = time.Minute
+	case "h":
+		l.Per = time.Hour
+	default:
+		if l.Per, err = time.ParseDuration(period); err != nil || l.Per <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: the period must be s, m, h or a positive duration", s)
+		}
+	}
+
+	if hasBurst {
+		if l.Burst, err = strconv.Atoi(burst); err != nil || l.Burst < 1 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: the burst must be a positive number", s)
+		}
+	}
+
+	return l, nil
+}
+
+// Store keeps the buckets of rate limits, identified by key
+type Store interface {
+	// Take takes a token from the bucket named key, full if it is new, and
+	// reports whether one was available. When none was, it returns how long
+	// until one is.
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+// sweepInterval is how often the memory store forgets buckets that are full again
+const sweepInterval = time.Minute
+
+// MemoryStore keeps buckets in memory, for a single server. Each bucket is
+// stored as the time it will be full again, so buckets of idle clients can be
+// dropped once that time has passed.
+type MemoryStore struct {
+	mu        sync.Mutex
+	full      map[string]time.Time // When each bucket is full again
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		full: make(map[string]time.Time),
+	}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if !limit.Enabled() {
+		return true, 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, full := range s.full {
+			if !full.After(now) {
+				delete(s.full, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	ok, wait, full := TakeToken(s.full[key], limit, now)
+	if ok {
+		s.full[key] = full
+	}
+	return ok, wait, nil
+}
+
+// TakeToken takes a token at now from a bucket that is full at full, a time
+// in the past for a new bucket. It reports whether a token was available,
+// how long until one is when not, and the bucket's new full time. Stores
+// only need to keep that time per bucket and update it atomically.
+func TakeToken(full time.Time, limit Limit, now time.Time) (bool, time.Duration, time.Time) {
+	if full.Before(now) {
+		full = now
+	}
+
+	// Taking a token delays the time the bucket is full by one interval; the
+	// bucket is empty when that is more than its size in intervals away
+	interval := limit.Interval()
+	next := full.Add(interval)
+	if excess := next.Sub(now) - time.Duration(limit.Size())*interval; excess > 0 {
+		return false, excess, full
+	}
+	return true, 0, next
+}
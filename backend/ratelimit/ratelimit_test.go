@@ -0,0 +1,169 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Limit
+	}{
+		{"30/m", Limit{Rate: 30, Per: time.Minute}},
+		{"5/s", Limit{Rate: 5, Per: time.Second}},
+		{"100/h:10", Limit{Rate: 100, Per: time.Hour, Burst: 10}},
+		{"2/10m", Limit{Rate: 2, Per: 10 * time.Minute}},
+		{" 30/m ", Limit{Rate: 30, Per: time.Minute}},
+		{"off", Limit{}},
+		{"0", Limit{}},
+	} {
+		got, err := ParseLimit(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "30", "thirty/m", "0/m", "-1/m", "30/d", "30/0s", "30/-1m", "30/m:0", "30/m:x", "30/m:"} {
+		if got, err := ParseLimit(in); err == nil {
+			t.Errorf("ParseLimit(%q) = %+v, want an error", in, got)
+		}
+	}
+}
+
+func TestLimitString(t *testing.T) {
+	for _, tt := range []struct {
+		limit Limit
+		want  string
+	}{
+		{Limit{Rate: 30, Per: time.Minute}, "30/m"},
+		{Limit{Rate: 5, Per: time.Second}, "5/s"},
+		{Limit{Rate: 100, Per: time.Hour, Burst: 10}, "100/h:10"},
+		{Limit{Rate: 10, Per: time.Hour, Burst: 10}, "10/h"},
+		{Limit{Rate: 2, Per: 10 * time.Minute}, "2/10m0s"},
+		{Limit{}, "off"},
+		{Limit{Rate: 5}, "off"},
+	} {
+		got := tt.limit.String()
+		if got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.limit, got, tt.want)
+		}
+		// What String writes, ParseLimit reads back
+		if parsed, err := ParseLimit(got); err != nil || parsed.String() != got {
+			t.Errorf("ParseLimit(%q) = %+v, %v", got, parsed, err)
+		}
+	}
+}
+
+func TestTakeToken(t *testing.T) {
+	limit := Limit{Rate: 6, Per: time.Minute, Burst: 3} // A token every 10s
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// A new bucket is full: the burst is allowed at once
+	var full time.Time
+	for i := 0; i < 3; i++ {
+		ok, _, next := TakeToken(full, limit, now)
+		if !ok {
+			t.Fatalf("token %d of the burst was refused", i+1)
+		}
+		full = next
+	}
+	ok, wait, next := TakeToken(full, limit, now)
+	if ok || wait != 10*time.Second || !next.Equal(full) {
+		t.Errorf("empty bucket: got %t, wait %s, full at %s; want a refusal for 10s", ok, wait, next)
+	}
+
+	// One token is back after an interval, and the refusal shortens the wait
+	if ok, wait, _ := TakeToken(full, limit, now.Add(4*time.Second)); ok || wait != 6*time.Second {
+		t.Errorf("after 4s: got %t, wait %s; want a refusal for 6s", ok, wait)
+	}
+	ok, _, full = TakeToken(full, limit, now.Add(10*time.Second))
+	if !ok {
+		t.Fatal("the token added back after an interval was refused")
+	}
+	if ok, _, _ := TakeToken(full, limit, now.Add(10*time.Second)); ok {
+		t.Error("a second token was taken after one interval")
+	}
+
+	// After it is full again, the whole burst is back and no more
+	later := full.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		ok, _, next := TakeToken(full, limit, later)
+		if !ok {
+			t.Fatalf("token %d of the refilled burst was refused", i+1)
+		}
+		full = next
+	}
+	if ok, _, _ := TakeToken(full, limit, later); ok {
+		t.Error("the refilled bucket held more than its burst")
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	limit := Limit{Rate: 2, Per: time.Hour}
+	for i := 0; i < 2; i++ {
+		if ok, _, err := s.Take(ctx, "alice", limit); !ok || err != nil {
+			t.Fatalf("token %d: got %t, %v", i+1, ok, err)
+		}
+	}
+	ok, wait, err := s.Take(ctx, "alice", limit)
+	if ok || err != nil || wait <= 29*time.Minute || wait > 30*time.Minute {
+		t.Errorf("empty bucket: got %t, wait %s, %v; want a refusal for about 30m", ok, wait, err)
+	}
+	if ok, _, _ := s.Take(ctx, "bob", limit); !ok {
+		t.Error("another key shares alice's bucket")
+	}
+	if ok, _, _ := s.Take(ctx, "alice", Limit{}); !ok {
+		t.Error("the zero limit refused a request")
+	}
+
+	// A bucket refills as time passes
+	fast := Limit{Rate: 20, Per: time.Second, Burst: 1}
+	if ok, _, _ := s.Take(ctx, "fast", fast); !ok {
+		t.Fatal("the first token was refused")
+	}
+	ok, wait, _ = s.Take(ctx, "fast", fast)
+	if ok || wait <= 0 || wait > 50*time.Millisecond {
+		t.Fatalf("empty bucket: got %t, wait %s; want a refusal for up to 50ms", ok, wait)
+	}
+	time.Sleep(wait)
+	if ok, _, _ := s.Take(ctx, "fast", fast); !ok {
+		t.Error("the token was not back after the wait")
+	}
+}
+
+// TestMemoryStoreSweep checks that buckets full again are forgotten, at most
+// once a sweep interval
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Per: time.Hour}
+	s := NewMemoryStore()
+	now := time.Now()
+	s.full["idle"] = now.Add(-time.Second)
+	s.full["busy"] = now.Add(time.Hour)
+
+	s.lastSweep = now
+	s.Take(ctx, "new", limit)
+	if _, ok := s.full["idle"]; !ok {
+		t.Error("swept before the sweep interval passed")
+	}
+
+	s.lastSweep = now.Add(-sweepInterval)
+	s.Take(ctx, "new", limit)
+	if _, ok := s.full["idle"]; ok {
+		t.Error("a bucket full again was kept")
+	}
+	if _, ok := s.full["busy"]; !ok {
+		t.Error("a bucket still refilling was dropped")
+	}
+	if ok, _, _ := s.Take(ctx, "busy", limit); ok {
+		t.Error("the busy bucket was refilled by the sweep")
+	}
+	if len(s.full) != 2 {
+		t.Errorf("the store keeps %d buckets, want busy and new", len(s.full))
+	}
+}
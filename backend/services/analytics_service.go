@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// Thresholds used by the difficulty report
+const (
+	// TooEasyRate is the correct rate at which a clue no longer tells players anything
+	TooEasyRate = 0.99
+
+	// DefaultMinSamples is how many answers an item needs before it is flagged
+	DefaultMinSamples = 20
+)
+
+// AnalyticsService aggregates how players fare on destinations and clues
+type AnalyticsService struct {
+	stats storage.StatsRepository
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(stats storage.StatsRepository) *AnalyticsService {
+	return &AnalyticsService{
+		stats: stats,
+	}
+}
+
+// Run aggregates difficulty statistics now and then every interval until ctx is cancelled
+func (s *AnalyticsService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RefreshDifficulty(); err != nil {
+			log.Printf("Difficulty aggregation failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshDifficulty recomputes the difficulty statistics from all answered questions
+func (s *AnalyticsService) RefreshDifficulty() error {
+	return s.stats.RefreshDifficultyStats()
+}
+
+// ListDestinationDifficulty returns the statistics of every answered destination
+func (s *AnalyticsService) ListDestinationDifficulty() ([]models.DifficultyStats, error) {
+	stats, err := s.stats.ListDestinationStats()
+	if err != nil {
+		return nil, err
+	}
+	return withCorrectRates(stats), nil
+}
+
+// ListClueDifficulty returns the statistics of every clue shown in an answered question
+func (s *AnalyticsService) ListClueDifficulty() ([]models.DifficultyStats, error) {
+	stats, err := s.stats.ListClueStats()
+	if err != nil {
+		return nil, err
+	}
+	return withCorrectRates(stats), nil
+}
+
+// DifficultyReport flags clues that are almost always answered correctly, and
+// destinations and clues where a wrong option is picked more often than the
+// answer. Items with fewer than minSamples answers are not flagged.
+func (s *AnalyticsService) DifficultyReport(minSamples int) (*models.DifficultyReport, error) {
+	updatedAt, err := s.stats.DifficultyStatsUpdatedAt()
+	if err != nil {
+		return nil, err
+	}
+
+	destinations, err := s.ListDestinationDifficulty()
+	if err != nil {
+		return nil, err
+	}
+	clues, err := s.ListClueDifficulty()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DifficultyReport{
+		UpdatedAt:              updatedAt,
+		MinSamples:             minSamples,
+		TooEasyClues:           []models.DifficultyStats{},
+		MisleadingDestinations: []models.DifficultyStats{},
+		MisleadingClues:        []models.DifficultyStats{},
+	}
+
+	for _, stat := range destinations {
+		if stat.Answered >= minSamples && stat.TopWrongCount > stat.Correct {
+			report.MisleadingDestinations = append(report.MisleadingDestinations, stat)
+		}
+	}
+	for _, stat := range clues {
+		if stat.Answered < minSamples {
+			continue
+		}
+		if stat.CorrectRate >= TooEasyRate {
+			report.TooEasyClues = append(report.TooEasyClues, stat)
+		}
+		if stat.TopWrongCount > stat.Correct {
+			report.MisleadingClues = append(report.MisleadingClues, stat)
+		}
+	}
+
+	return report, nil
+}
+
+// withCorrectRates fills in the correct rate of each entry. No statistics
+// are returned as an empty list rather than nil, which encodes as null.
+func withCorrectRates(stats []models.DifficultyStats) []models.DifficultyStats {
+	if stats == nil {
+		return []models.DifficultyStats{}
+	}
+	for i := range stats {
+		if stats[i].Answered > 0 {
+			stats[i].CorrectRate = float64(stats[i].Correct) / float64(stats[i].Answered)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// Page sizes of audit log listings
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+// AuditService reads the audit log. Entries are written by the repositories,
+// in the transaction of the change they record.
+type AuditService struct {
+	entries storage.AuditRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(entries storage.AuditRepository) *AuditService {
+	return &AuditService{
+		entries: entries,
+	}
+}
+
+// List returns the audit log entries matching filter, newest first, at most
+// MaxAuditLimit at a time and DefaultAuditLimit when no limit is given
+func (s *AuditService) List(filter storage.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditLimit
+	}
+	if filter.Limit > MaxAuditLimit {
+		filter.Limit = MaxAuditLimit
+	}
+
+	return s.entries.ListAuditEntries(filter)
+}
@@ -0,0 +1,211 @@
+// Package catalog keeps the destinations in memory, indexed by ID, country
+// and region, so games can be built without reading the whole table.
+package catalog
+
+import (
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
+)
+
+// DefaultCheckInterval is how often the catalog asks the storage whether
+// destinations changed outside this process, for example by an import
+const DefaultCheckInterval = 5 * time.Second
+
+// Source is the storage the catalog loads destinations from
+type Source interface {
+	ListDestinations(includeRetired bool) ([]models.Destination, error)
+	DestinationsVersion() (int64, error)
+}
+
+// snapshot is one loaded copy of the destinations. It is never modified
+// after it is built; reloading builds a new one.
+type snapshot struct {
+	version      int64
+	destinations []models.Destination // Ordered by ID, including retired ones
+	byID         map[int]int          // Index into destinations
+	active       []int                // Indexes of destinations that aren't retired
+	byCountry    map[string][]int     // Active destinations by lowercased country
+	byRegion     map[string][]int     // Active destinations by geo region
+}
+
+// Catalog is a concurrency-safe in-memory copy of the destinations. It loads
+// them on first use and again after Invalidate or when the storage reports
+// that they changed. Destinations it returns are copies the caller may modify.
+type Catalog struct {
+	source        Source
+	checkInterval time.Duration
+
+	mu        sync.RWMutex
+	current   *snapshot // nil until loaded and after Invalidate
+	checkedAt time.Time // When the storage version was last compared
+}
+
+// New creates a catalog loading from source
+func New(source Source) *Catalog {
+	return &Catalog{
+		source:        source,
+		checkInterval: DefaultCheckInterval,
+	}
+}
+
+// Invalidate drops the loaded destinations so the next read loads them
+// again. Call it after changing destinations through this process.
+func (c *Catalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = nil
+}
+
+// Get returns a destination by its ID, including retired ones. It returns
+// sql.ErrNoRows if there is none, like the storage does.
+func (c *Catalog) Get(destinationID int) (*models.Destination, error) {
+	snap, err := c.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := snap.byID[destinationID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	dest := clone(snap.destinations[i])
+	return &dest, nil
+}
+
+// List returns all destinations ordered by ID, optionally including retired ones
+func (c *Catalog) List(includeRetired bool) ([]models.Destination, error) {
+	snap, err := c.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	if !includeRetired {
+		return snap.pick(snap.active), nil
+	}
+	all := make([]models.Destination, len(snap.destinations))
+	for i, dest := range snap.destinations {
+		all[i] = clone(dest)
+	}
+	return all, nil
+}
+
+// ByCountry returns the active destinations in a country, ignoring case
+func (c *Catalog) ByCountry(country string) ([]models.Destination, error) {
+	snap, err := c.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snap.pick(snap.byCountry[countryKey(country)]), nil
+}
+
+// ByRegion returns the active destinations in one of the geo regions
+func (c *Catalog) ByRegion(region string) ([]models.Destination, error) {
+	snap, err := c.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snap.pick(snap.byRegion[region]), nil
+}
+
+// snapshot returns the loaded destinations, loading them if there are none
+// or the storage reports a change since they were loaded
+func (c *Catalog) snapshot() (*snapshot, error) {
+	c.mu.RLock()
+	snap, fresh := c.current, time.Since(c.checkedAt) < c.checkInterval
+	c.mu.RUnlock()
+	if snap != nil && fresh {
+		return snap, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another reader may have loaded or checked while this one waited
+	if c.current != nil && time.Since(c.checkedAt) < c.checkInterval {
+		return c.current, nil
+	}
+
+	version, err := c.source.DestinationsVersion()
+	if err != nil {
+		return nil, err
+	}
+	if c.current != nil && c.current.version == version {
+		c.checkedAt = time.Now()
+		return c.current, nil
+	}
+
+	// The version is read before the destinations, so a change made while
+	// they load is picked up by the next check
+	destinations, err := c.source.ListDestinations(true)
+	if err != nil {
+		return nil, err
+	}
+
+	c.current = build(version, destinations)
+	c.checkedAt = time.Now()
+	return c.current, nil
+}
+
+// build indexes destinations into a snapshot
+func build(version int64, destinations []models.Destination) *snapshot {
+	snap := &snapshot{
+		version:      version,
+		destinations: destinations,
+		byID:         make(map[int]int, len(destinations)),
+		byCountry:    make(map[string][]int),
+		byRegion:     make(map[string][]int),
+	}
+
+	for i, dest := range destinations {
+		snap.byID[dest.ID] = i
+		if dest.Retired {
+			continue
+		}
+		snap.active = append(snap.active, i)
+		snap.byCountry[countryKey(dest.Country)] = append(snap.byCountry[countryKey(dest.Country)], i)
+		if region := geo.Region(dest.Country); region != geo.RegionUnclassified {
+			snap.byRegion[region] = append(snap.byRegion[region], i)
+		}
+	}
+
+	return snap
+}
+
+// pick returns copies of the destinations at the given indexes
+func (s *snapshot) pick(indexes []int) []models.Destination {
+	picked := make([]models.Destination, len(indexes))
+	for i, index := range indexes {
+		picked[i] = clone(s.destinations[index])
+	}
+	return picked
+}
+
+// countryKey normalizes a country name for the country index
+func countryKey(country string) string {
+	return strings.ToLower(strings.TrimSpace(country))
+}
+
+// clone copies a destination along with its content, so callers translating
+// or shuffling it don't change the catalog
+func clone(dest models.Destination) models.Destination {
+	dest.Clues = append(make([]string, 0, len(dest.Clues)), dest.Clues...)
+	dest.FunFact = append(make([]string, 0, len(dest.FunFact)), dest.FunFact...)
+	dest.Trivia = append(make([]string, 0, len(dest.Trivia)), dest.Trivia...)
+	dest.ClueIDs = append(make([]int, 0, len(dest.ClueIDs)), dest.ClueIDs...)
+	dest.FunFactIDs = append(make([]int, 0, len(dest.FunFactIDs)), dest.FunFactIDs...)
+	dest.TriviaIDs = append(make([]int, 0, len(dest.TriviaIDs)), dest.TriviaIDs...)
+	if dest.Latitude != nil {
+		latitude := *dest.Latitude
+		dest.Latitude = &latitude
+	}
+	if dest.Longitude != nil {
+		longitude := *dest.Longitude
+		dest.Longitude = &longitude
+	}
+	return dest
+}
@@ -0,0 +1,206 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// source is a Source whose destinations and version tests change, counting loads
+type source struct {
+	mu           sync.Mutex
+	destinations []models.Destination
+	version      int64
+	loads        int
+}
+
+func (s *source) ListDestinations(includeRetired bool) ([]models.Destination, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loads++
+	// Return copies, as a storage would
+	destinations := make([]models.Destination, len(s.destinations))
+	for i, dest := range s.destinations {
+		destinations[i] = clone(dest)
+	}
+	return destinations, nil
+}
+
+func (s *source) DestinationsVersion() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version, nil
+}
+
+// rename changes a destination's city, optionally bumping the version as the
+// storage's triggers do
+func (s *source) rename(id int, city string, bump bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.destinations {
+		if s.destinations[i].ID == id {
+			s.destinations[i].City = city
+		}
+	}
+	if bump {
+		s.version++
+	}
+}
+
+func newSource() *source {
+	latitude, longitude := 48.85, 2.35
+	return &source{
+		version: 1,
+		destinations: []models.Destination{
+			{ID: 1, City: "Paris", Country: "France", Latitude: &latitude, Longitude: &longitude,
+				Clues: []string{"Iron lady", "City of light"}, ClueIDs: []int{1, 2}, FunFact: []string{"Fun"}, Trivia: []string{"Trivia"}},
+			{ID: 2, City: "Tokyo", Country: "Japan", Clues: []string{"Shibuya"}, ClueIDs: []int{3}},
+			{ID: 3, City: "Atlantis", Country: "Nowhere", Retired: true, Clues: []string{"Sunk"}, ClueIDs: []int{4}},
+		},
+	}
+}
+
+// city returns the city of a destination read through the catalog
+func city(t *testing.T, c *Catalog, id int) string {
+	t.Helper()
+	dest, err := c.Get(id)
+	if err != nil {
+		t.Fatalf("Get(%d): %v", id, err)
+	}
+	return dest.City
+}
+
+func TestReads(t *testing.T) {
+	c := New(newSource())
+
+	if _, err := c.Get(99); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Get of a missing destination: got %v, want sql.ErrNoRows", err)
+	}
+	if got := city(t, c, 3); got != "Atlantis" {
+		t.Errorf("Get of a retired destination: got %q", got)
+	}
+
+	for _, tt := range []struct {
+		name string
+		list func() ([]models.Destination, error)
+		want []int
+	}{
+		{"active", func() ([]models.Destination, error) { return c.List(false) }, []int{1, 2}},
+		{"all", func() ([]models.Destination, error) { return c.List(true) }, []int{1, 2, 3}},
+		{"by country", func() ([]models.Destination, error) { return c.ByCountry(" FRANCE ") }, []int{1}},
+		{"by country of a retired one", func() ([]models.Destination, error) { return c.ByCountry("Nowhere") }, nil},
+		{"by region", func() ([]models.Destination, error) { return c.ByRegion("asia") }, []int{2}},
+	} {
+		destinations, err := tt.list()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var ids []int
+		for _, dest := range destinations {
+			ids = append(ids, dest.ID)
+		}
+		if len(ids) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	s := newSource()
+	c := New(s)
+	if got := city(t, c, 1); got != "Paris" {
+		t.Fatalf("got %q, want Paris", got)
+	}
+
+	// A change through this process without a version change is only seen
+	// after Invalidate
+	s.rename(1, "Lutetia", false)
+	if got := city(t, c, 1); got != "Paris" {
+		t.Errorf("before Invalidate: got %q, want the loaded Paris", got)
+	}
+	c.Invalidate()
+	if got := city(t, c, 1); got != "Lutetia" {
+		t.Errorf("after Invalidate: got %q, want Lutetia", got)
+	}
+	if s.loads != 2 {
+		t.Errorf("loaded %d times, want 2", s.loads)
+	}
+}
+
+func TestVersionChange(t *testing.T) {
+	s := newSource()
+	c := New(s)
+	c.checkInterval = 0 // Compare the version on every read
+	city(t, c, 1)
+
+	// An unchanged version keeps the loaded destinations
+	city(t, c, 1)
+	if s.loads != 1 {
+		t.Errorf("loaded %d times with an unchanged version, want 1", s.loads)
+	}
+
+	// A change by another process bumps the version
+	s.rename(1, "Lutetia", true)
+	if got := city(t, c, 1); got != "Lutetia" {
+		t.Errorf("after a version change: got %q, want Lutetia", got)
+	}
+	if s.loads != 2 {
+		t.Errorf("loaded %d times, want 2", s.loads)
+	}
+
+	// Within the check interval the version isn't asked again
+	c.checkInterval = DefaultCheckInterval
+	s.rename(1, "Paris", true)
+	if got := city(t, c, 1); got != "Lutetia" {
+		t.Errorf("within the check interval: got %q, want the loaded Lutetia", got)
+	}
+}
+
+// TestCopies checks that callers changing what they are returned don't
+// change the catalog
+func TestCopies(t *testing.T) {
+	c := New(newSource())
+
+	dest, err := c.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest.City = "Changed"
+	dest.Clues[0] = "Changed"
+	dest.ClueIDs[0] = 99
+	*dest.Latitude = 0
+
+	listed, err := c.List(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed[0].Clues = append(listed[0].Clues[:1], "Appended")
+	listed[0].FunFact[0] = "Changed"
+	*listed[0].Longitude = 0
+
+	byCountry, err := c.ByCountry("France")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byCountry[0].Trivia[0] = "Changed"
+
+	got, err := c.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.City != "Paris" || got.Clues[0] != "Iron lady" || got.Clues[1] != "City of light" || got.ClueIDs[0] != 1 ||
+		got.FunFact[0] != "Fun" || got.Trivia[0] != "Trivia" || *got.Latitude != 48.85 || *got.Longitude != 2.35 {
+		t.Errorf("catalog changed through returned copies: %+v", got)
+	}
+}
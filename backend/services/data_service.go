@@ -1,58 +1,52 @@
 package services
 
 import (
-	"encoding/json"
-	"io/ioutil"
-	"math/rand"
-	"time"
+	"fmt"
 
-	"github.com/shubhsherl/globetrotter/backend/db"
 	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/catalog"
+	"github.com/shubhsherl/globetrotter/backend/storage"
 )
 
-var destinations []models.Destination
-
-func init() {
-	rand.Seed(time.Now().UnixNano())
-	loadDestinations()
-}
-
-func loadDestinations() {
-	data, err := ioutil.ReadFile("data/data.json")
-	if err != nil {
-		panic("Failed to load destinations data: " + err.Error())
-	}
-
-	if err := json.Unmarshal(data, &destinations); err != nil {
-		panic("Failed to parse destinations data: " + err.Error())
-	}
-}
-
 // DataService coordinates access to other services
 type DataService struct {
 	destinationService *DestinationService
 	userService        *UserService
 	gameService        *GameService
+	submissionService  *SubmissionService
+	analyticsService   *AnalyticsService
+	datasetService     *DatasetService
+	auditService       *AuditService
 }
 
-// NewDataService creates a new data service
-func NewDataService(database *db.Database) *DataService {
+// NewDataService creates a new data service. Its services share one
+// destination catalog, loaded from the store on first use.
+func NewDataService(store storage.Store) *DataService {
+	destinations := catalog.New(store)
 	return &DataService{
-		destinationService: NewDestinationService(database),
-		userService:        NewUserService(database),
-		gameService:        NewGameService(database),
+		destinationService: NewDestinationService(store, destinations),
+		userService:        NewUserService(store),
+		gameService:        NewGameService(store, store, store, destinations),
+		submissionService:  NewSubmissionService(store, store, store, destinations),
+		analyticsService:   NewAnalyticsService(store),
+		datasetService:     NewDatasetService(store, destinations),
+		auditService:       NewAuditService(store),
 	}
 }
 
-// Then add methods that delegate to the appropriate service
-// For example:
-func (s *DataService) GetRandomDestination() (models.Destination, error) {
-	return s.destinationService.GetRandomDestination()
+// GetRandomDestination delegates to the destination service
+func (s *DataService) GetRandomDestination(country, region string) (models.Destination, error) {
+	return s.destinationService.GetRandomDestination(country, region)
 }
 
 // CreateUser delegates to the user service
-func (s *DataService) CreateUser(username string) (models.User, error) {
-	return s.userService.CreateUser(username)
+func (s *DataService) CreateUser(username, locale string) (models.User, error) {
+	return s.userService.CreateUser(username, locale)
+}
+
+// SetUserLocale delegates to the user service
+func (s *DataService) SetUserLocale(username, locale string) (models.User, error) {
+	return s.userService.SetLocale(username, locale)
 }
 
 // GetUser delegates to the user service
@@ -61,23 +55,74 @@ func (s *DataService) GetUser(username string) (models.User, error) {
 }
 
 // CreateGame delegates to the game service
-func (s *DataService) CreateGame(username string) (int, error) {
+func (s *DataService) CreateGame(username string, scoring string) (int, error) {
 	// Get user ID from username
 	user, err := s.GetUser(username)
 	if err != nil {
 		return 0, err
 	}
-	return s.gameService.CreateGame(user.ID)
+	return s.gameService.CreateGame(user.ID, scoring)
+}
+
+// GameLocales delegates to the game service
+func (s *DataService) GameLocales(gameID int, accepted []string) []string {
+	return s.gameService.Locales(gameID, accepted)
 }
 
 // GetNextQuestion delegates to the game service
-func (s *DataService) GetNextQuestion(gameID int) (*models.GameQuestionDetail, error) {
-	return s.gameService.GetNextQuestion(gameID)
+func (s *DataService) GetNextQuestion(gameID int, locales []string) (*models.GameQuestionDetail, error) {
+	return s.gameService.GetNextQuestion(gameID, locales)
+}
+
+// NextQuestion returns the next question of a game as the APIs serve it:
+// with the names of its options, the game's scoring mode and whether more
+// questions follow
+func (s *DataService) NextQuestion(gameID int, locales []string) (*models.NextQuestionResponse, error) {
+	question, err := s.GetNextQuestion(gameID, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if there are more questions
+	hasNext, err := s.HasNextQuestion(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients need the scoring mode to know whether to show options or a map
+	game, err := s.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get destination details for all options at once
+	options, err := s.GetLocalizedDestinations(question.OptionDestinationIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+	optionsDisplay := make(map[int]string)
+	for destID, dest := range options {
+		optionsDisplay[destID] = fmt.Sprintf("%s, %s", dest.City, dest.Country)
+	}
+
+	return &models.NextQuestionResponse{
+		GameID:         gameID,
+		QuestionID:     question.ID,
+		Question:       question.Question,
+		OptionsDisplay: optionsDisplay,
+		Scoring:        game.Scoring,
+		HasNext:        hasNext,
+	}, nil
 }
 
 // SubmitAnswer delegates to the game service
-func (s *DataService) SubmitAnswer(gameID, questionID int, selectedDestinationID int) (*models.SubmitAnswerResponse, error) {
-	return s.gameService.SubmitAnswer(gameID, questionID, selectedDestinationID)
+func (s *DataService) SubmitAnswer(gameID, questionID int, selectedDestinationID int, pin *models.Pin, locales []string) (*models.SubmitAnswerResponse, error) {
+	return s.gameService.SubmitAnswer(gameID, questionID, selectedDestinationID, pin, locales)
+}
+
+// GetGame delegates to the game service
+func (s *DataService) GetGame(gameID int) (*models.Game, error) {
+	return s.gameService.GetGame(gameID)
 }
 
 // GetGameResult delegates to the game service
@@ -95,7 +140,112 @@ func (s *DataService) GetDestinationByID(destinationID int) (*models.Destination
 	return s.gameService.GetDestinationByID(destinationID)
 }
 
+// GetLocalizedDestinations delegates to the game service
+func (s *DataService) GetLocalizedDestinations(destinationIDs []int, locales []string) (map[int]models.Destination, error) {
+	return s.gameService.GetLocalizedDestinations(destinationIDs, locales)
+}
+
 // GetGameSummary delegates to the game service
 func (s *DataService) GetGameSummary(gameID int) (*models.GameSummary, error) {
 	return s.gameService.GetGameSummary(gameID)
 }
+
+// ListDestinations delegates to the destination service
+func (s *DataService) ListDestinations(includeRetired bool) ([]models.Destination, error) {
+	return s.destinationService.ListDestinations(includeRetired)
+}
+
+// GetDestination delegates to the destination service
+func (s *DataService) GetDestination(destinationID int) (*models.Destination, error) {
+	return s.destinationService.GetDestination(destinationID)
+}
+
+// CreateDestination delegates to the destination service
+func (s *DataService) CreateDestination(actor string, dest models.Destination) (*models.Destination, error) {
+	return s.destinationService.CreateDestination(actor, dest)
+}
+
+// UpdateDestination delegates to the destination service
+func (s *DataService) UpdateDestination(actor string, dest models.Destination) (*models.Destination, error) {
+	return s.destinationService.UpdateDestination(actor, dest)
+}
+
+// DeleteDestination delegates to the destination service
+func (s *DataService) DeleteDestination(actor string, destinationID int) (bool, error) {
+	return s.destinationService.DeleteDestination(actor, destinationID)
+}
+
+// ListClues delegates to the destination service
+func (s *DataService) ListClues(destinationID int, includeRetired bool) ([]models.Clue, error) {
+	return s.destinationService.ListClues(destinationID, includeRetired)
+}
+
+// GetClue delegates to the destination service
+func (s *DataService) GetClue(clueID int) (*models.Clue, error) {
+	return s.destinationService.GetClue(clueID)
+}
+
+// UpdateClue delegates to the destination service
+func (s *DataService) UpdateClue(actor string, clue models.Clue) (*models.Clue, error) {
+	return s.destinationService.UpdateClue(actor, clue)
+}
+
+// SubmitContent delegates to the submission service
+func (s *DataService) SubmitContent(username string, sub models.Submission) (*models.Submission, error) {
+	return s.submissionService.Submit(username, sub)
+}
+
+// ListUserSubmissions delegates to the submission service
+func (s *DataService) ListUserSubmissions(username string) ([]models.Submission, error) {
+	return s.submissionService.ListUserSubmissions(username)
+}
+
+// ListSubmissions delegates to the submission service
+func (s *DataService) ListSubmissions(status string) ([]models.Submission, error) {
+	return s.submissionService.ListSubmissions(status)
+}
+
+// GetSubmission delegates to the submission service
+func (s *DataService) GetSubmission(submissionID int) (*models.Submission, error) {
+	return s.submissionService.GetSubmission(submissionID)
+}
+
+// ApproveSubmission delegates to the submission service
+func (s *DataService) ApproveSubmission(actor string, sub models.Submission) (*models.Destination, error) {
+	return s.submissionService.ApproveSubmission(actor, sub)
+}
+
+// RejectSubmission delegates to the submission service
+func (s *DataService) RejectSubmission(actor string, submissionID int, note string) (*models.Submission, error) {
+	return s.submissionService.RejectSubmission(actor, submissionID, note)
+}
+
+// RefreshDifficulty delegates to the analytics service
+func (s *DataService) RefreshDifficulty() error {
+	return s.analyticsService.RefreshDifficulty()
+}
+
+// ListDestinationDifficulty delegates to the analytics service
+func (s *DataService) ListDestinationDifficulty() ([]models.DifficultyStats, error) {
+	return s.analyticsService.ListDestinationDifficulty()
+}
+
+// ListClueDifficulty delegates to the analytics service
+func (s *DataService) ListClueDifficulty() ([]models.DifficultyStats, error) {
+	return s.analyticsService.ListClueDifficulty()
+}
+
+// DifficultyReport delegates to the analytics service
+func (s *DataService) DifficultyReport(minSamples int) (*models.DifficultyReport, error) {
+	return s.analyticsService.DifficultyReport(minSamples)
+}
+
+// ReloadDataset delegates to the dataset service
+func (s *DataService) ReloadDataset(actor, path string, prune bool) (*models.DatasetReload, error) {
+	return s.datasetService.Reload(actor, path, prune)
+}
+
+// ListAuditEntries delegates to the audit service
+func (s *DataService) ListAuditEntries(filter storage.AuditFilter) ([]models.AuditEntry, error) {
+	return s.auditService.List(filter)
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/dataset"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/catalog"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// InvalidDatasetError describes why a dataset was not loaded. It matches
+// ErrValidation.
+type InvalidDatasetError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *InvalidDatasetError) Error() string {
+	return fmt.Sprintf("invalid dataset %s: %s", e.Path, strings.Join(e.Problems, "; "))
+}
+
+// Is reports whether target is ErrValidation
+func (e *InvalidDatasetError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// DatasetService loads the destination dataset file into a running server
+type DatasetService struct {
+	destinations storage.DestinationRepository
+	catalog      *catalog.Catalog
+
+	mu sync.Mutex // Serializes reloads
+}
+
+// NewDatasetService creates a new dataset service
+func NewDatasetService(destinations storage.DestinationRepository, catalog *catalog.Catalog) *DatasetService {
+	return &DatasetService{
+		destinations: destinations,
+		catalog:      catalog,
+	}
+}
+
+// loadedDataset is a dataset file and its translations, parsed and validated
+type loadedDataset struct {
+	destinations []models.Destination
+	warnings     []string
+	locales      []string
+	translations map[string][]dataset.TranslationEntry
+}
+
+// Reload replaces the stored destinations with the dataset at path, like
+// `dataset import`, and applies the translation files next to it. The
+// dataset and every translation file are read and validated before anything
+// is written; if one fails, Reload returns an InvalidDatasetError and the
+// current destinations keep being served. Destinations and translations are
+// written in one transaction, so games never see part of a dataset or a
+// dataset without its translations, and recorded in the audit log under
+// actor.
+func (s *DatasetService) Reload(actor, path string, prune bool) (*models.DatasetReload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report, err := s.reload(actor, path, prune)
+	if err != nil {
+		log.Printf("Dataset reload failed, still serving the current destinations: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Reloaded dataset %s: %d added, %d updated, %d unchanged, %d missing",
+		path, report.Added, report.Updated, report.Unchanged, report.Missing)
+	return report, nil
+}
+
+// reload validates and imports the dataset at path
+func (s *DatasetService) reload(actor, path string, prune bool) (*models.DatasetReload, error) {
+	loaded, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.destinations.ListDestinations(true)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := dataset.Diff(existing, loaded.destinations)
+	added, updated, removedIDs := plan.Changes(prune)
+	changed := len(added) > 0 || len(updated) > 0 || len(removedIDs) > 0
+
+	report := &models.DatasetReload{
+		Path:      path,
+		Added:     len(plan.Added),
+		Updated:   len(plan.Updated),
+		Unchanged: len(plan.Unchanged),
+		Missing:   len(plan.Missing),
+		Locales:   loaded.locales,
+		Warnings:  append(loaded.warnings, plan.Warnings...),
+		LoadedAt:  time.Now(),
+	}
+
+	// Translations are matched against the destinations as the import leaves
+	// them, within its transaction
+	var translate storage.TranslateFunc
+	if len(loaded.locales) > 0 {
+		translate = func(stored []models.Destination) ([]models.DestinationTranslation, []models.TextTranslation, []models.TextTranslation, error) {
+			var names []models.DestinationTranslation
+			var clues, facts []models.TextTranslation
+			for _, locale := range loaded.locales {
+				translations := dataset.MatchTranslations(stored, loaded.translations[locale], locale)
+				names = append(names, translations.Names...)
+				clues = append(clues, translations.Clues...)
+				facts = append(facts, translations.Facts...)
+				for _, warning := range translations.Warnings {
+					report.Warnings = append(report.Warnings, locale+": "+warning)
+				}
+			}
+			return names, clues, facts, nil
+		}
+	}
+
+	if changed || translate != nil {
+		result, err := s.destinations.ImportDestinations(actor, added, updated, removedIDs, translate)
+		if err != nil {
+			return nil, err
+		}
+		report.Deleted, report.Retired = result.Deleted, result.Retired
+		if changed {
+			s.catalog.Invalidate()
+		}
+	}
+
+	if report.Warnings == nil {
+		report.Warnings = []string{}
+	}
+	if report.Locales == nil {
+		report.Locales = []string{}
+	}
+	return report, nil
+}
+
+// load reads the dataset at path and its translation files and checks that
+// they can be served
+func load(path string) (*loadedDataset, error) {
+	destinations, err := dataset.ReadFile(path)
+	if err != nil {
+		return nil, &InvalidDatasetError{Path: path, Problems: []string{err.Error()}}
+	}
+	if len(destinations) == 0 {
+		return nil, &InvalidDatasetError{Path: path, Problems: []string{"dataset has no destinations"}}
+	}
+
+	loaded := &loadedDataset{
+		destinations: destinations,
+		translations: make(map[string][]dataset.TranslationEntry),
+	}
+
+	var problems []string
+	for _, issue := range dataset.Lint(destinations, dataset.DefaultLintOptions) {
+		message := fmt.Sprintf("%s, %s: %s (%s)", issue.City, issue.Country, issue.Message, issue.Code)
+		if issue.Severity == dataset.SeverityError {
+			problems = append(problems, message)
+		} else {
+			loaded.warnings = append(loaded.warnings, message)
+		}
+	}
+	// Games also need each destination to have enough content to ask about
+	for i := range destinations {
+		var invalid *ValidationError
+		if err := ValidateDestination(&destinations[i]); err != nil && errors.As(err, &invalid) {
+			for _, problem := range invalid.Problems {
+				problems = append(problems, fmt.Sprintf("%s, %s: %s", destinations[i].City, destinations[i].Country, problem))
+			}
+		}
+	}
+
+	paths, err := dataset.LocaleFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, localePath := range paths {
+		locale, entries, err := dataset.ReadTranslationFile(localePath)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		loaded.locales = append(loaded.locales, locale)
+		loaded.translations[locale] = entries
+	}
+
+	if len(problems) > 0 {
+		return nil, &InvalidDatasetError{Path: path, Problems: problems}
+	}
+	return loaded, nil
+}
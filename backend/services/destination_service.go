@@ -1,43 +1,280 @@
 package services
 
 import (
+	"fmt"
 	"math/rand"
-	"time"
+	"slices"
+	"strings"
 
-	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/dataset"
 	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/catalog"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
+	"github.com/shubhsherl/globetrotter/backend/storage"
 )
 
 // DestinationService handles destination-related operations
 type DestinationService struct {
-	db *db.Database
+	destinations storage.DestinationRepository
+	catalog      *catalog.Catalog
 }
 
-// NewDestinationService creates a new destination service
-func NewDestinationService(database *db.Database) *DestinationService {
+// NewDestinationService creates a new destination service. Changes it makes
+// to destinations invalidate the catalog.
+func NewDestinationService(destinations storage.DestinationRepository, catalog *catalog.Catalog) *DestinationService {
 	return &DestinationService{
-		db: database,
+		destinations: destinations,
+		catalog:      catalog,
 	}
 }
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
-// GetRandomDestination returns a random destination with multiple choice options
-func (s *DestinationService) GetRandomDestination() (models.Destination, error) {
-	// Implementation depends on your database structure
-	// This is a placeholder implementation
-	destinations, err := s.db.GetAllDestinations()
+// GetRandomDestination returns a random active destination, optionally only
+// from a country or one of the geo regions
+func (s *DestinationService) GetRandomDestination(country, region string) (models.Destination, error) {
+	var destinations []models.Destination
+	var err error
+	switch {
+	case country != "":
+		destinations, err = s.catalog.ByCountry(country)
+	case region != "":
+		if !slices.Contains(geo.Regions, region) {
+			return models.Destination{}, ErrUnknownRegion
+		}
+		destinations, err = s.catalog.ByRegion(region)
+	default:
+		destinations, err = s.catalog.List(false)
+	}
 	if err != nil {
 		return models.Destination{}, err
 	}
-	
+
 	if len(destinations) == 0 {
 		return models.Destination{}, nil
 	}
-	
-	rand.Seed(time.Now().UnixNano())
-	randomIndex := rand.Intn(len(destinations))
-	return destinations[randomIndex], nil
+
+	return destinations[rand.Intn(len(destinations))], nil
+}
+
+// Minimum content a destination needs before it can be used in games
+const (
+	MinClues    = 2
+	MinFunFacts = 1
+	MinTrivia   = 1
+)
+
+// ValidationError describes why a destination, clue or submission was
+// rejected. It matches ErrValidation.
+type ValidationError struct {
+	Subject  string // What was rejected, "destination" when empty
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid " + e.subject() + ": " + strings.Join(e.Problems, "; ")
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Code is the stable error code of the rejection, such as "invalid_destination"
+func (e *ValidationError) Code() string {
+	return "invalid_" + e.subject()
+}
+
+// Message describes the rejection to clients, such as "Invalid destination"
+func (e *ValidationError) Message() string {
+	return "Invalid " + e.subject()
+}
+
+func (e *ValidationError) subject() string {
+	if e.Subject == "" {
+		return "destination"
+	}
+	return e.Subject
+}
+
+// ValidateDestination trims the destination's fields in place and checks that
+// it has a city, a country and enough clues, fun facts and trivia
+func ValidateDestination(dest *models.Destination) error {
+	var problems []string
+
+	dest.City = strings.TrimSpace(dest.City)
+	dest.Country = strings.TrimSpace(dest.Country)
+
+	if dest.City == "" {
+		problems = append(problems, "city is required")
+	}
+	if dest.Country == "" {
+		problems = append(problems, "country is required")
+	}
+
+	// Coordinates are optional but must come as a valid pair
+	if (dest.Latitude == nil) != (dest.Longitude == nil) {
+		problems = append(problems, "latitude and longitude must be given together")
+	} else if dest.Latitude != nil && !geo.ValidCoordinates(*dest.Latitude, *dest.Longitude) {
+		problems = append(problems, "latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+
+	problems = append(problems, validateTexts("clues", dest.Clues, MinClues)...)
+	for i, clue := range dest.Clues {
+		if dataset.ClueRevealsAnswer(clue, dest.City, dest.Country) {
+			problems = append(problems, fmt.Sprintf("clues[%d] names the city or country", i))
+		}
+	}
+	problems = append(problems, validateTexts("fun_fact", dest.FunFact, MinFunFacts)...)
+	problems = append(problems, validateTexts("trivia", dest.Trivia, MinTrivia)...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// validateTexts trims each entry and checks the list has at least min non-empty entries
+func validateTexts(field string, texts []string, min int) []string {
+	var problems []string
+
+	for i := range texts {
+		texts[i] = strings.TrimSpace(texts[i])
+		if texts[i] == "" {
+			problems = append(problems, fmt.Sprintf("%s[%d] is empty", field, i))
+		}
+	}
+
+	if len(texts) < min {
+		problems = append(problems, fmt.Sprintf("%s needs at least %d entries", field, min))
+	}
+
+	return problems
+}
+
+// ListDestinations returns all destinations, optionally including retired ones
+func (s *DestinationService) ListDestinations(includeRetired bool) ([]models.Destination, error) {
+	return s.catalog.List(includeRetired)
+}
+
+// GetDestination returns a single destination by ID
+func (s *DestinationService) GetDestination(destinationID int) (*models.Destination, error) {
+	dest, err := s.catalog.Get(destinationID)
+	return dest, notFound(err, ErrDestinationNotFound)
+}
+
+// CreateDestination validates and stores a new destination on behalf of actor
+func (s *DestinationService) CreateDestination(actor string, dest models.Destination) (*models.Destination, error) {
+	if err := ValidateDestination(&dest); err != nil {
+		return nil, err
+	}
+
+	id, err := s.destinations.CreateDestination(actor, dest)
+	if err != nil {
+		return nil, translate(err, storage.ErrDuplicateDestination, ErrDuplicateDestination)
+	}
+	s.catalog.Invalidate()
+
+	return s.destinations.GetDestinationByID(id)
+}
+
+// UpdateDestination validates and replaces an existing destination's content.
+// The ID is preserved so questions in running games keep pointing at it.
+func (s *DestinationService) UpdateDestination(actor string, dest models.Destination) (*models.Destination, error) {
+	if err := ValidateDestination(&dest); err != nil {
+		return nil, err
+	}
+
+	if err := s.destinations.UpdateDestination(actor, dest); err != nil {
+		return nil, notFound(translate(err, storage.ErrDuplicateDestination, ErrDuplicateDestination), ErrDestinationNotFound)
+	}
+	s.catalog.Invalidate()
+
+	return s.destinations.GetDestinationByID(dest.ID)
+}
+
+// DeleteDestination deletes a destination, or retires it if games reference it.
+// It reports whether the destination was retired.
+func (s *DestinationService) DeleteDestination(actor string, destinationID int) (bool, error) {
+	retired, err := s.destinations.DeleteDestination(actor, destinationID)
+	if err != nil {
+		return false, notFound(err, ErrDestinationNotFound)
+	}
+	s.catalog.Invalidate()
+
+	return retired, nil
+}
+
+// ListClues returns the clues of a destination, optionally including retired ones
+func (s *DestinationService) ListClues(destinationID int, includeRetired bool) ([]models.Clue, error) {
+	// Surface a missing destination as not found rather than an empty list
+	if _, err := s.catalog.Get(destinationID); err != nil {
+		return nil, notFound(err, ErrDestinationNotFound)
+	}
+
+	return s.destinations.ListClues(destinationID, includeRetired)
+}
+
+// GetClue returns a single clue by ID
+func (s *DestinationService) GetClue(clueID int) (*models.Clue, error) {
+	clue, err := s.destinations.GetClueByID(clueID)
+	return clue, notFound(err, ErrClueNotFound)
+}
+
+// UpdateClue edits, tags or retires a single clue, keeping its ID so questions
+// that showed it still point at it
+func (s *DestinationService) UpdateClue(actor string, clue models.Clue) (*models.Clue, error) {
+	current, err := s.destinations.GetClueByID(clue.ID)
+	if err != nil {
+		return nil, notFound(err, ErrClueNotFound)
+	}
+
+	dest, err := s.destinations.GetDestinationByID(current.DestinationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+
+	clue.DestinationID = current.DestinationID
+	clue.Text = strings.TrimSpace(clue.Text)
+	if clue.Text == "" {
+		problems = append(problems, "text is required")
+	} else if dataset.ClueRevealsAnswer(clue.Text, dest.City, dest.Country) {
+		problems = append(problems, "text names the city or country")
+	}
+
+	if clue.Retired && !current.Retired && len(dest.Clues) <= MinClues {
+		problems = append(problems, fmt.Sprintf("destination needs at least %d active clues", MinClues))
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Subject: "clue", Problems: problems}
+	}
+
+	clue.Tags = normalizeTags(clue.Tags)
+
+	if err := s.destinations.UpdateClue(actor, clue); err != nil {
+		return nil, err
+	}
+	s.catalog.Invalidate()
+
+	return s.destinations.GetClueByID(clue.ID)
+}
+
+// normalizeTags lowercases and trims tags, dropping empty and repeated ones.
+// Commas are replaced since tags are stored comma-separated.
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, ",", " ")))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
 }
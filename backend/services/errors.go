@@ -0,0 +1,100 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Kinds of domain errors. Errors returned by the services match one of them
+// with errors.Is when the caller is at fault, and the API maps each kind to
+// an HTTP status. Any other error is an internal failure.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("invalid")
+	ErrForbidden  = errors.New("forbidden")
+)
+
+// Error is a domain error: a kind, a stable code clients can branch on and a
+// message that is safe to show them. It matches its kind and any Error with
+// the same code with errors.Is, and unwraps to the error that caused it.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Is reports whether target is the error's kind or an error with its code
+func (e *Error) Is(target error) bool {
+	if t, ok := target.(*Error); ok {
+		return t.Code == e.Code
+	}
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithMessage returns a copy of the error with a more specific message
+func (e *Error) WithMessage(message string) *Error {
+	copied := *e
+	copied.Message = message
+	return &copied
+}
+
+// Wrap returns a copy of the error caused by err
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// Domain errors returned by the services
+var (
+	ErrUserNotFound        = &Error{Kind: ErrNotFound, Code: "user_not_found", Message: "User not found"}
+	ErrGameNotFound        = &Error{Kind: ErrNotFound, Code: "game_not_found", Message: "Game not found"}
+	ErrQuestionNotFound    = &Error{Kind: ErrNotFound, Code: "question_not_found", Message: "Question not found"}
+	ErrGameFinished        = &Error{Kind: ErrNotFound, Code: "game_finished", Message: "The game has no questions left"}
+	ErrGameCompacted       = &Error{Kind: ErrNotFound, Code: "game_compacted", Message: "The game was archived and its questions removed"}
+	ErrDestinationNotFound = &Error{Kind: ErrNotFound, Code: "destination_not_found", Message: "Destination not found"}
+	ErrClueNotFound        = &Error{Kind: ErrNotFound, Code: "clue_not_found", Message: "Clue not found"}
+	ErrSubmissionNotFound  = &Error{Kind: ErrNotFound, Code: "submission_not_found", Message: "Submission not found"}
+
+	ErrUsernameTaken         = &Error{Kind: ErrConflict, Code: "username_taken", Message: "Username already exists"}
+	ErrDuplicateDestination  = &Error{Kind: ErrConflict, Code: "duplicate_destination", Message: "Destination with this city and country already exists"}
+	ErrSubmissionReviewed    = &Error{Kind: ErrConflict, Code: "submission_reviewed", Message: "Submission has already been reviewed"}
+	ErrNotEnoughDestinations = &Error{Kind: ErrConflict, Code: "not_enough_destinations", Message: "Not enough destinations to create a game"}
+
+	// ErrUnknownRegion is returned when destinations are filtered by a region that doesn't exist
+	ErrUnknownRegion = &Error{Kind: ErrValidation, Code: "unknown_region", Message: "Unknown region"}
+	// ErrInvalidLocale is returned when a preferred language is not a valid language tag
+	ErrInvalidLocale = &Error{Kind: ErrValidation, Code: "invalid_locale", Message: "Invalid locale"}
+	// ErrInvalidScoring is returned when a game is started with an unknown scoring mode
+	ErrInvalidScoring = &Error{Kind: ErrValidation, Code: "invalid_scoring", Message: `Scoring must be "standard", "proximity" or "pin"`}
+	// ErrInvalidAnswer is returned when an answer doesn't fit the game's mode
+	ErrInvalidAnswer = &Error{Kind: ErrValidation, Code: "invalid_answer", Message: "Invalid answer"}
+)
+
+// translate returns domainErr caused by err when err is cause, and err
+// otherwise. Repositories report missing rows as sql.ErrNoRows and conflicts
+// with the sentinel errors of the storage package.
+func translate(err, cause error, domainErr *Error) error {
+	if errors.Is(err, cause) {
+		return domainErr.Wrap(err)
+	}
+	return err
+}
+
+// notFound translates a missing row into domainErr
+func notFound(err error, domainErr *Error) error {
+	return translate(err, sql.ErrNoRows, domainErr)
+}
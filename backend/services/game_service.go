@@ -1,37 +1,91 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"time"
 
-	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
 	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/catalog"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
 	"github.com/shubhsherl/globetrotter/backend/services/images"
+	"github.com/shubhsherl/globetrotter/backend/storage"
 )
 
+// AlreadyAnsweredError is returned when a question has been answered before,
+// for example by a retried or double-tapped request. Answer is the outcome
+// of the answer that was recorded. It matches ErrConflict.
+type AlreadyAnsweredError struct {
+	Answer *models.SubmitAnswerResponse
+}
+
+func (e *AlreadyAnsweredError) Error() string {
+	return "question already answered"
+}
+
+// Is reports whether target is ErrConflict
+func (e *AlreadyAnsweredError) Is(target error) bool {
+	return target == ErrConflict
+}
+
 // GameService handles game-related operations
 type GameService struct {
-	db *db.Database
+	games        storage.GameRepository
+	users        storage.UserRepository
+	destinations storage.DestinationRepository
+	catalog      *catalog.Catalog
 }
 
-// NewGameService creates a new game service
-func NewGameService(database *db.Database) *GameService {
+// NewGameService creates a new game service. Destinations are read from the
+// catalog; translations, clues and photos from the repository.
+func NewGameService(games storage.GameRepository, users storage.UserRepository, destinations storage.DestinationRepository, catalog *catalog.Catalog) *GameService {
 	return &GameService{
-		db: database,
+		games:        games,
+		users:        users,
+		destinations: destinations,
+		catalog:      catalog,
 	}
 }
 
-// CreateGame creates a new game for a user
-func (s *GameService) CreateGame(userID int) (int, error) {
-	// Create a new game
-	gameID, err := s.db.CreateGame(userID, 5) // 5 questions per game
+// CreateGame creates a new game for a user. Scoring defaults to standard
+// (right or wrong); proximity scoring also awards points for near misses, and
+// pin games replace the options with a pin dropped on the map.
+func (s *GameService) CreateGame(userID int, scoring string) (int, error) {
+	if scoring == "" {
+		scoring = models.ScoringStandard
+	}
+	if scoring != models.ScoringStandard && scoring != models.ScoringProximity && scoring != models.ScoringPin {
+		return 0, ErrInvalidScoring
+	}
+
+	// Get all active destinations
+	destinations, err := s.catalog.List(false)
 	if err != nil {
 		return 0, err
 	}
 
-	// Get all destinations
-	destinations, err := s.db.GetAllDestinations()
+	// Pin answers can only be scored against destinations with coordinates
+	if scoring == models.ScoringPin {
+		located := destinations[:0]
+		for _, dest := range destinations {
+			if dest.Latitude != nil && dest.Longitude != nil {
+				located = append(located, dest)
+			}
+		}
+		destinations = located
+	}
+
+	// Retiring destinations can shrink the pool below what a game needs
+	if len(destinations) < 5 {
+		return 0, ErrNotEnoughDestinations
+	}
+
+	// Create a new game
+	gameID, err := s.games.CreateGame(userID, 5, scoring) // 5 questions per game
 	if err != nil {
 		return 0, err
 	}
@@ -48,14 +102,25 @@ func (s *GameService) CreateGame(userID int) (int, error) {
 	for _, dest := range questionDestinations {
 		// Use a random clue as the question
 		var question string
+		var clueID int
 		if len(dest.Clues) > 0 {
-			// Pick a random clue
-			question = dest.Clues[rand.Intn(len(dest.Clues))]
+			// Pick a random clue and remember which one was shown
+			clueIndex := rand.Intn(len(dest.Clues))
+			question = dest.Clues[clueIndex]
+			clueID = dest.ClueIDs[clueIndex]
 		} else {
 			// Fallback to default question if no clues available
 			question = fmt.Sprintf("Where is %s located?", dest.City)
 		}
 
+		// Pin games have no options to choose from
+		if scoring == models.ScoringPin {
+			if _, err := s.games.AddGameQuestion(gameID, question, []int{}, dest.ID, clueID); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
 		// Generate options (3 wrong options + 1 correct)
 		optionDestinations := []models.Destination{dest} // Add correct destination
 
@@ -87,7 +152,7 @@ func (s *GameService) CreateGame(userID int) (int, error) {
 		}
 
 		// Add question to game
-		_, err = s.db.AddGameQuestion(gameID, question, optionDestinationIDs, dest.ID)
+		_, err = s.games.AddGameQuestion(gameID, question, optionDestinationIDs, dest.ID, clueID)
 		if err != nil {
 			return 0, err
 		}
@@ -96,114 +161,360 @@ func (s *GameService) CreateGame(userID int) (int, error) {
 	return gameID, nil
 }
 
-// GetNextQuestion gets the next unanswered question for a game
-func (s *GameService) GetNextQuestion(gameID int) (*models.GameQuestionDetail, error) {
+// Locales returns the fallback chain used for a game's content: the player's
+// preferred language first, then the accepted languages of the request
+func (s *GameService) Locales(gameID int, accepted []string) []string {
+	game, err := s.games.GetGame(gameID)
+	if err != nil {
+		return i18n.Chain(accepted...)
+	}
+
+	user, err := s.users.GetUserByID(game.UserID)
+	if err != nil {
+		return i18n.Chain(accepted...)
+	}
+
+	return i18n.Chain(append([]string{user.Locale}, accepted...)...)
+}
+
+// GetNextQuestion gets the next unanswered question for a game, translated
+// into the first locale of the chain that has the clue
+func (s *GameService) GetNextQuestion(gameID int, locales []string) (*models.GameQuestionDetail, error) {
 	// Get the next question
-	question, err := s.db.GetNextQuestion(gameID)
+	question, err := s.games.GetNextQuestion(gameID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either the game doesn't exist or every question is answered
+		if _, err := s.GetGame(gameID); err != nil {
+			return nil, err
+		}
+		return nil, ErrGameFinished
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if question.ClueID != 0 {
+		text, ok, err := s.destinations.TranslateClue(question.ClueID, locales)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			question.Question = text
+		}
+	} else if dest, err := s.GetLocalizedDestination(question.CorrectDestinationID, locales); err == nil {
+		// Questions without a clue name the city, so rebuild them in the player's language
+		question.Question = i18n.Sprintf(locales, "Where is %s located?", dest.City)
+	}
+
 	// Don't return the correct destination ID to the client
 	question.CorrectDestinationID = 0
 
 	return question, nil
 }
 
-// SubmitAnswer submits an answer for a question
-func (s *GameService) SubmitAnswer(gameID, questionID, selectedDestinationID int) (*models.SubmitAnswerResponse, error) {
+// SubmitAnswer submits an answer for a question. Multiple choice games expect
+// one of the question's options, pin games expect a pin.
+func (s *GameService) SubmitAnswer(gameID, questionID, selectedDestinationID int, pin *models.Pin, locales []string) (*models.SubmitAnswerResponse, error) {
 	// Check if the question has already been answered
-	question, err := s.db.GetQuestionByID(gameID, questionID)
+	question, err := s.games.GetQuestionByID(gameID, questionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The retention job removes the questions of old finished games
+		if game, gameErr := s.games.GetGame(gameID); gameErr == nil && game.CompactedAt != nil {
+			return nil, ErrGameCompacted
+		}
+	}
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, ErrQuestionNotFound)
 	}
 
 	if question.IsAnswered == 1 {
-		return nil, fmt.Errorf("question already answered")
+		return nil, s.alreadyAnswered(question, locales)
 	}
 
-	// Validate that the selected destination ID is in the list of options
-	isValidOption := false
-	for _, optionID := range question.OptionDestinationIDs {
-		if optionID == selectedDestinationID {
-			isValidOption = true
-			break
-		}
+	game, err := s.GetGame(gameID)
+	if err != nil {
+		return nil, err
 	}
 
-	if !isValidOption {
-		return nil, fmt.Errorf("selected destination is not in the list of options")
+	if game.Scoring == models.ScoringPin {
+		if pin == nil {
+			return nil, ErrInvalidAnswer.WithMessage("Pin games are answered with a pin")
+		}
+		if !geo.ValidCoordinates(pin.Latitude, pin.Longitude) {
+			return nil, ErrInvalidAnswer.WithMessage("Pin coordinates are out of range")
+		}
+		selectedDestinationID = 0
+	} else {
+		if pin != nil {
+			return nil, ErrInvalidAnswer.WithMessage("Only pin games accept a pin")
+		}
+
+		// Validate that the selected destination ID is in the list of options
+		isValidOption := false
+		for _, optionID := range question.OptionDestinationIDs {
+			if optionID == selectedDestinationID {
+				isValidOption = true
+				break
+			}
+		}
+
+		if !isValidOption {
+			return nil, ErrInvalidAnswer.WithMessage("Selected destination is not in the list of options")
+		}
 	}
 
 	// Get the correct destination details
-	correctDest, err := s.db.GetDestinationByID(question.CorrectDestinationID)
+	correctDest, err := s.catalog.Get(question.CorrectDestinationID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Submit the answer
-	err = s.db.SubmitAnswer(gameID, questionID, selectedDestinationID)
+	answer := storage.Answer{
+		SelectedDestinationID: selectedDestinationID,
+		CorrectLatitude:       correctDest.Latitude,
+		CorrectLongitude:      correctDest.Longitude,
+	}
+
+	if pin != nil {
+		// A pin close enough to earn full points counts as correct
+		answer.PinLatitude, answer.PinLongitude = &pin.Latitude, &pin.Longitude
+		answer.DistanceKm = distanceBetween(&models.Destination{Latitude: &pin.Latitude, Longitude: &pin.Longitude}, correctDest)
+		answer.Correct = answer.DistanceKm != nil && *answer.DistanceKm <= geo.FullPointsRadiusKm
+	} else {
+		answer.Correct = selectedDestinationID == question.CorrectDestinationID
+
+		// Measure how far off a wrong answer was, when both places are located
+		if !answer.Correct {
+			selectedDest, err := s.catalog.Get(selectedDestinationID)
+			if err != nil {
+				return nil, err
+			}
+			answer.DistanceKm = distanceBetween(selectedDest, correctDest)
+		}
+	}
+
+	answer.Points = scoreAnswer(game.Scoring, answer.Correct, answer.DistanceKm)
+
+	// Submit the answer; only one request per question can record it
+	err = s.games.SubmitAnswer(gameID, questionID, answer)
+	if errors.Is(err, storage.ErrAlreadyAnswered) {
+		// Another request answered the question after it was loaded
+		question, err = s.games.GetQuestionByID(gameID, questionID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.alreadyAnswered(question, locales)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if the answer is correct
-	isCorrect := selectedDestinationID == question.CorrectDestinationID
+	return s.revealAnswer(question, correctDest, answer, locales)
+}
+
+// alreadyAnswered returns an AlreadyAnsweredError with the outcome recorded for an answered question
+func (s *GameService) alreadyAnswered(question *models.GameQuestionDetail, locales []string) error {
+	correctDest, err := s.catalog.Get(question.CorrectDestinationID)
+	if err != nil {
+		return err
+	}
+
+	answer := storage.Answer{
+		SelectedDestinationID: question.SelectedDestinationID,
+		PinLatitude:           question.PinLatitude,
+		PinLongitude:          question.PinLongitude,
+		Points:                question.Points,
+		DistanceKm:            question.DistanceKm,
+	}
+	if answer.PinLatitude != nil {
+		answer.Correct = answer.DistanceKm != nil && *answer.DistanceKm <= geo.FullPointsRadiusKm
+	} else {
+		answer.Correct = answer.SelectedDestinationID == question.CorrectDestinationID
+	}
+
+	response, err := s.revealAnswer(question, correctDest, answer, locales)
+	if err != nil {
+		return err
+	}
+
+	return &AlreadyAnsweredError{Answer: response}
+}
+
+// revealAnswer builds the response to an answer: whether it was right, the
+// correct destination in the player's language and a fun fact or trivia entry
+func (s *GameService) revealAnswer(question *models.GameQuestionDetail, correctDest *models.Destination, answer storage.Answer, locales []string) (*models.SubmitAnswerResponse, error) {
+	isCorrect := answer.Correct
+	pinAnswer := answer.PinLatitude != nil
+
+	// Reveal the answer in the player's language
+	localized := []models.Destination{*correctDest}
+	if err := s.destinations.LocalizeDestinations(localized, locales); err != nil {
+		return nil, err
+	}
+	correctDest = &localized[0]
 
 	// Prepare response
 	response := &models.SubmitAnswerResponse{
-		Correct:         isCorrect,
-		CorrectCity:     correctDest.City,
-		CorrectCountry:  correctDest.Country,
-		CorrectOptionID: question.CorrectDestinationID,
+		Correct:          isCorrect,
+		CorrectCity:      correctDest.City,
+		CorrectCountry:   correctDest.Country,
+		CorrectOptionID:  question.CorrectDestinationID,
+		Points:           answer.Points,
+		CorrectLatitude:  correctDest.Latitude,
+		CorrectLongitude: correctDest.Longitude,
 	}
 
-	// Add fun fact or trivia based on correctness
+	// Credit the player who contributed the clue
+	if question.ClueID != 0 {
+		clue, err := s.destinations.GetClueByID(question.ClueID)
+		if err != nil {
+			return nil, err
+		}
+		response.ClueSubmittedBy = clue.SubmittedBy
+	}
+
+	// Show the destination's photo with the reveal
+	image, err := s.destinations.GetDestinationImage(question.CorrectDestinationID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if image != nil {
+		image.URL = images.MediaURL(image.File)
+		response.Image = image
+	}
+
+	// Pin answers always report their distance so the miss can be drawn on the map
+	if !isCorrect || pinAnswer {
+		response.DistanceKm = answer.DistanceKm
+	}
+
+	// Add fun fact or trivia based on correctness, picked by the question so
+	// that repeated answers are told the same one as the recorded answer
 	if isCorrect && len(correctDest.FunFact) > 0 {
-		// Pick a random fun fact
-		response.FunFact = correctDest.FunFact[rand.Intn(len(correctDest.FunFact))]
+		response.FunFact = correctDest.FunFact[question.ID%len(correctDest.FunFact)]
 	} else if !isCorrect && len(correctDest.Trivia) > 0 {
-		// Pick a random trivia
-		response.Trivia = correctDest.Trivia[rand.Intn(len(correctDest.Trivia))]
+		response.Trivia = correctDest.Trivia[question.ID%len(correctDest.Trivia)]
 	}
 
 	return response, nil
 }
 
+// distanceBetween returns the distance between two destinations, rounded to
+// the kilometre, or nil if either has no coordinates
+func distanceBetween(a, b *models.Destination) *float64 {
+	if a.Latitude == nil || a.Longitude == nil || b.Latitude == nil || b.Longitude == nil {
+		return nil
+	}
+
+	distance := math.Round(geo.DistanceKm(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude))
+	return &distance
+}
+
+// scoreAnswer works out the points an answer earns under a game's scoring mode.
+// Correct answers always earn full points; in proximity and pin games wrong
+// answers earn a share that shrinks with their distance from the right one.
+func scoreAnswer(scoring string, isCorrect bool, distanceKm *float64) int {
+	if isCorrect {
+		return geo.MaxPoints
+	}
+	if (scoring == models.ScoringProximity || scoring == models.ScoringPin) && distanceKm != nil {
+		return geo.ProximityPoints(*distanceKm)
+	}
+	return 0
+}
+
+// GetGame gets a game by its ID
+func (s *GameService) GetGame(gameID int) (*models.Game, error) {
+	game, err := s.games.GetGame(gameID)
+	return game, notFound(err, ErrGameNotFound)
+}
+
 // GetGameResult gets the result of a game
 func (s *GameService) GetGameResult(gameID int) (*models.GameResult, error) {
-	return s.db.GetGameResult(gameID)
+	result, err := s.games.GetGameResult(gameID)
+	return result, notFound(err, ErrGameNotFound)
 }
 
 // HasNextQuestion checks if a game has more unanswered questions
 func (s *GameService) HasNextQuestion(gameID int) (bool, error) {
-	return s.db.HasNextQuestion(gameID)
+	return s.games.HasNextQuestion(gameID)
 }
 
 // GetDestinationByID gets a destination by its ID
 func (s *GameService) GetDestinationByID(destinationID int) (*models.Destination, error) {
-	return s.db.GetDestinationByID(destinationID)
+	return s.catalog.Get(destinationID)
+}
+
+// GetLocalizedDestinations gets destinations by their IDs, translated along
+// the locale chain, keyed by ID. IDs without a destination are left out.
+func (s *GameService) GetLocalizedDestinations(destinationIDs []int, locales []string) (map[int]models.Destination, error) {
+	var destinations []models.Destination
+	for _, id := range destinationIDs {
+		dest, err := s.catalog.Get(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		destinations = append(destinations, *dest)
+	}
+
+	if err := s.destinations.LocalizeDestinations(destinations, locales); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]models.Destination, len(destinations))
+	for _, dest := range destinations {
+		byID[dest.ID] = dest
+	}
+	return byID, nil
+}
+
+// GetLocalizedDestination gets a destination by its ID, translated along the locale chain
+func (s *GameService) GetLocalizedDestination(destinationID int, locales []string) (*models.Destination, error) {
+	dest, err := s.catalog.Get(destinationID)
+	if err != nil {
+		return nil, err
+	}
+
+	localized := []models.Destination{*dest}
+	if err := s.destinations.LocalizeDestinations(localized, locales); err != nil {
+		return nil, err
+	}
+
+	return &localized[0], nil
 }
 
 // GetGameSummary gets a summary of a game
 func (s *GameService) GetGameSummary(gameID int) (*models.GameSummary, error) {
 	// Get the game
-	game, err := s.db.GetGame(gameID)
+	game, err := s.GetGame(gameID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get the user
-	user, err := s.db.GetUserByID(game.UserID)
+	user, err := s.users.GetUserByID(game.UserID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Use a photo of one of the game's destinations when there is one
+	imageURL := images.DefaultImageURL
+	image, err := s.games.GetGameImage(gameID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if image != nil {
+		imageURL = images.MediaURL(image.File)
+	}
+
 	// Create summary
 	summary := &models.GameSummary{
 		GameID:         game.ID,
 		Username:       user.Username,
-		ImageURL:       images.GetTravelImage(),
+		ImageURL:       imageURL,
 		TotalQuestions: game.TotalQuestions,
 		TotalAnswered:  game.TotalAnswered,
 		TotalCorrect:   game.TotalCorrect,
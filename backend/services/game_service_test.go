@@ -0,0 +1,450 @@
+package services_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services"
+	"github.com/shubhsherl/globetrotter/backend/services/catalog"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
+)
+
+func TestRepeatedAnswerGetsRecordedOutcome(t *testing.T) {
+	t.Setenv("DATASET_PATH", filepath.Join("..", "data", "data.json"))
+	database, err := db.Open(filepath.Join(t.TempDir(), "globetrotter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	data := services.NewDataService(database)
+
+	if _, err := data.CreateUser("repeater", ""); err != nil {
+		t.Fatal(err)
+	}
+	gameID, err := data.CreateGame("repeater", models.ScoringStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		question, err := data.NextQuestion(gameID, nil)
+		if errors.Is(err, services.ErrGameFinished) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Alternate between options so both fun facts and trivia are revealed
+		var options []int
+		for id := range question.OptionsDisplay {
+			options = append(options, id)
+		}
+		sort.Ints(options)
+		selected := options[question.QuestionID%len(options)]
+
+		first, err := data.SubmitAnswer(gameID, question.QuestionID, selected, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			_, err := data.SubmitAnswer(gameID, question.QuestionID, options[i%len(options)], nil, nil)
+			var answered *services.AlreadyAnsweredError
+			if !errors.As(err, &answered) {
+				t.Fatalf("repeated answer returned %v, want an AlreadyAnsweredError", err)
+			}
+			if !reflect.DeepEqual(answered.Answer, first) {
+				t.Errorf("repeated answer got %+v, want the recorded %+v", answered.Answer, first)
+			}
+		}
+	}
+}
+
+// located builds a destination at a latitude and longitude, with one clue,
+// fun fact and trivia entry
+func located(city string, lat, lon float64) models.Destination {
+	return models.Destination{
+		City:      city,
+		Country:   "Testland",
+		Latitude:  &lat,
+		Longitude: &lon,
+		Clues:     []string{"Clue of " + city},
+		FunFact:   []string{"Fun fact of " + city},
+		Trivia:    []string{"Trivia of " + city},
+	}
+}
+
+// testDestinations are enough destinations for a game, at known distances
+func testDestinations() []models.Destination {
+	return []models.Destination{
+		located("Paris", 48.8566, 2.3522),
+		located("Lyon", 45.7640, 4.8357),
+		located("Rome", 41.9028, 12.4964),
+		located("Tokyo", 35.6762, 139.6503),
+		located("Sydney", -33.8688, 151.2093),
+		located("Cairo", 30.0444, 31.2357),
+	}
+}
+
+// newGameService creates a game service over store, with a player whose ID is 1
+func newGameService(t *testing.T, store *memoryStore) *services.GameService {
+	t.Helper()
+	if err := store.SaveUser("test", models.User{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	return services.NewGameService(store, store, store, catalog.New(store))
+}
+
+// wrongOption returns an option of a question other than the correct one
+func wrongOption(question models.GameQuestionDetail) int {
+	for _, id := range question.OptionDestinationIDs {
+		if id != question.CorrectDestinationID {
+			return id
+		}
+	}
+	return 0
+}
+
+func TestGameServiceCreateGame(t *testing.T) {
+	tests := []struct {
+		name         string
+		destinations []models.Destination
+		scoring      string
+		wantErr      error
+		wantOptions  int
+	}{
+		{"standard by default", testDestinations(), "", nil, 4},
+		{"proximity", testDestinations(), models.ScoringProximity, nil, 4},
+		{"pin games have no options", testDestinations(), models.ScoringPin, nil, 0},
+		{"unknown scoring", testDestinations(), "darts", services.ErrInvalidScoring, 0},
+		{"too few destinations", testDestinations()[:4], "", services.ErrNotEnoughDestinations, 0},
+		{"too few located destinations for pins", append(testDestinations()[:4], models.Destination{City: "Atlantis", Clues: []string{"Sunk"}}), models.ScoringPin, services.ErrNotEnoughDestinations, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore(tt.destinations...)
+			games := newGameService(t, store)
+
+			gameID, err := games.CreateGame(1, tt.scoring)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			result, err := games.GetGameResult(gameID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Questions) != 5 {
+				t.Fatalf("got %d questions, want 5", len(result.Questions))
+			}
+			seen := make(map[int]bool)
+			for _, q := range result.Questions {
+				question := store.question(q.ID)
+				if seen[question.CorrectDestinationID] {
+					t.Errorf("destination %d is asked about twice", question.CorrectDestinationID)
+				}
+				seen[question.CorrectDestinationID] = true
+				if len(question.OptionDestinationIDs) != tt.wantOptions {
+					t.Errorf("question %d has %d options, want %d", q.ID, len(question.OptionDestinationIDs), tt.wantOptions)
+				}
+				if tt.wantOptions > 0 && wrongOption(question) == 0 {
+					t.Errorf("question %d has no wrong option", q.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestGameServiceSubmitAnswer(t *testing.T) {
+	tests := []struct {
+		name    string
+		scoring string
+		// answer picks the option and pin to answer question with
+		answer      func(question models.GameQuestionDetail, correct models.Destination) (int, *models.Pin)
+		wantErr     error
+		wantCorrect bool
+		// wantPoints returns the points expected for the distance of the answer
+		wantPoints func(distanceKm *float64) int
+	}{
+		{
+			name:    "standard correct",
+			scoring: models.ScoringStandard,
+			answer: func(q models.GameQuestionDetail, _ models.Destination) (int, *models.Pin) {
+				return q.CorrectDestinationID, nil
+			},
+			wantCorrect: true,
+			wantPoints:  func(*float64) int { return geo.MaxPoints },
+		},
+		{
+			name:    "standard wrong",
+			scoring: models.ScoringStandard,
+			answer: func(q models.GameQuestionDetail, _ models.Destination) (int, *models.Pin) {
+				return wrongOption(q), nil
+			},
+			wantPoints: func(*float64) int { return 0 },
+		},
+		{
+			name:    "proximity wrong",
+			scoring: models.ScoringProximity,
+			answer: func(q models.GameQuestionDetail, _ models.Destination) (int, *models.Pin) {
+				return wrongOption(q), nil
+			},
+			wantPoints: func(distanceKm *float64) int { return geo.ProximityPoints(*distanceKm) },
+		},
+		{
+			name:    "option not offered",
+			scoring: models.ScoringStandard,
+			answer: func(models.GameQuestionDetail, models.Destination) (int, *models.Pin) {
+				return 999, nil
+			},
+			wantErr: services.ErrInvalidAnswer,
+		},
+		{
+			name:    "pin on the destination",
+			scoring: models.ScoringPin,
+			answer: func(_ models.GameQuestionDetail, correct models.Destination) (int, *models.Pin) {
+				return 0, &models.Pin{Latitude: *correct.Latitude, Longitude: *correct.Longitude}
+			},
+			wantCorrect: true,
+			wantPoints:  func(*float64) int { return geo.MaxPoints },
+		},
+		{
+			name:    "pin within the full points radius",
+			scoring: models.ScoringPin,
+			answer: func(_ models.GameQuestionDetail, correct models.Destination) (int, *models.Pin) {
+				return 0, &models.Pin{Latitude: *correct.Latitude + 0.2, Longitude: *correct.Longitude}
+			},
+			wantCorrect: true,
+			wantPoints:  func(*float64) int { return geo.MaxPoints },
+		},
+		{
+			name:    "pin far away",
+			scoring: models.ScoringPin,
+			answer: func(_ models.GameQuestionDetail, correct models.Destination) (int, *models.Pin) {
+				return 0, &models.Pin{Latitude: -*correct.Latitude, Longitude: *correct.Longitude}
+			},
+			wantPoints: func(distanceKm *float64) int { return geo.ProximityPoints(*distanceKm) },
+		},
+		{
+			name:    "pin out of range",
+			scoring: models.ScoringPin,
+			answer: func(models.GameQuestionDetail, models.Destination) (int, *models.Pin) {
+				return 0, &models.Pin{Latitude: 91}
+			},
+			wantErr: services.ErrInvalidAnswer,
+		},
+		{
+			name:    "pin game answered with an option",
+			scoring: models.ScoringPin,
+			answer: func(q models.GameQuestionDetail, _ models.Destination) (int, *models.Pin) {
+				return q.CorrectDestinationID, nil
+			},
+			wantErr: services.ErrInvalidAnswer,
+		},
+		{
+			name:    "option game answered with a pin",
+			scoring: models.ScoringStandard,
+			answer: func(q models.GameQuestionDetail, _ models.Destination) (int, *models.Pin) {
+				return q.CorrectDestinationID, &models.Pin{}
+			},
+			wantErr: services.ErrInvalidAnswer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore(testDestinations()...)
+			games := newGameService(t, store)
+			gameID, err := games.CreateGame(1, tt.scoring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next, err := games.GetNextQuestion(gameID, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			question := store.question(next.ID)
+			correct, err := games.GetDestinationByID(question.CorrectDestinationID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			selected, pin := tt.answer(question, *correct)
+			response, err := games.SubmitAnswer(gameID, question.ID, selected, pin, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if store.question(question.ID).IsAnswered != 0 {
+					t.Error("invalid answer was recorded")
+				}
+				return
+			}
+
+			if response.Correct != tt.wantCorrect {
+				t.Errorf("got correct %t, want %t", response.Correct, tt.wantCorrect)
+			}
+			if response.CorrectOptionID != question.CorrectDestinationID || response.CorrectCity != correct.City {
+				t.Errorf("got correct answer %d %q, want %d %q", response.CorrectOptionID, response.CorrectCity, question.CorrectDestinationID, correct.City)
+			}
+			if !response.Correct && response.DistanceKm == nil {
+				t.Error("wrong answer has no distance")
+			}
+			if want := tt.wantPoints(response.DistanceKm); response.Points != want {
+				t.Errorf("got %d points, want %d", response.Points, want)
+			}
+			if tt.wantCorrect && response.FunFact != "Fun fact of "+correct.City {
+				t.Errorf("got fun fact %q, want the correct destination's", response.FunFact)
+			}
+			if !tt.wantCorrect && response.Trivia != "Trivia of "+correct.City {
+				t.Errorf("got trivia %q, want the correct destination's", response.Trivia)
+			}
+
+			game, err := games.GetGame(gameID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if game.TotalAnswered != 1 || game.Score != response.Points {
+				t.Errorf("game has %d answers and %d points, want 1 and %d", game.TotalAnswered, game.Score, response.Points)
+			}
+
+			// The question is answered once; repeating it returns the recorded answer
+			_, err = games.SubmitAnswer(gameID, question.ID, selected, pin, nil)
+			var answered *services.AlreadyAnsweredError
+			if !errors.As(err, &answered) || !reflect.DeepEqual(answered.Answer, response) {
+				t.Errorf("answering again got %v, want the recorded answer", err)
+			}
+		})
+	}
+}
+
+// TestGameServiceCompactedGame checks that only finished games are compacted,
+// and that answering a compacted game's question says so
+func TestGameServiceCompactedGame(t *testing.T) {
+	store := newMemoryStore(testDestinations()...)
+	games := newGameService(t, store)
+	finishedID, err := games.CreateGame(1, models.ScoringStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unfinishedID, err := games.CreateGame(1, models.ScoringStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lastID int
+	for {
+		next, err := games.GetNextQuestion(finishedID, nil)
+		if errors.Is(err, services.ErrGameFinished) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lastID = next.ID
+		if _, err := games.SubmitAnswer(finishedID, next.ID, store.question(next.ID).CorrectDestinationID, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	next, err := games.GetNextQuestion(unfinishedID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := games.SubmitAnswer(unfinishedID, next.ID, store.question(next.ID).CorrectDestinationID, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := store.CompactGames(time.Now().Add(time.Minute), 10); err != nil || n != 1 {
+		t.Fatalf("compacted %d games, %v; want only the finished one", n, err)
+	}
+
+	_, err = games.SubmitAnswer(finishedID, lastID, store.question(lastID).CorrectDestinationID, nil, nil)
+	if !errors.Is(err, services.ErrGameCompacted) {
+		t.Errorf("answering a compacted game got %v, want ErrGameCompacted", err)
+	}
+	_, err = games.SubmitAnswer(finishedID, 999, 1, nil, nil)
+	if !errors.Is(err, services.ErrGameCompacted) {
+		t.Errorf("answering an unknown question of a compacted game got %v, want ErrGameCompacted", err)
+	}
+	_, err = games.SubmitAnswer(unfinishedID, 999, 1, nil, nil)
+	if !errors.Is(err, services.ErrQuestionNotFound) {
+		t.Errorf("answering an unknown question got %v, want ErrQuestionNotFound", err)
+	}
+
+	next, err = games.GetNextQuestion(unfinishedID, nil)
+	if err != nil {
+		t.Fatalf("the unfinished game can't be played on: %v", err)
+	}
+	if _, err := games.SubmitAnswer(unfinishedID, next.ID, store.question(next.ID).CorrectDestinationID, nil, nil); err != nil {
+		t.Errorf("answering the unfinished game: %v", err)
+	}
+}
+
+func TestGameServicePlay(t *testing.T) {
+	store := newMemoryStore(testDestinations()...)
+	games := newGameService(t, store)
+	gameID, err := games.CreateGame(1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for answered := 0; ; answered++ {
+		next, err := games.GetNextQuestion(gameID, nil)
+		if errors.Is(err, services.ErrGameFinished) {
+			if answered != 5 {
+				t.Fatalf("game finished after %d questions, want 5", answered)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.CorrectDestinationID != 0 {
+			t.Fatal("next question reveals the correct destination")
+		}
+
+		// Get every other question right
+		question := store.question(next.ID)
+		selected := question.CorrectDestinationID
+		if answered%2 == 1 {
+			selected = wrongOption(question)
+		}
+		if _, err := games.SubmitAnswer(gameID, question.ID, selected, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := games.GetGameResult(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalCorrect != 3 || result.TotalIncorrect != 2 || result.Score != 3*geo.MaxPoints {
+		t.Errorf("got %d right, %d wrong and %d points, want 3, 2 and %d", result.TotalCorrect, result.TotalIncorrect, result.Score, 3*geo.MaxPoints)
+	}
+
+	summary, err := games.GetGameSummary(gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Username != "alice" || summary.TotalAnswered != 5 || summary.TotalCorrect != 3 {
+		t.Errorf("got summary %+v", summary)
+	}
+
+	for _, missing := range []func() error{
+		func() error { _, err := games.GetNextQuestion(999, nil); return err },
+		func() error { _, err := games.GetGameResult(999); return err },
+		func() error { _, err := games.GetGameSummary(999); return err },
+	} {
+		if err := missing(); !errors.Is(err, services.ErrGameNotFound) {
+			t.Errorf("got %v for a missing game, want %v", err, services.ErrGameNotFound)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package geo provides great-circle distances, the distance-based scoring curve
+// and the regions countries belong to.
+package geo
+
+import "math"
+
+// EarthRadiusKm is the mean radius of the Earth
+const EarthRadiusKm = 6371.0
+
+// Scoring curve: answers within FullPointsRadiusKm earn MaxPoints, after which
+// points decay exponentially with distance, losing ~63% every DecayKm
+const (
+	MaxPoints          = 1000
+	FullPointsRadiusKm = 50.0
+	DecayKm            = 1000.0
+)
+
+// DistanceKm returns the great-circle distance between two points in kilometres
+// using the haversine formula
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := toRadians(lat1)
+	phi2 := toRadians(lat2)
+	dPhi := toRadians(lat2 - lat1)
+	dLambda := toRadians(lon2 - lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+
+	return EarthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// ProximityPoints scores an answer by how far it is from the correct location.
+// Lyon for Paris (~390 km) earns about 710 points, Rome (~1100 km) about 350,
+// and anything beyond ~7000 km rounds down to zero.
+func ProximityPoints(distanceKm float64) int {
+	if distanceKm <= FullPointsRadiusKm {
+		return MaxPoints
+	}
+
+	return int(math.Round(MaxPoints * math.Exp(-(distanceKm-FullPointsRadiusKm)/DecayKm)))
+}
+
+// ValidCoordinates reports whether a latitude and longitude are in range
+func ValidCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
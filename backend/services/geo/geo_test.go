@@ -0,0 +1,87 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"same place", 48.8566, 2.3522, 48.8566, 2.3522, 0},
+		{"Paris to London", 48.8566, 2.3522, 51.5074, -0.1278, 343.6},
+		{"Paris to Lyon", 48.8566, 2.3522, 45.7640, 4.8357, 391.5},
+		{"New York to Los Angeles", 40.7128, -74.0060, 34.0522, -118.2437, 3935.7},
+		{"Sydney to Tokyo", -33.8688, 151.2093, 35.6762, 139.6503, 7825.8},
+		{"antipodes", 0, 0, 0, 180, math.Pi * EarthRadiusKm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceKm(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.want) > 0.1 {
+				t.Errorf("got %.1f km, want %.1f", got, tt.want)
+			}
+			if back := DistanceKm(tt.lat2, tt.lon2, tt.lat1, tt.lon1); math.Abs(back-got) > 1e-9 {
+				t.Errorf("got %.1f km the other way, want %.1f", back, got)
+			}
+		})
+	}
+}
+
+func TestProximityPoints(t *testing.T) {
+	tests := []struct {
+		distanceKm float64
+		want       int
+	}{
+		{0, MaxPoints},
+		{FullPointsRadiusKm, MaxPoints},
+		{FullPointsRadiusKm + 0.5, 1000},
+		{FullPointsRadiusKm + 1, 999},
+		{391.5, 711},
+		{1000, 387},
+		{FullPointsRadiusKm + DecayKm, 368}, // One decay length loses 1/e
+		{FullPointsRadiusKm + 2*DecayKm, 135},
+		{7000, 1},
+		{7300, 1},
+		{7700, 0},
+		{20015, 0},
+	}
+	for _, tt := range tests {
+		if got := ProximityPoints(tt.distanceKm); got != tt.want {
+			t.Errorf("ProximityPoints(%v) = %d, want %d", tt.distanceKm, got, tt.want)
+		}
+	}
+
+	// Points never grow with distance
+	previous := MaxPoints
+	for km := 0.0; km <= 10000; km += 10 {
+		points := ProximityPoints(km)
+		if points > previous {
+			t.Fatalf("ProximityPoints(%v) = %d, more than %d nearer", km, points, previous)
+		}
+		previous = points
+	}
+}
+
+func TestValidCoordinates(t *testing.T) {
+	tests := []struct {
+		lat, lon float64
+		want     bool
+	}{
+		{0, 0, true},
+		{90, 180, true},
+		{-90, -180, true},
+		{90.1, 0, false},
+		{-90.1, 0, false},
+		{0, 180.1, false},
+		{0, -180.1, false},
+	}
+	for _, tt := range tests {
+		if got := ValidCoordinates(tt.lat, tt.lon); got != tt.want {
+			t.Errorf("ValidCoordinates(%v, %v) = %t, want %t", tt.lat, tt.lon, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package geo
+
+import "strings"
+
+// Regions destinations are grouped into
+const (
+	RegionAfrica       = "africa"
+	RegionAmericas     = "americas"
+	RegionAsia         = "asia"
+	RegionEurope       = "europe"
+	RegionMiddleEast   = "middle-east"
+	RegionOceania      = "oceania"
+	RegionUnclassified = ""
+)
+
+// Regions lists the regions Region can return, except RegionUnclassified
+var Regions = []string{RegionAfrica, RegionAmericas, RegionAsia, RegionEurope, RegionMiddleEast, RegionOceania}
+
+// countryRegions maps lowercased country names, including the short forms
+// used in the dataset, to their region
+var countryRegions = map[string]string{
+	// Africa
+	"algeria": RegionAfrica, "angola": RegionAfrica, "botswana": RegionAfrica, "cameroon": RegionAfrica,
+	"cape verde": RegionAfrica, "democratic republic of the congo": RegionAfrica, "egypt": RegionAfrica,
+	"ethiopia": RegionAfrica, "ghana": RegionAfrica, "kenya": RegionAfrica, "madagascar": RegionAfrica,
+	"malawi": RegionAfrica, "mali": RegionAfrica, "mauritius": RegionAfrica, "morocco": RegionAfrica,
+	"mozambique": RegionAfrica, "namibia": RegionAfrica, "nigeria": RegionAfrica, "rwanda": RegionAfrica,
+	"senegal": RegionAfrica, "seychelles": RegionAfrica, "south africa": RegionAfrica, "sudan": RegionAfrica,
+	"tanzania": RegionAfrica, "tunisia": RegionAfrica, "uganda": RegionAfrica, "zambia": RegionAfrica,
+	"zimbabwe": RegionAfrica,
+
+	// Americas
+	"argentina": RegionAmericas, "bahamas": RegionAmericas, "belize": RegionAmericas, "bolivia": RegionAmericas,
+	"brazil": RegionAmericas, "canada": RegionAmericas, "chile": RegionAmericas, "colombia": RegionAmericas,
+	"costa rica": RegionAmericas, "cuba": RegionAmericas, "dominican republic": RegionAmericas,
+	"ecuador": RegionAmericas, "el salvador": RegionAmericas, "guatemala": RegionAmericas, "haiti": RegionAmericas,
+	"honduras": RegionAmericas, "jamaica": RegionAmericas, "mexico": RegionAmericas, "nicaragua": RegionAmericas,
+	"panama": RegionAmericas, "paraguay": RegionAmericas, "peru": RegionAmericas, "puerto rico": RegionAmericas,
+	"united states": RegionAmericas, "usa": RegionAmericas, "uruguay": RegionAmericas, "venezuela": RegionAmericas,
+
+	// Asia
+	"bangladesh": RegionAsia, "bhutan": RegionAsia, "brunei": RegionAsia, "cambodia": RegionAsia,
+	"china": RegionAsia, "india": RegionAsia, "indonesia": RegionAsia, "japan": RegionAsia,
+	"kazakhstan": RegionAsia, "kyrgyzstan": RegionAsia, "laos": RegionAsia, "malaysia": RegionAsia,
+	"maldives": RegionAsia, "mongolia": RegionAsia, "myanmar": RegionAsia, "nepal": RegionAsia,
+	"north korea": RegionAsia, "pakistan": RegionAsia, "philippines": RegionAsia, "singapore": RegionAsia,
+	"south korea": RegionAsia, "sri lanka": RegionAsia, "taiwan": RegionAsia, "tajikistan": RegionAsia,
+	"thailand": RegionAsia, "turkmenistan": RegionAsia, "uzbekistan": RegionAsia, "vietnam": RegionAsia,
+
+	// Europe
+	"albania": RegionEurope, "austria": RegionEurope, "belgium": RegionEurope, "bosnia and herzegovina": RegionEurope,
+	"bulgaria": RegionEurope, "croatia": RegionEurope, "czech republic": RegionEurope, "czechia": RegionEurope,
+	"denmark": RegionEurope, "estonia": RegionEurope, "finland": RegionEurope, "france": RegionEurope,
+	"germany": RegionEurope, "greece": RegionEurope, "hungary": RegionEurope, "iceland": RegionEurope,
+	"ireland": RegionEurope, "italy": RegionEurope, "latvia": RegionEurope, "lithuania": RegionEurope,
+	"luxembourg": RegionEurope, "malta": RegionEurope, "monaco": RegionEurope, "montenegro": RegionEurope,
+	"netherlands": RegionEurope, "north macedonia": RegionEurope, "norway": RegionEurope, "poland": RegionEurope,
+	"portugal": RegionEurope, "romania": RegionEurope, "russia": RegionEurope, "serbia": RegionEurope,
+	"slovakia": RegionEurope, "slovenia": RegionEurope, "spain": RegionEurope, "sweden": RegionEurope,
+	"switzerland": RegionEurope, "ukraine": RegionEurope, "united kingdom": RegionEurope, "uk": RegionEurope,
+
+	// Middle East
+	"bahrain": RegionMiddleEast, "iran": RegionMiddleEast, "iraq": RegionMiddleEast, "israel": RegionMiddleEast,
+	"jordan": RegionMiddleEast, "kuwait": RegionMiddleEast, "lebanon": RegionMiddleEast, "oman": RegionMiddleEast,
+	"qatar": RegionMiddleEast, "saudi arabia": RegionMiddleEast, "syria": RegionMiddleEast, "turkey": RegionMiddleEast,
+	"united arab emirates": RegionMiddleEast, "uae": RegionMiddleEast, "yemen": RegionMiddleEast,
+
+	// Oceania
+	"australia": RegionOceania, "fiji": RegionOceania, "french polynesia": RegionOceania,
+	"new caledonia": RegionOceania, "new zealand": RegionOceania, "papua new guinea": RegionOceania,
+	"samoa": RegionOceania, "tonga": RegionOceania, "vanuatu": RegionOceania,
+}
+
+// Region returns the region a country belongs to, or RegionUnclassified for
+// countries it doesn't know
+func Region(country string) string {
+	return countryRegions[strings.ToLower(strings.TrimSpace(country))]
+}
@@ -0,0 +1,268 @@
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+// maxImageBytes caps the size of a downloaded photo
+const maxImageBytes = 10 << 20
+
+// retryAfter is how long a destination without a photo is left alone before
+// the provider is asked again
+const retryAfter = 24 * time.Hour
+
+// imageExtensions are the file types looked for when picking up pre-seeded photos
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".webp"}
+
+// Catalog is the storage the fetcher reads destinations from and records photos in
+type Catalog interface {
+	ListDestinationsWithoutImage() ([]models.Destination, error)
+	SaveDestinationImage(image models.DestinationImage) error
+}
+
+// attribution is the sidecar stored next to each photo, so a media directory
+// can be copied to another deployment and picked up without network access
+type attribution struct {
+	Provider        string `json:"provider"`
+	Photographer    string `json:"photographer,omitempty"`
+	PhotographerURL string `json:"photographer_url,omitempty"`
+	SourceURL       string `json:"source_url,omitempty"`
+	Alt             string `json:"alt,omitempty"`
+}
+
+// Fetcher fills the local image cache in the background. For each destination
+// without a photo it first looks for a pre-seeded file in the media directory
+// and otherwise asks the provider, if there is one.
+type Fetcher struct {
+	catalog  Catalog
+	provider Provider // nil to only use pre-seeded files
+	mediaDir string
+	client   *http.Client
+	delay    time.Duration // Pause between provider requests
+
+	failed map[int]time.Time // Destinations the provider had no photo for
+}
+
+// NewFetcher creates a fetcher storing photos under mediaDir
+func NewFetcher(catalog Catalog, provider Provider, mediaDir string) *Fetcher {
+	return &Fetcher{
+		catalog:  catalog,
+		provider: provider,
+		mediaDir: mediaDir,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		delay:    time.Second,
+		failed:   make(map[int]time.Time),
+	}
+}
+
+// Run fetches missing photos now and then every interval until ctx is cancelled
+func (f *Fetcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := f.RunOnce(ctx); err != nil {
+			log.Printf("Image fetch failed: %v", err)
+		} else if count > 0 {
+			log.Printf("Cached %d destination images", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce caches a photo for every destination that lacks one and returns how many it stored
+func (f *Fetcher) RunOnce(ctx context.Context) (int, error) {
+	destinations, err := f.catalog.ListDestinationsWithoutImage()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(f.mediaDir, DestinationsDir), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create media directory: %v", err)
+	}
+
+	stored := 0
+	for _, dest := range destinations {
+		if ctx.Err() != nil {
+			return stored, ctx.Err()
+		}
+
+		image, err := f.preseeded(dest)
+		if err != nil {
+			log.Printf("Ignoring pre-seeded image for %s, %s: %v", dest.City, dest.Country, err)
+		}
+
+		if image == nil && f.provider != nil {
+			if failedAt, ok := f.failed[dest.ID]; ok && time.Since(failedAt) < retryAfter {
+				continue
+			}
+
+			image, err = f.fetch(ctx, dest)
+			if err != nil {
+				f.failed[dest.ID] = time.Now()
+				if !errors.Is(err, ErrNoImage) {
+					log.Printf("Failed to fetch image for %s, %s: %v", dest.City, dest.Country, err)
+				}
+			}
+
+			// Stay well within provider rate limits
+			select {
+			case <-ctx.Done():
+			case <-time.After(f.delay):
+			}
+		}
+
+		if image == nil {
+			continue
+		}
+
+		if err := f.catalog.SaveDestinationImage(*image); err != nil {
+			return stored, err
+		}
+		delete(f.failed, dest.ID)
+		stored++
+	}
+
+	return stored, nil
+}
+
+// preseeded returns the photo already on disk for a destination, if any
+func (f *Fetcher) preseeded(dest models.Destination) (*models.DestinationImage, error) {
+	slug := Slug(dest.City, dest.Country)
+
+	for _, ext := range imageExtensions {
+		file := filepath.ToSlash(filepath.Join(DestinationsDir, slug+ext))
+		if _, err := os.Stat(filepath.Join(f.mediaDir, file)); err != nil {
+			continue
+		}
+
+		// Attribution is required, photos without it are not used
+		data, err := os.ReadFile(filepath.Join(f.mediaDir, DestinationsDir, slug+".json"))
+		if err != nil {
+			return nil, fmt.Errorf("%s has no attribution file: %v", file, err)
+		}
+
+		var credit attribution
+		if err := json.Unmarshal(data, &credit); err != nil {
+			return nil, fmt.Errorf("invalid attribution for %s: %v", file, err)
+		}
+
+		return newImage(dest.ID, file, credit), nil
+	}
+
+	return nil, nil
+}
+
+// fetch asks the provider for a photo of a destination and stores it on disk
+func (f *Fetcher) fetch(ctx context.Context, dest models.Destination) (*models.DestinationImage, error) {
+	result, err := f.provider.Search(ctx, dest.City+" "+dest.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	slug := Slug(dest.City, dest.Country)
+	file, err := f.download(ctx, result.URL, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	credit := attribution{
+		Provider:        f.provider.Name(),
+		Photographer:    result.Photographer,
+		PhotographerURL: result.PhotographerURL,
+		SourceURL:       result.SourceURL,
+		Alt:             result.Alt,
+	}
+
+	data, err := json.MarshalIndent(credit, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(f.mediaDir, DestinationsDir, slug+".json"), data, 0644); err != nil {
+		return nil, err
+	}
+
+	return newImage(dest.ID, file, credit), nil
+}
+
+// download saves an image to the media directory and returns its relative path.
+// It writes to a temporary file first so a failed download never leaves a
+// truncated image behind.
+func (f *Fetcher) download(ctx context.Context, imageURL, slug string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("image download returned %s", resp.Status)
+	}
+
+	ext := ".jpg"
+	switch contentType := resp.Header.Get("Content-Type"); {
+	case strings.HasPrefix(contentType, "image/png"):
+		ext = ".png"
+	case strings.HasPrefix(contentType, "image/webp"):
+		ext = ".webp"
+	}
+
+	dir := filepath.Join(f.mediaDir, DestinationsDir)
+	tmp, err := os.CreateTemp(dir, slug+"-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(resp.Body, maxImageBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if written > maxImageBytes {
+		return "", fmt.Errorf("image is larger than %d bytes", maxImageBytes)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, slug+ext)); err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(filepath.Join(DestinationsDir, slug+ext)), nil
+}
+
+// newImage builds the stored image record for a cached file
+func newImage(destinationID int, file string, credit attribution) *models.DestinationImage {
+	return &models.DestinationImage{
+		DestinationID:   destinationID,
+		File:            file,
+		Provider:        credit.Provider,
+		Photographer:    credit.Photographer,
+		PhotographerURL: credit.PhotographerURL,
+		SourceURL:       credit.SourceURL,
+		Alt:             credit.Alt,
+	}
+}
@@ -0,0 +1,88 @@
+// Package images finds, caches and serves photos of destinations.
+package images
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultImageURL is shown wherever no destination photo is available
+const DefaultImageURL = "https://images.pexels.com/photos/2245436/pexels-photo-2245436.png?auto=compress&cs=tinysrgb&h=650&w=940"
+
+// MediaRoute is the URL prefix cached images are served under
+const MediaRoute = "/media"
+
+// DestinationsDir is the subdirectory of the media directory holding destination photos
+const DestinationsDir = "destinations"
+
+// ErrNoImage is returned by providers that found no photo for a query
+var ErrNoImage = errors.New("no image found")
+
+// Provider searches an image service for a photo
+type Provider interface {
+	// Name identifies the provider in stored attribution
+	Name() string
+	// Search returns the best photo for a query, or ErrNoImage
+	Search(ctx context.Context, query string) (*ImageResult, error)
+}
+
+// ImageResult represents the result of an image search
+type ImageResult struct {
+	URL             string `json:"url"`        // Direct link to the image file
+	SourceURL       string `json:"source_url"` // Page the photo is published on
+	Photographer    string `json:"photographer"`
+	PhotographerURL string `json:"photographer_url"`
+	Alt             string `json:"alt"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+}
+
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug builds the file name stem for a destination, e.g. "sao-paulo-brazil"
+func Slug(city, country string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), city+" "+country)
+	if err != nil {
+		folded = city + " " + country
+	}
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(folded), "-"), "-")
+}
+
+// MediaURL returns the URL a cached file is served from
+func MediaURL(file string) string {
+	return path.Join(MediaRoute, file)
+}
+
+// Handler serves the image files of a media directory and nothing else: the
+// attribution sidecars, downloads in progress and directory listings are not
+// found. Mount it with the MediaRoute prefix stripped.
+func Handler(mediaDir string) http.Handler {
+	files := http.FileServer(http.Dir(mediaDir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isImage(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+// isImage reports whether a file name has one of the image extensions
+func isImage(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, imageExt := range imageExtensions {
+		if ext == imageExt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package images
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	destinations := filepath.Join(dir, DestinationsDir)
+	if err := os.MkdirAll(destinations, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"paris-france.jpg", "paris-france.json", "rome-italy-123.tmp", "tokyo-japan.PNG"} {
+		if err := os.WriteFile(filepath.Join(destinations, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := http.StripPrefix(MediaRoute, Handler(dir))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/media/destinations/paris-france.jpg", http.StatusOK},
+		{"/media/destinations/tokyo-japan.PNG", http.StatusOK},
+		{"/media/destinations/paris-france.json", http.StatusNotFound},
+		{"/media/destinations/rome-italy-123.tmp", http.StatusNotFound},
+		{"/media/destinations/", http.StatusNotFound},
+		{"/media/destinations/missing.jpg", http.StatusNotFound},
+		{"/media/../images.go", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != tt.want {
+			t.Errorf("GET %s: got %d, want %d", tt.path, recorder.Code, tt.want)
+		}
+	}
+}
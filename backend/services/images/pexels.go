@@ -1,18 +1,14 @@
 package images
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"math/rand"
 	"net/http"
-	"os"
+	"net/url"
+	"time"
 )
 
-// PexelsAPIKey is the API key for Pexels
-// In production, this should be loaded from environment variables
-var PexelsAPIKey = os.Getenv("PEXELS_API_KEY")
-
 // PexelsResponse represents the response from Pexels API
 type PexelsResponse struct {
 	Page         int     `json:"page"`
@@ -49,66 +45,70 @@ type PhotoSource struct {
 	Tiny      string `json:"tiny"`
 }
 
-// ImageResult represents the result of an image search
-type ImageResult struct {
-	URL             string `json:"url"`
-	Photographer    string `json:"photographer"`
-	PhotographerURL string `json:"photographer_url"`
-	Alt             string `json:"alt"`
-	Width           int    `json:"width"`
-	Height          int    `json:"height"`
-}
+// pexelsSearchURL is the Pexels photo search endpoint
+const pexelsSearchURL = "https://api.pexels.com/v1/search"
 
-// GetTravelImage fetches a travel image for a destination from Pexels API
-func GetTravelImage() string {
-	defaultImage := "https://images.pexels.com/photos/2245436/pexels-photo-2245436.png?auto=compress&cs=tinysrgb&h=650&w=940"
+// PexelsProvider finds destination photos on Pexels
+type PexelsProvider struct {
+	apiKey string
+	client *http.Client
+}
 
-	// Ensure API key is set
-	if PexelsAPIKey == "" {
-		return defaultImage
+// NewPexelsProvider creates a Pexels provider for the given API key
+func NewPexelsProvider(apiKey string) *PexelsProvider {
+	return &PexelsProvider{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 15 * time.Second},
 	}
+}
+
+// Name returns the provider name stored with each image
+func (p *PexelsProvider) Name() string {
+	return "pexels"
+}
+
+// Search returns the best landscape photo for a query
+func (p *PexelsProvider) Search(ctx context.Context, query string) (*ImageResult, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("per_page", "1")
+	params.Set("orientation", "landscape")
 
-	// Create the request
-	randomPage := rand.Intn(5000)
-	url := fmt.Sprintf("https://api.pexels.com/v1/search?query=travel&per_page=1&page=%d", randomPage)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pexelsSearchURL+"?"+params.Encode(), nil)
 	if err != nil {
-		return defaultImage
+		return nil, err
 	}
 
 	// Add authorization header
-	req.Header.Add("Authorization", PexelsAPIKey)
+	req.Header.Add("Authorization", p.apiKey)
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := p.client.Do(req)
 	if err != nil {
-		return defaultImage
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return defaultImage
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return defaultImage
+		return nil, fmt.Errorf("pexels search returned %s", resp.Status)
 	}
 
-	// Parse the response
 	var pexelsResp PexelsResponse
-	if err := json.Unmarshal(body, &pexelsResp); err != nil {
-		return defaultImage
+	if err := json.NewDecoder(resp.Body).Decode(&pexelsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse pexels response: %v", err)
 	}
 
-	// Check if we got any photos
 	if len(pexelsResp.Photos) == 0 {
-		return defaultImage
+		return nil, ErrNoImage
 	}
 
-	// Get the first photo and return its large URL
-	return pexelsResp.Photos[0].Src.Large
+	photo := pexelsResp.Photos[0]
+	return &ImageResult{
+		URL:             photo.Src.Large,
+		SourceURL:       photo.URL,
+		Photographer:    photo.Photographer,
+		PhotographerURL: photo.PhotographerURL,
+		Alt:             photo.Alt,
+		Width:           photo.Width,
+		Height:          photo.Height,
+	}, nil
 }
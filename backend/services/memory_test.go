@@ -0,0 +1,310 @@
+package services_test
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// memoryStore keeps users, games and destinations in memory, for testing
+// services without a database. It implements the user and game repositories,
+// and the destination reads the game service makes; other destination
+// methods are left to the nil embedded interface and panic if called.
+type memoryStore struct {
+	storage.DestinationRepository
+
+	mu           sync.Mutex
+	users        []models.User
+	games        map[int]*models.Game
+	questions    []models.GameQuestionDetail // Ordered by ID
+	destinations []models.Destination
+	version      int64
+}
+
+// newMemoryStore creates a store holding destinations, numbering them and
+// their clues from 1
+func newMemoryStore(destinations ...models.Destination) *memoryStore {
+	s := &memoryStore{games: make(map[int]*models.Game)}
+	clueID := 0
+	for i, dest := range destinations {
+		dest.ID = i + 1
+		dest.ClueIDs = nil
+		for range dest.Clues {
+			clueID++
+			dest.ClueIDs = append(dest.ClueIDs, clueID)
+		}
+		s.destinations = append(s.destinations, dest)
+	}
+	return s
+}
+
+// question returns a copy of a stored question
+func (s *memoryStore) question(questionID int) models.GameQuestionDetail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.questions[questionID-1]
+}
+
+// ListDestinations implements catalog.Source
+func (s *memoryStore) ListDestinations(includeRetired bool) ([]models.Destination, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var destinations []models.Destination
+	for _, dest := range s.destinations {
+		if includeRetired || !dest.Retired {
+			destinations = append(destinations, dest)
+		}
+	}
+	return destinations, nil
+}
+
+// DestinationsVersion implements catalog.Source
+func (s *memoryStore) DestinationsVersion() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version, nil
+}
+
+// LocalizeDestinations leaves destinations in English; the store has no translations
+func (s *memoryStore) LocalizeDestinations(destinations []models.Destination, locales []string) error {
+	return nil
+}
+
+// TranslateClue finds no translation
+func (s *memoryStore) TranslateClue(clueID int, locales []string) (string, bool, error) {
+	return "", false, nil
+}
+
+func (s *memoryStore) GetClueByID(clueID int) (*models.Clue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, dest := range s.destinations {
+		for i, id := range dest.ClueIDs {
+			if id == clueID {
+				return &models.Clue{ID: id, DestinationID: dest.ID, Text: dest.Clues[i]}, nil
+			}
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetDestinationImage finds no photo
+func (s *memoryStore) GetDestinationImage(destinationID int) (*models.DestinationImage, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (s *memoryStore) GetUserByUsername(username string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func (s *memoryStore) GetUserByID(userID int) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if userID < 1 || userID > len(s.users) {
+		return nil, sql.ErrNoRows
+	}
+	user := s.users[userID-1]
+	return &user, nil
+}
+
+func (s *memoryStore) GetUserIDByUsername(username string) (int, error) {
+	user, err := s.GetUserByUsername(username)
+	return user.ID, err
+}
+
+// SaveUser adds a new user; existing users are left as they are
+func (s *memoryStore) SaveUser(actor string, user models.User) error {
+	if _, err := s.GetUserByUsername(user.Username); err == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user.ID = len(s.users) + 1
+	s.users = append(s.users, user)
+	return nil
+}
+
+func (s *memoryStore) SetUserLocale(actor, username, locale string) error {
+	user, err := s.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.ID-1].Locale = locale
+	return nil
+}
+
+func (s *memoryStore) CreateGame(userID int, totalQuestions int, scoring string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := len(s.games) + 1
+	s.games[id] = &models.Game{ID: id, UserID: userID, TotalQuestions: totalQuestions, Scoring: scoring, CreatedAt: time.Now()}
+	return id, nil
+}
+
+func (s *memoryStore) AddGameQuestion(gameID int, question string, optionDestinationIDs []int, correctDestinationID int, clueID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := len(s.questions) + 1
+	s.questions = append(s.questions, models.GameQuestionDetail{
+		ID:                   id,
+		GameID:               gameID,
+		Question:             question,
+		OptionDestinationIDs: append([]int(nil), optionDestinationIDs...),
+		CorrectDestinationID: correctDestinationID,
+		ClueID:               clueID,
+	})
+	return id, nil
+}
+
+func (s *memoryStore) GetGame(gameID int) (*models.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	game, ok := s.games[gameID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *game
+	return &copied, nil
+}
+
+func (s *memoryStore) GetNextQuestion(gameID int) (*models.GameQuestionDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, question := range s.questions {
+		if question.GameID == gameID && question.IsAnswered == 0 {
+			return &question, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *memoryStore) GetQuestionByID(gameID, questionID int) (*models.GameQuestionDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if questionID < 1 || questionID > len(s.questions) || s.questions[questionID-1].GameID != gameID {
+		return nil, sql.ErrNoRows
+	}
+	question := s.questions[questionID-1]
+	return &question, nil
+}
+
+// HasNextQuestion reports whether a question remains after the next one
+func (s *memoryStore) HasNextQuestion(gameID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unanswered := 0
+	for _, question := range s.questions {
+		if question.GameID == gameID && question.IsAnswered == 0 {
+			unanswered++
+		}
+	}
+	return unanswered > 1, nil
+}
+
+func (s *memoryStore) SubmitAnswer(gameID, questionID int, answer storage.Answer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if questionID < 1 || questionID > len(s.questions) || s.questions[questionID-1].GameID != gameID {
+		return sql.ErrNoRows
+	}
+	question := &s.questions[questionID-1]
+	if question.IsAnswered == 1 {
+		return storage.ErrAlreadyAnswered
+	}
+
+	question.IsAnswered = 1
+	question.SelectedDestinationID = answer.SelectedDestinationID
+	question.Points = answer.Points
+	question.DistanceKm = answer.DistanceKm
+	question.PinLatitude, question.PinLongitude = answer.PinLatitude, answer.PinLongitude
+	question.CorrectLatitude, question.CorrectLongitude = answer.CorrectLatitude, answer.CorrectLongitude
+
+	game := s.games[gameID]
+	game.TotalAnswered++
+	game.Score += answer.Points
+	if answer.Correct {
+		game.TotalCorrect++
+	} else {
+		game.TotalIncorrect++
+	}
+	return nil
+}
+
+func (s *memoryStore) GetGameResult(gameID int) (*models.GameResult, error) {
+	game, err := s.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	questions := []models.GameQuestionDetail{}
+	for _, question := range s.questions {
+		if question.GameID == gameID {
+			if question.IsAnswered == 0 {
+				question.CorrectDestinationID = 0
+			}
+			questions = append(questions, question)
+		}
+	}
+	return &models.GameResult{
+		GameID:         game.ID,
+		TotalQuestions: game.TotalQuestions,
+		TotalCorrect:   game.TotalCorrect,
+		TotalIncorrect: game.TotalIncorrect,
+		Scoring:        game.Scoring,
+		Score:          game.Score,
+		Questions:      questions,
+	}, nil
+}
+
+// GetGameImage finds no photo
+func (s *memoryStore) GetGameImage(gameID int) (*models.DestinationImage, error) {
+	return nil, sql.ErrNoRows
+}
+
+// DeleteAbandonedGames is not used by the services under test
+func (s *memoryStore) DeleteAbandonedGames(createdBefore time.Time) (int, error) {
+	panic("memoryStore: DeleteAbandonedGames is not implemented")
+}
+
+// CompactGames marks finished games compacted and detaches their questions,
+// which keep their IDs
+func (s *memoryStore) CompactGames(createdBefore time.Time, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	compacted := 0
+	for id := 1; id <= len(s.games) && compacted < limit; id++ {
+		game := s.games[id]
+		if game.CompactedAt != nil || game.TotalAnswered == 0 || game.TotalAnswered < game.TotalQuestions || !game.CreatedAt.Before(createdBefore) {
+			continue
+		}
+		now := time.Now()
+		game.CompactedAt = &now
+		for i := range s.questions {
+			if s.questions[i].GameID == id {
+				s.questions[i].GameID = 0
+			}
+		}
+		compacted++
+	}
+	return compacted, nil
+}
+
+// Stores must implement the repositories the game and user services use
+var (
+	_ storage.UserRepository        = (*memoryStore)(nil)
+	_ storage.GameRepository        = (*memoryStore)(nil)
+	_ storage.DestinationRepository = (*memoryStore)(nil)
+)
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// RetentionPolicy sets how long games keep their questions. A zero duration
+// keeps those games forever, so the zero policy deletes nothing.
+type RetentionPolicy struct {
+	AbandonedAfter time.Duration // Games nobody answered are deleted after this
+	CompactAfter   time.Duration // Answered games are compacted after this
+}
+
+// Enabled reports whether the policy removes anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.AbandonedAfter > 0 || p.CompactAfter > 0
+}
+
+// String describes the policy for the log
+func (p RetentionPolicy) String() string {
+	describe := func(after time.Duration) string {
+		if after <= 0 {
+			return "never"
+		}
+		return fmt.Sprintf("after %d days", int(after/(24*time.Hour)))
+	}
+	return fmt.Sprintf("unanswered games are deleted %s, answered games are compacted %s", describe(p.AbandonedAfter), describe(p.CompactAfter))
+}
+
+// compactBatchSize is how many games are compacted per transaction, so a
+// first run over a large table doesn't hold the database for long
+const compactBatchSize = 500
+
+// RetentionService removes old games so the game tables don't grow without bound
+type RetentionService struct {
+	games  storage.GameRepository
+	policy RetentionPolicy
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(games storage.GameRepository, policy RetentionPolicy) *RetentionService {
+	return &RetentionService{
+		games:  games,
+		policy: policy,
+	}
+}
+
+// Run applies the policy now and then every interval until ctx is cancelled
+func (s *RetentionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.Apply()
+		if err != nil {
+			log.Printf("Game retention failed: %v", err)
+		} else if result.DeletedGames > 0 || result.CompactedGames > 0 {
+			log.Printf("Game retention deleted %d abandoned games and compacted %d", result.DeletedGames, result.CompactedGames)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply deletes the games nobody answered within AbandonedAfter and compacts
+// the answered ones older than CompactAfter. Compacted games keep their
+// totals and score, and their answers keep counting in the difficulty
+// statistics, but their questions are gone.
+func (s *RetentionService) Apply() (*models.RetentionResult, error) {
+	result := &models.RetentionResult{}
+	now := time.Now()
+
+	if s.policy.AbandonedAfter > 0 {
+		deleted, err := s.games.DeleteAbandonedGames(now.Add(-s.policy.AbandonedAfter))
+		if err != nil {
+			return result, err
+		}
+		result.DeletedGames = deleted
+	}
+
+	if s.policy.CompactAfter > 0 {
+		for {
+			compacted, err := s.games.CompactGames(now.Add(-s.policy.CompactAfter), compactBatchSize)
+			result.CompactedGames += compacted
+			if err != nil {
+				return result, err
+			}
+			if compacted < compactBatchSize {
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
+)
+
+func TestScoreAnswer(t *testing.T) {
+	km := func(distance float64) *float64 { return &distance }
+
+	tests := []struct {
+		name       string
+		scoring    string
+		correct    bool
+		distanceKm *float64
+		want       int
+	}{
+		{"standard correct", models.ScoringStandard, true, nil, geo.MaxPoints},
+		{"standard wrong", models.ScoringStandard, false, km(100), 0},
+		{"proximity correct", models.ScoringProximity, true, nil, geo.MaxPoints},
+		{"proximity wrong within the full points radius", models.ScoringProximity, false, km(geo.FullPointsRadiusKm), geo.MaxPoints},
+		{"proximity wrong at 1000 km", models.ScoringProximity, false, km(1000), 387},
+		{"proximity wrong without coordinates", models.ScoringProximity, false, nil, 0},
+		{"pin at the destination", models.ScoringPin, true, km(0), geo.MaxPoints},
+		{"pin at 1000 km", models.ScoringPin, false, km(1000), 387},
+		{"pin at the antipodes", models.ScoringPin, false, km(20015), 0},
+		{"pin for a destination without coordinates", models.ScoringPin, false, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scoreAnswer(tt.scoring, tt.correct, tt.distanceKm); got != tt.want {
+				t.Errorf("got %d points, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceBetween(t *testing.T) {
+	at := func(lat, lon float64) *models.Destination {
+		return &models.Destination{Latitude: &lat, Longitude: &lon}
+	}
+	paris, lyon := at(48.8566, 2.3522), at(45.7640, 4.8357)
+
+	if got := distanceBetween(paris, lyon); got == nil || *got != 391 {
+		t.Errorf("Paris to Lyon: got %v, want 391 km", got)
+	}
+
+	// A pin on a destination without coordinates can't be measured
+	latitude := 48.8566
+	for _, missing := range []*models.Destination{
+		{},
+		{Latitude: &latitude},
+	} {
+		if got := distanceBetween(paris, missing); got != nil {
+			t.Errorf("pin for %+v: got %v km, want no distance", missing, *got)
+		}
+		if got := distanceBetween(missing, paris); got != nil {
+			t.Errorf("%+v to a pin: got %v km, want no distance", missing, *got)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shubhsherl/globetrotter/backend/dataset"
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/services/catalog"
+	"github.com/shubhsherl/globetrotter/backend/services/geo"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// MaxSubmittedEntries caps how many clues, fun facts or trivia entries one submission may propose per field
+const MaxSubmittedEntries = 10
+
+// SubmissionService handles content proposed by players and its review
+type SubmissionService struct {
+	submissions  storage.SubmissionRepository
+	users        storage.UserRepository
+	destinations storage.DestinationRepository
+	catalog      *catalog.Catalog
+}
+
+// NewSubmissionService creates a new submission service. Approvals
+// invalidate the catalog.
+func NewSubmissionService(submissions storage.SubmissionRepository, users storage.UserRepository, destinations storage.DestinationRepository, catalog *catalog.Catalog) *SubmissionService {
+	return &SubmissionService{
+		submissions:  submissions,
+		users:        users,
+		destinations: destinations,
+		catalog:      catalog,
+	}
+}
+
+// Submit stores a player's proposal for review
+func (s *SubmissionService) Submit(username string, sub models.Submission) (*models.Submission, error) {
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+
+	if err := s.validateSubmission(&sub); err != nil {
+		return nil, err
+	}
+	sub.UserID = user.ID
+
+	id, err := s.submissions.CreateSubmission(sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submissions.GetSubmission(id)
+}
+
+// ListUserSubmissions returns everything a player has submitted, with its review status
+func (s *SubmissionService) ListUserSubmissions(username string) ([]models.Submission, error) {
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+
+	return s.submissions.ListSubmissions("", user.ID)
+}
+
+// ListSubmissions returns the submissions with the given status, or all of
+// them when status is empty, flagging pending ones that duplicate existing content
+func (s *SubmissionService) ListSubmissions(status string) ([]models.Submission, error) {
+	submissions, err := s.submissions.ListSubmissions(status, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.flagDuplicates(submissions); err != nil {
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+// GetSubmission returns a single submission, flagged like in ListSubmissions
+func (s *SubmissionService) GetSubmission(submissionID int) (*models.Submission, error) {
+	sub, err := s.submissions.GetSubmission(submissionID)
+	if err != nil {
+		return nil, notFound(err, ErrSubmissionNotFound)
+	}
+
+	submissions := []models.Submission{*sub}
+	if err := s.flagDuplicates(submissions); err != nil {
+		return nil, err
+	}
+
+	return &submissions[0], nil
+}
+
+// ApproveSubmission merges a pending submission into the destinations. A new
+// destination is created unless one with the same city and country exists,
+// in which case the submitted entries it doesn't have yet are added to it.
+// sub may carry reviewer edits; they are validated like the original
+// submission and stored in its place.
+func (s *SubmissionService) ApproveSubmission(actor string, sub models.Submission) (*models.Destination, error) {
+	if err := s.validateSubmission(&sub); err != nil {
+		return nil, err
+	}
+
+	target, err := s.findTarget(sub)
+	if err != nil {
+		return nil, err
+	}
+
+	dest := models.Destination{
+		City:      sub.City,
+		Country:   sub.Country,
+		Latitude:  sub.Latitude,
+		Longitude: sub.Longitude,
+	}
+	if target != nil {
+		dest = *target
+		// Only fill in coordinates the destination doesn't have yet
+		if dest.Latitude == nil && sub.Latitude != nil {
+			dest.Latitude, dest.Longitude = sub.Latitude, sub.Longitude
+		}
+	}
+	dest.Clues = mergeTexts(dest.Clues, sub.Clues)
+	dest.FunFact = mergeTexts(dest.FunFact, sub.FunFact)
+	dest.Trivia = mergeTexts(dest.Trivia, sub.Trivia)
+
+	// The merged destination must be playable; reviewers can edit the submission until it is
+	if err := ValidateDestination(&dest); err != nil {
+		return nil, err
+	}
+
+	id, err := s.submissions.ApproveSubmission(actor, sub, dest)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	s.catalog.Invalidate()
+
+	return s.destinations.GetDestinationByID(id)
+}
+
+// RejectSubmission rejects a pending submission, recording the reviewer's note for the submitter
+func (s *SubmissionService) RejectSubmission(actor string, submissionID int, note string) (*models.Submission, error) {
+	if err := s.submissions.RejectSubmission(actor, submissionID, strings.TrimSpace(note)); err != nil {
+		return nil, reviewError(err)
+	}
+
+	return s.submissions.GetSubmission(submissionID)
+}
+
+// reviewError translates the storage errors of approving or rejecting a submission
+func reviewError(err error) error {
+	err = translate(err, storage.ErrSubmissionReviewed, ErrSubmissionReviewed)
+	err = translate(err, storage.ErrDuplicateDestination, ErrDuplicateDestination)
+	return notFound(err, ErrSubmissionNotFound)
+}
+
+// validateSubmission trims the submission's fields in place and checks it is
+// a well-formed proposal. Proposals for an existing destination take its name.
+func (s *SubmissionService) validateSubmission(sub *models.Submission) error {
+	var problems []string
+
+	sub.City = strings.TrimSpace(sub.City)
+	sub.Country = strings.TrimSpace(sub.Country)
+
+	switch sub.Kind {
+	case models.SubmissionDestination:
+		sub.DestinationID = nil
+		if sub.City == "" {
+			problems = append(problems, "city is required")
+		}
+		if sub.Country == "" {
+			problems = append(problems, "country is required")
+		}
+		if len(sub.Clues) == 0 {
+			problems = append(problems, "clues needs at least 1 entry")
+		}
+	case models.SubmissionClues:
+		if sub.DestinationID == nil {
+			problems = append(problems, "destination_id is required")
+			break
+		}
+
+		dest, err := s.destinations.GetDestinationByID(*sub.DestinationID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && dest.Retired) {
+			problems = append(problems, fmt.Sprintf("destination %d does not exist", *sub.DestinationID))
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		sub.City, sub.Country = dest.City, dest.Country
+		sub.Latitude, sub.Longitude = nil, nil
+		if len(sub.Clues)+len(sub.FunFact)+len(sub.Trivia) == 0 {
+			problems = append(problems, "at least one clue, fun fact or trivia entry is required")
+		}
+	default:
+		return &ValidationError{Subject: "submission", Problems: []string{fmt.Sprintf("kind must be %q or %q", models.SubmissionDestination, models.SubmissionClues)}}
+	}
+
+	if (sub.Latitude == nil) != (sub.Longitude == nil) {
+		problems = append(problems, "latitude and longitude must be given together")
+	} else if sub.Latitude != nil && !geo.ValidCoordinates(*sub.Latitude, *sub.Longitude) {
+		problems = append(problems, "latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+
+	problems = append(problems, validateEntries("clues", sub.Clues, dataset.DefaultLintOptions.MaxClueLength)...)
+	problems = append(problems, validateEntries("fun_fact", sub.FunFact, dataset.DefaultLintOptions.MaxFactLength)...)
+	problems = append(problems, validateEntries("trivia", sub.Trivia, dataset.DefaultLintOptions.MaxFactLength)...)
+	for i, clue := range sub.Clues {
+		if dataset.ClueRevealsAnswer(clue, sub.City, sub.Country) {
+			problems = append(problems, fmt.Sprintf("clues[%d] names the city or country", i))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Subject: "submission", Problems: problems}
+	}
+
+	return nil
+}
+
+// findTarget returns the existing destination a submission adds to, or nil
+// if it proposes a new one
+func (s *SubmissionService) findTarget(sub models.Submission) (*models.Destination, error) {
+	if sub.Kind == models.SubmissionClues {
+		return s.destinations.GetDestinationByID(*sub.DestinationID)
+	}
+
+	destinations, err := s.destinations.ListDestinations(true)
+	if err != nil {
+		return nil, err
+	}
+
+	key := dataset.Key(sub.City, sub.Country)
+	for i := range destinations {
+		if dataset.Key(destinations[i].City, destinations[i].Country) == key {
+			return &destinations[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// flagDuplicates marks pending submissions whose destination already exists,
+// that overlap with other pending submissions, or that repeat existing clues
+func (s *SubmissionService) flagDuplicates(submissions []models.Submission) error {
+	destinations, err := s.destinations.ListDestinations(true)
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[string]*models.Destination, len(destinations))
+	for i := range destinations {
+		byKey[dataset.Key(destinations[i].City, destinations[i].Country)] = &destinations[i]
+	}
+
+	// Compare against the whole queue, not just the submissions being flagged
+	pending, err := s.submissions.ListSubmissions(models.SubmissionPending, 0)
+	if err != nil {
+		return err
+	}
+	pendingByKey := make(map[string][]int)
+	for _, sub := range pending {
+		key := dataset.Key(sub.City, sub.Country)
+		pendingByKey[key] = append(pendingByKey[key], sub.ID)
+	}
+
+	for i := range submissions {
+		sub := &submissions[i]
+		if sub.Status != models.SubmissionPending {
+			continue
+		}
+
+		key := dataset.Key(sub.City, sub.Country)
+		if dest, ok := byKey[key]; ok {
+			if sub.Kind == models.SubmissionDestination {
+				id := dest.ID
+				sub.DuplicateOf = &id
+			}
+			for _, clue := range sub.Clues {
+				if containsFold(dest.Clues, clue) {
+					sub.DuplicateClues = append(sub.DuplicateClues, clue)
+				}
+			}
+		}
+
+		for _, id := range pendingByKey[key] {
+			if id != sub.ID {
+				sub.PendingDuplicates = append(sub.PendingDuplicates, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateEntries trims each entry and checks it is non-empty and not too long
+func validateEntries(field string, texts []string, maxLength int) []string {
+	var problems []string
+
+	if len(texts) > MaxSubmittedEntries {
+		problems = append(problems, fmt.Sprintf("%s can have at most %d entries", field, MaxSubmittedEntries))
+	}
+
+	for i := range texts {
+		texts[i] = strings.TrimSpace(texts[i])
+		if texts[i] == "" {
+			problems = append(problems, fmt.Sprintf("%s[%d] is empty", field, i))
+		} else if len([]rune(texts[i])) > maxLength {
+			problems = append(problems, fmt.Sprintf("%s[%d] is longer than %d characters", field, i, maxLength))
+		}
+	}
+
+	return problems
+}
+
+// mergeTexts appends the entries of added that existing doesn't already have
+func mergeTexts(existing, added []string) []string {
+	merged := append([]string{}, existing...)
+	for _, text := range added {
+		if !containsFold(merged, text) {
+			merged = append(merged, text)
+		}
+	}
+	return merged
+}
+
+// containsFold reports whether list has text, ignoring case and surrounding space
+func containsFold(list []string, text string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(text)) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,43 +1,76 @@
 package services
 
 import (
-	"errors"
-
-	"github.com/shubhsherl/globetrotter/backend/db"
+	"github.com/shubhsherl/globetrotter/backend/i18n"
 	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
 )
 
 // UserService handles user-related operations
 type UserService struct {
-	db *db.Database
+	users storage.UserRepository
 }
 
 // NewUserService creates a new user service
-func NewUserService(database *db.Database) *UserService {
+func NewUserService(users storage.UserRepository) *UserService {
 	return &UserService{
-		db: database,
+		users: users,
 	}
 }
 
-// CreateUser creates a new user
-func (s *UserService) CreateUser(username string) (models.User, error) {
-	existingUser, err := s.db.GetUserByUsername(username)
+// CreateUser creates a new user with an optional preferred language
+func (s *UserService) CreateUser(username, locale string) (models.User, error) {
+	normalized, err := normalizeLocale(locale)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	existingUser, err := s.users.GetUserByUsername(username)
 	if err == nil && existingUser.Username != "" {
-		return existingUser, errors.New("username already exists")
+		return existingUser, ErrUsernameTaken
 	}
 
 	// Implementation depends on your database structure
 	user := models.User{
 		Username: username,
+		Locale:   normalized,
 	}
 
-	err = s.db.SaveUser(user)
+	err = s.users.SaveUser(models.UserActor(username), user)
 	return user, err
 }
 
 // GetUser retrieves a user by username
 func (s *UserService) GetUser(username string) (models.User, error) {
-	return s.db.GetUserByUsername(username)
+	user, err := s.users.GetUserByUsername(username)
+	return user, notFound(err, ErrUserNotFound)
+}
+
+// SetLocale changes a user's preferred language; an empty locale clears it
+func (s *UserService) SetLocale(username, locale string) (models.User, error) {
+	normalized, err := normalizeLocale(locale)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := s.users.SetUserLocale(models.UserActor(username), username, normalized); err != nil {
+		return models.User{}, notFound(err, ErrUserNotFound)
+	}
+
+	return s.GetUser(username)
+}
+
+// normalizeLocale canonicalizes a language tag, allowing an empty one
+func normalizeLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+
+	normalized := i18n.Normalize(locale)
+	if normalized == "" {
+		return "", ErrInvalidLocale
+	}
+	return normalized, nil
 }
 
 // Add other user-related methods as needed
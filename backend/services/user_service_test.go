@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shubhsherl/globetrotter/backend/services"
+)
+
+func TestUserService(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func(users *services.UserService) (string, error)
+		want    string // Locale of the user returned
+		wantErr error
+	}{
+		{"create", func(users *services.UserService) (string, error) {
+			user, err := users.CreateUser("bob", "")
+			return user.Locale, err
+		}, "", nil},
+		{"create with a locale", func(users *services.UserService) (string, error) {
+			user, err := users.CreateUser("bob", "pt-br")
+			return user.Locale, err
+		}, "pt-BR", nil},
+		{"create with an invalid locale", func(users *services.UserService) (string, error) {
+			user, err := users.CreateUser("bob", "not a locale")
+			return user.Locale, err
+		}, "", services.ErrInvalidLocale},
+		{"create taken", func(users *services.UserService) (string, error) {
+			user, err := users.CreateUser("alice", "fr")
+			return user.Locale, err
+		}, "es", services.ErrUsernameTaken},
+		{"get", func(users *services.UserService) (string, error) {
+			user, err := users.GetUser("alice")
+			return user.Locale, err
+		}, "es", nil},
+		{"get missing", func(users *services.UserService) (string, error) {
+			user, err := users.GetUser("nobody")
+			return user.Locale, err
+		}, "", services.ErrUserNotFound},
+		{"set locale", func(users *services.UserService) (string, error) {
+			user, err := users.SetLocale("alice", "FR")
+			return user.Locale, err
+		}, "fr", nil},
+		{"clear locale", func(users *services.UserService) (string, error) {
+			user, err := users.SetLocale("alice", "")
+			return user.Locale, err
+		}, "", nil},
+		{"set invalid locale", func(users *services.UserService) (string, error) {
+			user, err := users.SetLocale("alice", "not a locale")
+			return user.Locale, err
+		}, "", services.ErrInvalidLocale},
+		{"set locale of missing user", func(users *services.UserService) (string, error) {
+			user, err := users.SetLocale("nobody", "fr")
+			return user.Locale, err
+		}, "", services.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := services.NewUserService(newMemoryStore())
+			if _, err := users.CreateUser("alice", "es"); err != nil {
+				t.Fatal(err)
+			}
+
+			locale, err := tt.run(users)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if locale != tt.want {
+				t.Errorf("got locale %q, want %q", locale, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package storage defines the repositories the services read and write game
+// data through, so game logic doesn't depend on a particular database.
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+)
+
+var (
+	// ErrDuplicateDestination is returned when another destination already uses the same city and country
+	ErrDuplicateDestination = errors.New("destination already exists")
+
+	// ErrSubmissionReviewed is returned when reviewing a submission that is no longer pending
+	ErrSubmissionReviewed = errors.New("submission has already been reviewed")
+
+	// ErrAlreadyAnswered is returned when answering a question that already has an answer
+	ErrAlreadyAnswered = errors.New("question already answered")
+)
+
+// Answer is a scored answer to a game question
+type Answer struct {
+	SelectedDestinationID int      // Chosen option, 0 for pin answers
+	PinLatitude           *float64 // Dropped pin, only for pin answers
+	PinLongitude          *float64
+	CorrectLatitude       *float64 // Location of the correct destination, when known
+	CorrectLongitude      *float64
+	Correct               bool
+	Points                int
+	DistanceKm            *float64 // Distance from the answer to the correct destination
+}
+
+// ImportResult counts what happened to destinations removed by an import
+type ImportResult struct {
+	Deleted int
+	Retired int
+}
+
+// TranslateFunc is called by ImportDestinations with the active destinations
+// as the import leaves them, before it commits, and returns translations to
+// store in the same transaction. Returning an error rolls the import back.
+type TranslateFunc func(destinations []models.Destination) (names []models.DestinationTranslation, clues, facts []models.TextTranslation, err error)
+
+// AuditFilter selects audit log entries. Zero fields don't filter.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   int
+	Since      time.Time // Entries created at or after
+	Until      time.Time // Entries created before
+	BeforeID   int       // Entries older than this one, to page through the log
+	Limit      int
+}
+
+// DestinationRepository stores destinations with their clues, facts,
+// translations and photos. Lookups of missing rows return sql.ErrNoRows.
+// DestinationsVersion changes whenever a destination, clue or fact does, so
+// caches can tell when to reload. Changes are recorded in the audit log
+// under the given actor, in the same transaction as the change.
+type DestinationRepository interface {
+	GetAllDestinations() ([]models.Destination, error)
+	ListDestinations(includeRetired bool) ([]models.Destination, error)
+	DestinationsVersion() (int64, error)
+	GetDestinationByID(destinationID int) (*models.Destination, error)
+	CreateDestination(actor string, dest models.Destination) (int, error)
+	UpdateDestination(actor string, dest models.Destination) error
+	DeleteDestination(actor string, destinationID int) (bool, error)
+	ImportDestinations(actor string, added, updated []models.Destination, removedIDs []int, translate TranslateFunc) (ImportResult, error)
+
+	ListClues(destinationID int, includeRetired bool) ([]models.Clue, error)
+	GetClueByID(clueID int) (*models.Clue, error)
+	UpdateClue(actor string, clue models.Clue) error
+
+	LocalizeDestinations(destinations []models.Destination, locales []string) error
+	TranslateClue(clueID int, locales []string) (string, bool, error)
+	ListTranslations(locale string) ([]models.DestinationTranslation, map[int]string, map[int]string, error)
+	SaveTranslations(names []models.DestinationTranslation, clues, facts []models.TextTranslation) error
+
+	GetDestinationImage(destinationID int) (*models.DestinationImage, error)
+	ListDestinationsWithoutImage() ([]models.Destination, error)
+	SaveDestinationImage(image models.DestinationImage) error
+}
+
+// UserRepository stores players. Changes are recorded in the audit log under
+// the given actor.
+type UserRepository interface {
+	GetUserByUsername(username string) (models.User, error)
+	GetUserByID(userID int) (*models.User, error)
+	GetUserIDByUsername(username string) (int, error)
+	SaveUser(actor string, user models.User) error
+	SetUserLocale(actor, username, locale string) error
+}
+
+// GameRepository stores games, their questions and answers. SubmitAnswer
+// records an answer and updates the game's totals atomically, and only for
+// the first answer to a question. Old games can be deleted if they were
+// never answered, or compacted: their questions are removed but their totals,
+// score and answer statistics are kept.
+type GameRepository interface {
+	CreateGame(userID int, totalQuestions int, scoring string) (int, error)
+	AddGameQuestion(gameID int, question string, optionDestinationIDs []int, correctDestinationID int, clueID int) (int, error)
+	GetGame(gameID int) (*models.Game, error)
+	GetNextQuestion(gameID int) (*models.GameQuestionDetail, error)
+	GetQuestionByID(gameID, questionID int) (*models.GameQuestionDetail, error)
+	HasNextQuestion(gameID int) (bool, error)
+	SubmitAnswer(gameID, questionID int, answer Answer) error
+	GetGameResult(gameID int) (*models.GameResult, error)
+	GetGameImage(gameID int) (*models.DestinationImage, error)
+
+	DeleteAbandonedGames(createdBefore time.Time) (int, error)
+	CompactGames(createdBefore time.Time, limit int) (int, error)
+}
+
+// SubmissionRepository stores content proposed by players and its review.
+// Reviews are recorded in the audit log under the given actor.
+type SubmissionRepository interface {
+	CreateSubmission(sub models.Submission) (int, error)
+	GetSubmission(submissionID int) (*models.Submission, error)
+	ListSubmissions(status string, userID int) ([]models.Submission, error)
+	ApproveSubmission(actor string, sub models.Submission, dest models.Destination) (int, error)
+	RejectSubmission(actor string, submissionID int, note string) error
+}
+
+// StatsRepository aggregates and stores answer statistics
+type StatsRepository interface {
+	RefreshDifficultyStats() error
+	ListDestinationStats() ([]models.DifficultyStats, error)
+	ListClueStats() ([]models.DifficultyStats, error)
+	DifficultyStatsUpdatedAt() (*time.Time, error)
+}
+
+// AuditRepository reads the audit log, newest entries first. Entries are
+// only written by the repositories whose changes they record, and are never
+// changed or removed.
+type AuditRepository interface {
+	ListAuditEntries(filter AuditFilter) ([]models.AuditEntry, error)
+}
+
+// Store is a complete storage backend
+type Store interface {
+	DestinationRepository
+	UserRepository
+	GameRepository
+	SubmissionRepository
+	StatsRepository
+	AuditRepository
+	Close() error
+}
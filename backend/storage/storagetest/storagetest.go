@@ -0,0 +1,1185 @@
+// Package storagetest checks that a storage backend behaves the way the
+// services expect. Every storage.Store implementation must pass it; the
+// backends' tests in package db call Run against each database.
+package storagetest
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shubhsherl/globetrotter/backend/models"
+	"github.com/shubhsherl/globetrotter/backend/storage"
+)
+
+// checks are the behaviors a store must have; each is run against a store
+// of its own and creates the users and destinations it needs
+var checks = []struct {
+	name string
+	run  func(c *checker)
+}{
+	{"users", checkUsers},
+	{"destinations", checkDestinations},
+	{"clues", checkClues},
+	{"destinations version", checkDestinationsVersion},
+	{"delete", checkDelete},
+	{"import", checkImport},
+	{"import translations", checkImportTranslations},
+	{"translations", checkTranslations},
+	{"images", checkImages},
+	{"games", checkGames},
+	{"concurrent answers", checkConcurrentAnswers},
+	{"submissions", checkSubmissions},
+	{"stats", checkStats},
+	{"retention", checkRetention},
+	{"audit", checkAudit},
+}
+
+// Run exercises a backend with a subtest per check, each against a new store
+// returned by open, so a failing check leaves the others unaffected and a
+// single check can be run with -run. The store may already hold data, such
+// as a seeded dataset: the checks create uniquely named users and
+// destinations of their own.
+func Run(t *testing.T, open func(t *testing.T) storage.Store) {
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			check.run(&checker{
+				T:      t,
+				store:  open(t),
+				suffix: fmt.Sprintf("%s-%d", check.name, time.Now().UnixNano()),
+			})
+		})
+	}
+}
+
+// checker is the state of a single check
+type checker struct {
+	*testing.T
+	store  storage.Store
+	suffix string
+}
+
+// actor is the audit log actor of the check's changes
+func (c *checker) actor() string {
+	return "storagetest:" + c.suffix
+}
+
+// expectErr reports a failure unless err is target
+func (c *checker) expectErr(what string, err, target error) {
+	c.Helper()
+	if !errors.Is(err, target) {
+		c.Errorf("%s: got error %v, want %v", what, err, target)
+	}
+}
+
+// user creates a player named after the check
+func (c *checker) user(name string) models.User {
+	c.Helper()
+	username := name + "-" + c.suffix
+	if err := c.store.SaveUser(c.actor(), models.User{Username: username}); err != nil {
+		c.Fatalf("saving user %s: %v", username, err)
+	}
+	user, err := c.store.GetUserByUsername(username)
+	if err != nil {
+		c.Fatalf("reading user %s: %v", username, err)
+	}
+	return user
+}
+
+// newDestination returns a playable destination named after the check
+func (c *checker) newDestination(name string) models.Destination {
+	latitude, longitude := 48.85, 2.35
+	return models.Destination{
+		City:      name + " " + c.suffix,
+		Country:   "Storagetest",
+		Latitude:  &latitude,
+		Longitude: &longitude,
+		Clues:     []string{name + " clue one", name + " clue two", name + " clue three"},
+		FunFact:   []string{name + " fun fact"},
+		Trivia:    []string{name + " trivia"},
+	}
+}
+
+// destination creates a destination named after the check and reads it back
+func (c *checker) destination(name string) *models.Destination {
+	c.Helper()
+	id, err := c.store.CreateDestination(c.actor(), c.newDestination(name))
+	if err != nil {
+		c.Fatalf("creating destination %s: %v", name, err)
+	}
+	dest, err := c.store.GetDestinationByID(id)
+	if err != nil {
+		c.Fatalf("reading destination %s: %v", name, err)
+	}
+	return dest
+}
+
+// game creates a game with an unanswered question per destination, the answer listed first
+func (c *checker) game(userID int, scoring string, destinations ...*models.Destination) (int, []int) {
+	c.Helper()
+	gameID, err := c.store.CreateGame(userID, len(destinations), scoring)
+	if err != nil {
+		c.Fatalf("creating game: %v", err)
+	}
+
+	var questionIDs []int
+	for _, dest := range destinations {
+		options := []int{dest.ID}
+		for _, other := range destinations {
+			if other.ID != dest.ID {
+				options = append(options, other.ID)
+			}
+		}
+		questionID, err := c.store.AddGameQuestion(gameID, dest.Clues[0], options, dest.ID, dest.ClueIDs[0])
+		if err != nil {
+			c.Fatalf("adding question: %v", err)
+		}
+		questionIDs = append(questionIDs, questionID)
+	}
+
+	return gameID, questionIDs
+}
+
+func checkUsers(c *checker) {
+	username := "user-" + c.suffix
+
+	_, err := c.store.GetUserByUsername(username)
+	c.expectErr("GetUserByUsername before saving", err, sql.ErrNoRows)
+
+	if err := c.store.SaveUser(c.actor(), models.User{Username: username, Locale: "fr"}); err != nil {
+		c.Fatalf("SaveUser: %v", err)
+	}
+
+	user, err := c.store.GetUserByUsername(username)
+	if err != nil {
+		c.Fatalf("GetUserByUsername: %v", err)
+	}
+	if user.ID == 0 || user.Username != username || user.Locale != "fr" {
+		c.Errorf("GetUserByUsername returned %+v", user)
+	}
+	if user.CreatedAt == "" {
+		c.Errorf("new user has no created_at")
+	}
+
+	byID, err := c.store.GetUserByID(user.ID)
+	if err != nil {
+		c.Fatalf("GetUserByID: %v", err)
+	}
+	if byID.Username != username {
+		c.Errorf("GetUserByID returned %q, want %q", byID.Username, username)
+	}
+
+	id, err := c.store.GetUserIDByUsername(username)
+	if err != nil || id != user.ID {
+		c.Errorf("GetUserIDByUsername returned %d, %v; want %d", id, err, user.ID)
+	}
+
+	// Saving an existing user must not add a second one
+	if err := c.store.SaveUser(c.actor(), models.User{Username: username}); err != nil {
+		c.Errorf("SaveUser of an existing user: %v", err)
+	}
+
+	if err := c.store.SetUserLocale(c.actor(), username, "de"); err != nil {
+		c.Fatalf("SetUserLocale: %v", err)
+	}
+	if user, err = c.store.GetUserByUsername(username); err != nil || user.Locale != "de" {
+		c.Errorf("locale after SetUserLocale is %q, %v; want de", user.Locale, err)
+	}
+
+	c.expectErr("SetUserLocale of a missing user", c.store.SetUserLocale(c.actor(), "missing-"+c.suffix, "de"), sql.ErrNoRows)
+	_, err = c.store.GetUserByID(-1)
+	c.expectErr("GetUserByID of a missing user", err, sql.ErrNoRows)
+	_, err = c.store.GetUserIDByUsername("missing-" + c.suffix)
+	c.expectErr("GetUserIDByUsername of a missing user", err, sql.ErrNoRows)
+}
+
+func checkDestinations(c *checker) {
+	want := c.newDestination("dest")
+	id, err := c.store.CreateDestination(c.actor(), want)
+	if err != nil {
+		c.Fatalf("CreateDestination: %v", err)
+	}
+
+	dest, err := c.store.GetDestinationByID(id)
+	if err != nil {
+		c.Fatalf("GetDestinationByID: %v", err)
+	}
+	if dest.City != want.City || dest.Country != want.Country || dest.Retired {
+		c.Errorf("GetDestinationByID returned %s, %s (retired %v)", dest.City, dest.Country, dest.Retired)
+	}
+	if dest.Latitude == nil || dest.Longitude == nil || *dest.Latitude != *want.Latitude || *dest.Longitude != *want.Longitude {
+		c.Errorf("coordinates were not stored")
+	}
+	if !reflect.DeepEqual(dest.Clues, want.Clues) || !reflect.DeepEqual(dest.FunFact, want.FunFact) || !reflect.DeepEqual(dest.Trivia, want.Trivia) {
+		c.Errorf("content is %q / %q / %q, want %q / %q / %q", dest.Clues, dest.FunFact, dest.Trivia, want.Clues, want.FunFact, want.Trivia)
+	}
+	if len(dest.ClueIDs) != len(dest.Clues) || len(dest.FunFactIDs) != len(dest.FunFact) || len(dest.TriviaIDs) != len(dest.Trivia) {
+		c.Errorf("content IDs don't line up with the content")
+	}
+
+	// Names are unique regardless of case
+	duplicate := c.newDestination("dest")
+	duplicate.City = strings.ToUpper(dest.City)
+	duplicate.Country = strings.ToLower(dest.Country)
+	_, err = c.store.CreateDestination(c.actor(), duplicate)
+	c.expectErr("CreateDestination of a duplicate", err, storage.ErrDuplicateDestination)
+
+	other := c.destination("other")
+	renamed := *other
+	renamed.City = dest.City
+	c.expectErr("UpdateDestination to a duplicate name", c.store.UpdateDestination(c.actor(), renamed), storage.ErrDuplicateDestination)
+
+	// Unchanged clues keep their IDs and move; dropped ones are retired
+	updated := *dest
+	updated.Clues = []string{dest.Clues[1], "dest new clue " + c.suffix}
+	updated.Trivia = []string{}
+	if err := c.store.UpdateDestination(c.actor(), updated); err != nil {
+		c.Fatalf("UpdateDestination: %v", err)
+	}
+	got, err := c.store.GetDestinationByID(id)
+	if err != nil {
+		c.Fatalf("GetDestinationByID after update: %v", err)
+	}
+	if !reflect.DeepEqual(got.Clues, updated.Clues) || len(got.Trivia) != 0 {
+		c.Errorf("content after update is %q / %q, want %q / []", got.Clues, got.Trivia, updated.Clues)
+	}
+	if len(got.ClueIDs) == 2 && got.ClueIDs[0] != dest.ClueIDs[1] {
+		c.Errorf("kept clue changed ID from %d to %d", dest.ClueIDs[1], got.ClueIDs[0])
+	}
+
+	// Retired destinations are only listed on request
+	updated.Retired = true
+	if err := c.store.UpdateDestination(c.actor(), updated); err != nil {
+		c.Fatalf("UpdateDestination retiring: %v", err)
+	}
+	if active, err := c.store.ListDestinations(false); err != nil || containsDestination(active, id) {
+		c.Errorf("ListDestinations(false) includes a retired destination (%v)", err)
+	}
+	if all, err := c.store.ListDestinations(true); err != nil || !containsDestination(all, id) {
+		c.Errorf("ListDestinations(true) leaves out a retired destination (%v)", err)
+	}
+	if active, err := c.store.GetAllDestinations(); err != nil || containsDestination(active, id) {
+		c.Errorf("GetAllDestinations includes a retired destination (%v)", err)
+	}
+
+	missing := c.newDestination("missing")
+	missing.ID = -1
+	c.expectErr("UpdateDestination of a missing destination", c.store.UpdateDestination(c.actor(), missing), sql.ErrNoRows)
+	_, err = c.store.GetDestinationByID(-1)
+	c.expectErr("GetDestinationByID of a missing destination", err, sql.ErrNoRows)
+}
+
+func checkClues(c *checker) {
+	dest := c.destination("clues")
+
+	clues, err := c.store.ListClues(dest.ID, false)
+	if err != nil {
+		c.Fatalf("ListClues: %v", err)
+	}
+	if len(clues) != len(dest.Clues) {
+		c.Fatalf("ListClues returned %d clues, want %d", len(clues), len(dest.Clues))
+	}
+	for i, clue := range clues {
+		if clue.ID != dest.ClueIDs[i] || clue.Text != dest.Clues[i] || clue.DestinationID != dest.ID || clue.Retired || len(clue.Tags) != 0 {
+			c.Errorf("ListClues[%d] is %+v", i, clue)
+		}
+	}
+
+	edited := clues[0]
+	edited.Text = "clues edited " + c.suffix
+	edited.Tags = []string{"landmark", "food"}
+	if err := c.store.UpdateClue(c.actor(), edited); err != nil {
+		c.Fatalf("UpdateClue: %v", err)
+	}
+	clue, err := c.store.GetClueByID(edited.ID)
+	if err != nil {
+		c.Fatalf("GetClueByID: %v", err)
+	}
+	if clue.Text != edited.Text || !reflect.DeepEqual(clue.Tags, edited.Tags) {
+		c.Errorf("clue after UpdateClue is %q %q", clue.Text, clue.Tags)
+	}
+
+	retired := clues[1]
+	retired.Retired = true
+	if err := c.store.UpdateClue(c.actor(), retired); err != nil {
+		c.Fatalf("UpdateClue retiring: %v", err)
+	}
+	if active, err := c.store.ListClues(dest.ID, false); err != nil || len(active) != len(clues)-1 {
+		c.Errorf("ListClues(false) returned %d clues after retiring one, want %d (%v)", len(active), len(clues)-1, err)
+	}
+	if all, err := c.store.ListClues(dest.ID, true); err != nil || len(all) != len(clues) {
+		c.Errorf("ListClues(true) returned %d clues, want %d (%v)", len(all), len(clues), err)
+	}
+	if got, err := c.store.GetDestinationByID(dest.ID); err != nil || len(got.Clues) != len(clues)-1 {
+		c.Errorf("destination still plays a retired clue (%v)", err)
+	}
+
+	c.expectErr("UpdateClue of a missing clue", c.store.UpdateClue(c.actor(), models.Clue{ID: -1, Text: "missing"}), sql.ErrNoRows)
+	_, err = c.store.GetClueByID(-1)
+	c.expectErr("GetClueByID of a missing clue", err, sql.ErrNoRows)
+}
+
+func checkDestinationsVersion(c *checker) {
+	// Every kind of change to a destination's content moves the version on
+	changed := func(what string, change func() error) {
+		before, err := c.store.DestinationsVersion()
+		if err != nil {
+			c.Errorf("DestinationsVersion: %v", err)
+			return
+		}
+		if err := change(); err != nil {
+			c.Errorf("%s: %v", what, err)
+			return
+		}
+		after, err := c.store.DestinationsVersion()
+		if err != nil {
+			c.Errorf("DestinationsVersion after %s: %v", what, err)
+		} else if after == before {
+			c.Errorf("DestinationsVersion is still %d after %s", after, what)
+		}
+	}
+
+	var dest *models.Destination
+	changed("CreateDestination", func() error {
+		dest = c.destination("versioned")
+		return nil
+	})
+	changed("UpdateDestination", func() error {
+		updated := *dest
+		latitude := *updated.Latitude + 1
+		updated.Latitude = &latitude
+		return c.store.UpdateDestination(c.actor(), updated)
+	})
+	changed("UpdateClue", func() error {
+		return c.store.UpdateClue(c.actor(), models.Clue{ID: dest.ClueIDs[0], Text: "versioned edited " + c.suffix})
+	})
+	changed("ImportDestinations", func() error {
+		_, err := c.store.ImportDestinations(c.actor(), []models.Destination{c.newDestination("versioned import")}, nil, nil, nil)
+		return err
+	})
+	changed("DeleteDestination", func() error {
+		_, err := c.store.DeleteDestination(c.actor(), dest.ID)
+		return err
+	})
+}
+
+func checkDelete(c *checker) {
+	unused := c.destination("unused")
+	retired, err := c.store.DeleteDestination(c.actor(), unused.ID)
+	if err != nil {
+		c.Fatalf("DeleteDestination: %v", err)
+	}
+	if retired {
+		c.Errorf("an unused destination was retired instead of deleted")
+	}
+	_, err = c.store.GetDestinationByID(unused.ID)
+	c.expectErr("GetDestinationByID after delete", err, sql.ErrNoRows)
+
+	_, err = c.store.DeleteDestination(c.actor(), -1)
+	c.expectErr("DeleteDestination of a missing destination", err, sql.ErrNoRows)
+
+	// Destinations used as an answer or only as an option are retired
+	answer := c.destination("answer")
+	option := c.destination("option")
+	user := c.user("delete")
+	gameID, err := c.store.CreateGame(user.ID, 1, models.ScoringStandard)
+	if err != nil {
+		c.Fatalf("CreateGame: %v", err)
+	}
+	if _, err := c.store.AddGameQuestion(gameID, answer.Clues[0], []int{answer.ID, option.ID}, answer.ID, answer.ClueIDs[0]); err != nil {
+		c.Fatalf("AddGameQuestion: %v", err)
+	}
+
+	for _, dest := range []*models.Destination{answer, option} {
+		retired, err := c.store.DeleteDestination(c.actor(), dest.ID)
+		if err != nil {
+			c.Fatalf("DeleteDestination of a referenced destination: %v", err)
+		}
+		got, err := c.store.GetDestinationByID(dest.ID)
+		if !retired || err != nil || !got.Retired {
+			c.Errorf("destination %s referenced by a game was not retired (%v)", dest.City, err)
+		}
+	}
+}
+
+func checkImport(c *checker) {
+	kept := c.destination("kept")
+	removed := c.destination("removed")
+
+	added := c.newDestination("added")
+	updated := *kept
+	updated.Trivia = []string{"kept new trivia " + c.suffix}
+
+	result, err := c.store.ImportDestinations(c.actor(), []models.Destination{added}, []models.Destination{updated}, []int{removed.ID}, nil)
+	if err != nil {
+		c.Fatalf("ImportDestinations: %v", err)
+	}
+	if result.Deleted != 1 || result.Retired != 0 {
+		c.Errorf("ImportDestinations returned %+v, want 1 deleted", result)
+	}
+
+	destinations, err := c.store.ListDestinations(true)
+	if err != nil {
+		c.Fatalf("ListDestinations: %v", err)
+	}
+	var found bool
+	for _, dest := range destinations {
+		switch {
+		case dest.City == added.City:
+			found = true
+			if !reflect.DeepEqual(dest.Clues, added.Clues) {
+				c.Errorf("imported destination has clues %q", dest.Clues)
+			}
+		case dest.ID == kept.ID:
+			if !reflect.DeepEqual(dest.Trivia, updated.Trivia) {
+				c.Errorf("updated destination has trivia %q, want %q", dest.Trivia, updated.Trivia)
+			}
+		case dest.ID == removed.ID:
+			c.Errorf("removed destination is still listed")
+		}
+	}
+	if !found {
+		c.Errorf("imported destination is not listed")
+	}
+
+	// A failing import changes nothing
+	failing := c.newDestination("failing")
+	_, err = c.store.ImportDestinations(c.actor(), []models.Destination{failing}, nil, []int{-1}, nil)
+	if err == nil {
+		c.Errorf("ImportDestinations removing a missing destination succeeded")
+	}
+	if destinations, err = c.store.ListDestinations(true); err == nil {
+		for _, dest := range destinations {
+			if dest.City == failing.City {
+				c.Errorf("a failed import was partly applied")
+			}
+		}
+	}
+}
+
+func checkImportTranslations(c *checker) {
+	added := c.newDestination("translated import")
+	var given []models.Destination
+	translate := func(destinations []models.Destination) ([]models.DestinationTranslation, []models.TextTranslation, []models.TextTranslation, error) {
+		given = destinations
+		for _, dest := range destinations {
+			if dest.City == added.City {
+				return []models.DestinationTranslation{{DestinationID: dest.ID, Locale: "fr", City: "Ville " + c.suffix}},
+					[]models.TextTranslation{{TextID: dest.ClueIDs[0], Locale: "fr", Text: "indice importé"}}, nil, nil
+			}
+		}
+		return nil, nil, nil, errors.New("the added destination was not given to translate")
+	}
+	if _, err := c.store.ImportDestinations(c.actor(), []models.Destination{added}, nil, nil, translate); err != nil {
+		c.Fatalf("ImportDestinations with translations: %v", err)
+	}
+	for _, dest := range given {
+		if dest.Retired || len(dest.ClueIDs) != len(dest.Clues) {
+			c.Errorf("translate was given destination %d retired or without its clue IDs", dest.ID)
+		}
+	}
+
+	destinations, err := c.store.ListDestinations(false)
+	if err != nil {
+		c.Fatalf("ListDestinations: %v", err)
+	}
+	var stored *models.Destination
+	for i := range destinations {
+		if destinations[i].City == added.City {
+			stored = &destinations[i]
+		}
+	}
+	if stored == nil {
+		c.Fatalf("imported destination is not listed")
+	}
+	if text, ok, err := c.store.TranslateClue(stored.ClueIDs[0], []string{"fr"}); err != nil || !ok || text != "indice importé" {
+		c.Errorf("TranslateClue of an imported clue returned %q, %v, %v", text, ok, err)
+	}
+
+	// Failing to translate rolls back the destinations too
+	failing := c.newDestination("untranslatable import")
+	_, err = c.store.ImportDestinations(c.actor(), []models.Destination{failing}, nil, nil,
+		func([]models.Destination) ([]models.DestinationTranslation, []models.TextTranslation, []models.TextTranslation, error) {
+			return nil, nil, nil, errors.New("translation failed")
+		})
+	if err == nil {
+		c.Errorf("ImportDestinations with failing translations succeeded")
+	}
+	if destinations, err = c.store.ListDestinations(true); err == nil {
+		for _, dest := range destinations {
+			if dest.City == failing.City {
+				c.Errorf("an import whose translations failed was applied")
+			}
+		}
+	}
+}
+
+func checkTranslations(c *checker) {
+	dest := c.destination("translated")
+
+	err := c.store.SaveTranslations(
+		[]models.DestinationTranslation{{DestinationID: dest.ID, Locale: "fr", City: "Ville " + c.suffix}},
+		[]models.TextTranslation{{TextID: dest.ClueIDs[0], Locale: "fr", Text: "indice"}},
+		[]models.TextTranslation{{TextID: dest.FunFactIDs[0], Locale: "es", Text: "dato curioso"}},
+	)
+	if err != nil {
+		c.Fatalf("SaveTranslations: %v", err)
+	}
+
+	// Empty name fields keep the stored translation
+	err = c.store.SaveTranslations([]models.DestinationTranslation{{DestinationID: dest.ID, Locale: "fr", Country: "Pays"}}, nil, nil)
+	if err != nil {
+		c.Fatalf("SaveTranslations of the country: %v", err)
+	}
+
+	localized := []models.Destination{*dest}
+	if err := c.store.LocalizeDestinations(localized, []string{"fr", "es", "en"}); err != nil {
+		c.Fatalf("LocalizeDestinations: %v", err)
+	}
+	got := localized[0]
+	if got.City != "Ville "+c.suffix || got.Country != "Pays" {
+		c.Errorf("localized name is %s, %s", got.City, got.Country)
+	}
+	if got.Clues[0] != "indice" || got.Clues[1] != dest.Clues[1] {
+		c.Errorf("localized clues are %q", got.Clues)
+	}
+	if got.FunFact[0] != "dato curioso" {
+		c.Errorf("localized fun fact is %q, want the fallback locale's", got.FunFact[0])
+	}
+
+	if text, ok, err := c.store.TranslateClue(dest.ClueIDs[0], []string{"de", "fr"}); err != nil || !ok || text != "indice" {
+		c.Errorf("TranslateClue returned %q, %v, %v", text, ok, err)
+	}
+	if _, ok, err := c.store.TranslateClue(dest.ClueIDs[1], []string{"fr"}); err != nil || ok {
+		c.Errorf("TranslateClue of an untranslated clue returned %v, %v", ok, err)
+	}
+
+	names, clues, _, err := c.store.ListTranslations("fr")
+	if err != nil {
+		c.Fatalf("ListTranslations: %v", err)
+	}
+	var listed bool
+	for _, name := range names {
+		if name.DestinationID == dest.ID && name.City == "Ville "+c.suffix && name.Country == "Pays" {
+			listed = true
+		}
+	}
+	if !listed || clues[dest.ClueIDs[0]] != "indice" {
+		c.Errorf("ListTranslations is missing the saved translations")
+	}
+
+	// Editing a clue's text drops its stale translations
+	clue, err := c.store.GetClueByID(dest.ClueIDs[0])
+	if err != nil {
+		c.Fatalf("GetClueByID: %v", err)
+	}
+	clue.Text = "translated edited " + c.suffix
+	if err := c.store.UpdateClue(c.actor(), *clue); err != nil {
+		c.Fatalf("UpdateClue: %v", err)
+	}
+	if _, ok, err := c.store.TranslateClue(clue.ID, []string{"fr"}); err != nil || ok {
+		c.Errorf("translation survived a change of the clue's text (%v)", err)
+	}
+}
+
+func checkImages(c *checker) {
+	dest := c.destination("pictured")
+
+	without, err := c.store.ListDestinationsWithoutImage()
+	if err != nil {
+		c.Fatalf("ListDestinationsWithoutImage: %v", err)
+	}
+	if !containsDestination(without, dest.ID) {
+		c.Errorf("ListDestinationsWithoutImage leaves out a destination without a photo")
+	}
+
+	_, err = c.store.GetDestinationImage(dest.ID)
+	c.expectErr("GetDestinationImage before saving", err, sql.ErrNoRows)
+
+	image := models.DestinationImage{DestinationID: dest.ID, File: "destinations/first.jpg", Provider: "storagetest", Alt: "first"}
+	if err := c.store.SaveDestinationImage(image); err != nil {
+		c.Fatalf("SaveDestinationImage: %v", err)
+	}
+	image.File, image.Alt = "destinations/second.jpg", "second"
+	if err := c.store.SaveDestinationImage(image); err != nil {
+		c.Fatalf("SaveDestinationImage replacing: %v", err)
+	}
+
+	got, err := c.store.GetDestinationImage(dest.ID)
+	if err != nil {
+		c.Fatalf("GetDestinationImage: %v", err)
+	}
+	if got.File != image.File || got.Alt != image.Alt || got.Provider != image.Provider || got.FetchedAt.IsZero() {
+		c.Errorf("GetDestinationImage returned %+v", got)
+	}
+
+	if without, err = c.store.ListDestinationsWithoutImage(); err != nil || containsDestination(without, dest.ID) {
+		c.Errorf("ListDestinationsWithoutImage includes a destination with a photo (%v)", err)
+	}
+}
+
+func checkGames(c *checker) {
+	user := c.user("player")
+	first := c.destination("first")
+	second := c.destination("second")
+
+	gameID, questionIDs := c.game(user.ID, models.ScoringProximity, first, second)
+
+	game, err := c.store.GetGame(gameID)
+	if err != nil {
+		c.Fatalf("GetGame: %v", err)
+	}
+	if game.UserID != user.ID || game.TotalQuestions != 2 || game.Scoring != models.ScoringProximity || game.TotalAnswered != 0 {
+		c.Errorf("GetGame returned %+v", game)
+	}
+
+	next, err := c.store.GetNextQuestion(gameID)
+	if err != nil {
+		c.Fatalf("GetNextQuestion: %v", err)
+	}
+	if next.ID != questionIDs[0] || next.ClueID != first.ClueIDs[0] || next.IsAnswered != 0 ||
+		!reflect.DeepEqual(next.OptionDestinationIDs, []int{first.ID, second.ID}) {
+		c.Errorf("GetNextQuestion returned %+v", next)
+	}
+	if hasNext, err := c.store.HasNextQuestion(gameID); err != nil || !hasNext {
+		c.Errorf("HasNextQuestion before the first answer returned %v, %v", hasNext, err)
+	}
+
+	err = c.store.SubmitAnswer(gameID, questionIDs[0], storage.Answer{
+		SelectedDestinationID: first.ID,
+		CorrectLatitude:       first.Latitude,
+		CorrectLongitude:      first.Longitude,
+		Correct:               true,
+		Points:                10,
+	})
+	if err != nil {
+		c.Fatalf("SubmitAnswer: %v", err)
+	}
+
+	// A second answer is refused and leaves the first one in place
+	err = c.store.SubmitAnswer(gameID, questionIDs[0], storage.Answer{SelectedDestinationID: second.ID, Points: 5})
+	c.expectErr("SubmitAnswer to an answered question", err, storage.ErrAlreadyAnswered)
+	err = c.store.SubmitAnswer(gameID, -1, storage.Answer{SelectedDestinationID: first.ID})
+	c.expectErr("SubmitAnswer to a missing question", err, sql.ErrNoRows)
+
+	question, err := c.store.GetQuestionByID(gameID, questionIDs[0])
+	if err != nil {
+		c.Fatalf("GetQuestionByID: %v", err)
+	}
+	if question.IsAnswered != 1 || question.SelectedDestinationID != first.ID || question.Points != 10 || question.CorrectLatitude == nil {
+		c.Errorf("answered question is %+v", question)
+	}
+
+	if next, err = c.store.GetNextQuestion(gameID); err != nil || next.ID != questionIDs[1] {
+		c.Errorf("GetNextQuestion after an answer returned %v, %v", next, err)
+	}
+	if hasNext, err := c.store.HasNextQuestion(gameID); err != nil || hasNext {
+		c.Errorf("HasNextQuestion on the last question returned %v, %v", hasNext, err)
+	}
+
+	distance := 123.5
+	err = c.store.SubmitAnswer(gameID, questionIDs[1], storage.Answer{
+		SelectedDestinationID: first.ID,
+		Correct:               false,
+		Points:                3,
+		DistanceKm:            &distance,
+	})
+	if err != nil {
+		c.Fatalf("SubmitAnswer: %v", err)
+	}
+
+	result, err := c.store.GetGameResult(gameID)
+	if err != nil {
+		c.Fatalf("GetGameResult: %v", err)
+	}
+	if result.TotalCorrect != 1 || result.TotalIncorrect != 1 || result.Score != 13 || len(result.Questions) != 2 {
+		c.Errorf("GetGameResult returned %+v", result)
+	} else if d := result.Questions[1].DistanceKm; d == nil || math.Abs(*d-distance) > 1e-9 {
+		c.Errorf("answer distance was not stored")
+	}
+
+	_, err = c.store.GetNextQuestion(gameID)
+	c.expectErr("GetNextQuestion of a finished game", err, sql.ErrNoRows)
+	_, err = c.store.GetQuestionByID(gameID, -1)
+	c.expectErr("GetQuestionByID of a missing question", err, sql.ErrNoRows)
+	_, err = c.store.GetGame(-1)
+	c.expectErr("GetGame of a missing game", err, sql.ErrNoRows)
+
+	// The game's photo is the first question's destination that has one
+	_, err = c.store.GetGameImage(gameID)
+	c.expectErr("GetGameImage without photos", err, sql.ErrNoRows)
+	if err := c.store.SaveDestinationImage(models.DestinationImage{DestinationID: second.ID, File: "destinations/second.jpg", Provider: "storagetest"}); err != nil {
+		c.Fatalf("SaveDestinationImage: %v", err)
+	}
+	if image, err := c.store.GetGameImage(gameID); err != nil || image.DestinationID != second.ID {
+		c.Errorf("GetGameImage returned %v, %v", image, err)
+	}
+}
+
+func checkConcurrentAnswers(c *checker) {
+	user := c.user("racer")
+	dest := c.destination("raced")
+	gameID, questionIDs := c.game(user.ID, models.ScoringStandard, dest)
+
+	// Exactly one of several simultaneous answers to a question is recorded
+	const racers = 8
+	errs := make(chan error, racers)
+	var wg sync.WaitGroup
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.store.SubmitAnswer(gameID, questionIDs[0], storage.Answer{SelectedDestinationID: dest.ID, Correct: true, Points: 10})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var recorded int
+	for err := range errs {
+		switch {
+		case err == nil:
+			recorded++
+		case !errors.Is(err, storage.ErrAlreadyAnswered):
+			c.Errorf("concurrent SubmitAnswer failed: %v", err)
+		}
+	}
+	if recorded != 1 {
+		c.Errorf("%d of %d concurrent answers were recorded, want 1", recorded, racers)
+	}
+
+	game, err := c.store.GetGame(gameID)
+	if err != nil {
+		c.Fatalf("GetGame: %v", err)
+	}
+	if game.TotalAnswered != 1 || game.TotalCorrect != 1 || game.Score != 10 {
+		c.Errorf("game after concurrent answers is %+v, want one answer counted", game)
+	}
+}
+
+func checkSubmissions(c *checker) {
+	user := c.user("submitter")
+
+	proposal := models.Submission{
+		Kind:    models.SubmissionDestination,
+		UserID:  user.ID,
+		City:    "Submitted " + c.suffix,
+		Country: "Storagetest",
+		Clues:   []string{"submitted clue one", "submitted clue two"},
+		FunFact: []string{"submitted fun fact"},
+	}
+	id, err := c.store.CreateSubmission(proposal)
+	if err != nil {
+		c.Fatalf("CreateSubmission: %v", err)
+	}
+
+	sub, err := c.store.GetSubmission(id)
+	if err != nil {
+		c.Fatalf("GetSubmission: %v", err)
+	}
+	if sub.Status != models.SubmissionPending || sub.Username != user.Username || sub.ReviewedAt != nil ||
+		!reflect.DeepEqual(sub.Clues, proposal.Clues) || !reflect.DeepEqual(sub.FunFact, proposal.FunFact) || sub.Trivia == nil || len(sub.Trivia) != 0 {
+		c.Errorf("GetSubmission returned %+v", sub)
+	}
+
+	if pending, err := c.store.ListSubmissions(models.SubmissionPending, user.ID); err != nil || len(pending) != 1 || pending[0].ID != id {
+		c.Errorf("ListSubmissions of pending submissions returned %d, %v", len(pending), err)
+	}
+	if approved, err := c.store.ListSubmissions(models.SubmissionApproved, user.ID); err != nil || len(approved) != 0 {
+		c.Errorf("ListSubmissions of approved submissions returned %d, %v", len(approved), err)
+	}
+
+	// Approve with a reviewer's edit
+	sub.Trivia = []string{"submitted trivia"}
+	sub.ReviewNote = "thanks"
+	destinationID, err := c.store.ApproveSubmission(c.actor(), *sub, models.Destination{
+		City:    sub.City,
+		Country: sub.Country,
+		Clues:   sub.Clues,
+		FunFact: sub.FunFact,
+		Trivia:  sub.Trivia,
+	})
+	if err != nil {
+		c.Fatalf("ApproveSubmission: %v", err)
+	}
+
+	dest, err := c.store.GetDestinationByID(destinationID)
+	if err != nil {
+		c.Fatalf("GetDestinationByID of the approved destination: %v", err)
+	}
+	if dest.SubmittedBy != user.Username || !reflect.DeepEqual(dest.Trivia, sub.Trivia) {
+		c.Errorf("approved destination is %+v", dest)
+	}
+	if clues, err := c.store.ListClues(destinationID, false); err != nil || len(clues) != 2 || clues[0].SubmittedBy != user.Username {
+		c.Errorf("approved clues are not credited to the submitter (%v)", err)
+	}
+
+	approved, err := c.store.GetSubmission(id)
+	if err != nil {
+		c.Fatalf("GetSubmission after approval: %v", err)
+	}
+	if approved.Status != models.SubmissionApproved || approved.ReviewedAt == nil || approved.ReviewNote != "thanks" ||
+		approved.DestinationID == nil || *approved.DestinationID != destinationID || !reflect.DeepEqual(approved.Trivia, sub.Trivia) {
+		c.Errorf("approved submission is %+v", approved)
+	}
+
+	_, err = c.store.ApproveSubmission(c.actor(), *sub, *dest)
+	c.expectErr("ApproveSubmission of a reviewed submission", err, storage.ErrSubmissionReviewed)
+	c.expectErr("RejectSubmission of a reviewed submission", c.store.RejectSubmission(c.actor(), id, ""), storage.ErrSubmissionReviewed)
+	c.expectErr("RejectSubmission of a missing submission", c.store.RejectSubmission(c.actor(), -1, ""), sql.ErrNoRows)
+
+	// A second proposal of the same destination can't be approved as a new one
+	duplicateID, err := c.store.CreateSubmission(proposal)
+	if err != nil {
+		c.Fatalf("CreateSubmission of a duplicate: %v", err)
+	}
+	duplicate := proposal
+	duplicate.ID = duplicateID
+	_, err = c.store.ApproveSubmission(c.actor(), duplicate, models.Destination{City: proposal.City, Country: proposal.Country, Clues: proposal.Clues})
+	c.expectErr("ApproveSubmission of a duplicate destination", err, storage.ErrDuplicateDestination)
+
+	if err := c.store.RejectSubmission(c.actor(), duplicateID, "already exists"); err != nil {
+		c.Fatalf("RejectSubmission: %v", err)
+	}
+	rejected, err := c.store.GetSubmission(duplicateID)
+	if err != nil {
+		c.Fatalf("GetSubmission after rejection: %v", err)
+	}
+	if rejected.Status != models.SubmissionRejected || rejected.ReviewNote != "already exists" || rejected.ReviewedAt == nil {
+		c.Errorf("rejected submission is %+v", rejected)
+	}
+
+	_, err = c.store.GetSubmission(-1)
+	c.expectErr("GetSubmission of a missing submission", err, sql.ErrNoRows)
+}
+
+func checkStats(c *checker) {
+	user := c.user("stats")
+	hard := c.destination("hard")
+	decoy := c.destination("decoy")
+
+	// Three answers to the hard destination: two pick the decoy, one is right.
+	// A pin answer picks no option and is left out of the statistics.
+	answers := []storage.Answer{
+		{SelectedDestinationID: decoy.ID},
+		{SelectedDestinationID: decoy.ID},
+		{SelectedDestinationID: hard.ID, Correct: true, Points: 10},
+		{},
+	}
+	gameID, err := c.store.CreateGame(user.ID, len(answers), models.ScoringStandard)
+	if err != nil {
+		c.Fatalf("CreateGame: %v", err)
+	}
+	for _, answer := range answers {
+		questionID, err := c.store.AddGameQuestion(gameID, hard.Clues[0], []int{hard.ID, decoy.ID}, hard.ID, hard.ClueIDs[0])
+		if err != nil {
+			c.Fatalf("AddGameQuestion: %v", err)
+		}
+		if err := c.store.SubmitAnswer(gameID, questionID, answer); err != nil {
+			c.Fatalf("SubmitAnswer: %v", err)
+		}
+	}
+
+	if err := c.store.RefreshDifficultyStats(); err != nil {
+		c.Fatalf("RefreshDifficultyStats: %v", err)
+	}
+
+	destinations, err := c.store.ListDestinationStats()
+	if err != nil {
+		c.Fatalf("ListDestinationStats: %v", err)
+	}
+	if stat := findStats(destinations, hard.ID, 0); stat == nil {
+		c.Errorf("ListDestinationStats leaves out an answered destination")
+	} else {
+		checkHardStats(c, "destination", stat, hard, decoy)
+	}
+
+	clues, err := c.store.ListClueStats()
+	if err != nil {
+		c.Fatalf("ListClueStats: %v", err)
+	}
+	if stat := findStats(clues, hard.ID, hard.ClueIDs[0]); stat == nil {
+		c.Errorf("ListClueStats leaves out an answered clue")
+	} else {
+		checkHardStats(c, "clue", stat, hard, decoy)
+		if stat.Clue != hard.Clues[0] {
+			c.Errorf("clue stats name the clue %q, want %q", stat.Clue, hard.Clues[0])
+		}
+	}
+
+	if updatedAt, err := c.store.DifficultyStatsUpdatedAt(); err != nil || updatedAt == nil || updatedAt.IsZero() {
+		c.Errorf("DifficultyStatsUpdatedAt returned %v, %v", updatedAt, err)
+	}
+}
+
+// checkRetention runs last: its cutoff is in the future, so it also deletes
+// and compacts the games of the earlier checks
+func checkRetention(c *checker) {
+	user := c.user("retention")
+	hard := c.destination("hard")
+	decoy := c.destination("decoy")
+
+	abandonedID, _ := c.game(user.ID, models.ScoringStandard, hard, decoy)
+
+	// Answered like the stats check: the decoy twice, then the right answer
+	playedID, err := c.store.CreateGame(user.ID, 3, models.ScoringStandard)
+	if err != nil {
+		c.Fatalf("CreateGame: %v", err)
+	}
+	for _, answer := range []storage.Answer{
+		{SelectedDestinationID: decoy.ID},
+		{SelectedDestinationID: decoy.ID},
+		{SelectedDestinationID: hard.ID, Correct: true, Points: 10},
+	} {
+		questionID, err := c.store.AddGameQuestion(playedID, hard.Clues[0], []int{hard.ID, decoy.ID}, hard.ID, hard.ClueIDs[0])
+		if err != nil {
+			c.Fatalf("AddGameQuestion: %v", err)
+		}
+		if err := c.store.SubmitAnswer(playedID, questionID, answer); err != nil {
+			c.Fatalf("SubmitAnswer: %v", err)
+		}
+	}
+
+	// Answered, but with a question left to play; its destination keeps it
+	// out of the hard destination's stats
+	unfinished := c.destination("unfinished")
+	partialID, err := c.store.CreateGame(user.ID, 2, models.ScoringStandard)
+	if err != nil {
+		c.Fatalf("CreateGame: %v", err)
+	}
+	var pendingID int
+	for i := 0; i < 2; i++ {
+		questionID, err := c.store.AddGameQuestion(partialID, unfinished.Clues[0], []int{unfinished.ID}, unfinished.ID, unfinished.ClueIDs[0])
+		if err != nil {
+			c.Fatalf("AddGameQuestion: %v", err)
+		}
+		pendingID = questionID
+		if i == 0 {
+			if err := c.store.SubmitAnswer(partialID, questionID, storage.Answer{SelectedDestinationID: unfinished.ID, Correct: true, Points: 10}); err != nil {
+				c.Fatalf("SubmitAnswer: %v", err)
+			}
+		}
+	}
+
+	cutoff := time.Now().Add(time.Minute)
+	if deleted, err := c.store.DeleteAbandonedGames(cutoff); err != nil || deleted < 1 {
+		c.Errorf("DeleteAbandonedGames deleted %d games, %v", deleted, err)
+	}
+	_, err = c.store.GetGame(abandonedID)
+	c.expectErr("GetGame of a deleted abandoned game", err, sql.ErrNoRows)
+
+	compacted := 0
+	for {
+		n, err := c.store.CompactGames(cutoff, 2)
+		if err != nil {
+			c.Fatalf("CompactGames: %v", err)
+		}
+		compacted += n
+		if n < 2 {
+			break
+		}
+	}
+	if compacted < 1 {
+		c.Errorf("CompactGames compacted no games")
+	}
+	if n, err := c.store.CompactGames(cutoff, 2); err != nil || n != 0 {
+		c.Errorf("CompactGames compacted %d games again, %v", n, err)
+	}
+
+	partial, err := c.store.GetGame(partialID)
+	if err != nil {
+		c.Fatalf("GetGame of an unfinished game: %v", err)
+	}
+	if partial.CompactedAt != nil {
+		c.Errorf("CompactGames compacted a game with questions left")
+	}
+	if _, err := c.store.GetQuestionByID(partialID, pendingID); err != nil {
+		c.Errorf("GetQuestionByID of an unfinished game's question: %v", err)
+	}
+
+	game, err := c.store.GetGame(playedID)
+	if err != nil {
+		c.Fatalf("GetGame of a compacted game: %v", err)
+	}
+	if game.CompactedAt == nil || game.TotalAnswered != 3 || game.TotalCorrect != 1 || game.Score != 10 {
+		c.Errorf("compacted game is %+v, want its totals and compacted_at", game)
+	}
+	result, err := c.store.GetGameResult(playedID)
+	if err != nil {
+		c.Fatalf("GetGameResult of a compacted game: %v", err)
+	}
+	if !result.Compacted || len(result.Questions) != 0 || result.Score != 10 {
+		c.Errorf("compacted game result is %+v, want no questions and the score", result)
+	}
+
+	// The archived answers count exactly like the questions they replace
+	if err := c.store.RefreshDifficultyStats(); err != nil {
+		c.Fatalf("RefreshDifficultyStats: %v", err)
+	}
+	destinations, err := c.store.ListDestinationStats()
+	if err != nil {
+		c.Fatalf("ListDestinationStats: %v", err)
+	}
+	if stat := findStats(destinations, hard.ID, 0); stat == nil {
+		c.Errorf("ListDestinationStats leaves out a destination answered in a compacted game")
+	} else {
+		checkHardStats(c, "compacted destination", stat, hard, decoy)
+	}
+	clues, err := c.store.ListClueStats()
+	if err != nil {
+		c.Fatalf("ListClueStats: %v", err)
+	}
+	if stat := findStats(clues, hard.ID, hard.ClueIDs[0]); stat == nil {
+		c.Errorf("ListClueStats leaves out a clue answered in a compacted game")
+	} else {
+		checkHardStats(c, "compacted clue", stat, hard, decoy)
+	}
+
+	// Destinations counted in archived answers are retired, not deleted
+	if retired, err := c.store.DeleteDestination(c.actor(), decoy.ID); err != nil || !retired {
+		c.Errorf("DeleteDestination of a destination in archived answers: retired %v, %v", retired, err)
+	}
+}
+
+func checkAudit(c *checker) {
+	user := c.user("audit")
+	dest := c.destination("audit")
+
+	updated := *dest
+	updated.Clues = append([]string{"audit clue four"}, dest.Clues...)
+	if err := c.store.UpdateDestination(c.actor(), updated); err != nil {
+		c.Fatalf("UpdateDestination: %v", err)
+	}
+	clue, err := c.store.GetClueByID(dest.ClueIDs[0])
+	if err != nil {
+		c.Fatalf("GetClueByID: %v", err)
+	}
+	clue.Tags = []string{"audited"}
+	if err := c.store.UpdateClue(c.actor(), *clue); err != nil {
+		c.Fatalf("UpdateClue: %v", err)
+	}
+	if _, err := c.store.DeleteDestination(c.actor(), dest.ID); err != nil {
+		c.Fatalf("DeleteDestination: %v", err)
+	}
+
+	// Failed changes leave nothing behind
+	_, err = c.store.CreateDestination(c.actor(), c.newDestination("audit-duplicate"))
+	if err != nil {
+		c.Fatalf("CreateDestination: %v", err)
+	}
+	_, err = c.store.CreateDestination(c.actor(), c.newDestination("audit-duplicate"))
+	c.expectErr("CreateDestination of a duplicate", err, storage.ErrDuplicateDestination)
+	err = c.store.UpdateDestination(c.actor(), models.Destination{ID: -1, City: "Nowhere", Country: "Storagetest"})
+	c.expectErr("UpdateDestination of a missing destination", err, sql.ErrNoRows)
+
+	entries, err := c.store.ListAuditEntries(storage.AuditFilter{Actor: c.actor()})
+	if err != nil {
+		c.Fatalf("ListAuditEntries: %v", err)
+	}
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	want := []string{
+		models.AuditDestinationCreate,
+		models.AuditDestinationDelete,
+		models.AuditClueUpdate,
+		models.AuditDestinationUpdate,
+		models.AuditDestinationCreate,
+		models.AuditUserCreate,
+	}
+	if !reflect.DeepEqual(actions, want) {
+		c.Fatalf("audit log has %v, want %v newest first", actions, want)
+	}
+
+	created, deleted, clueEntry, userEntry := entries[4], entries[1], entries[2], entries[5]
+	if created.TargetType != models.AuditTargetDestination || created.TargetID != dest.ID || created.Before != nil {
+		c.Errorf("destination creation recorded as %+v", created)
+	}
+	var after models.Destination
+	if err := json.Unmarshal(created.After, &after); err != nil || after.City != dest.City || len(after.Clues) != len(dest.Clues) {
+		c.Errorf("destination creation recorded %s, %v", created.After, err)
+	}
+	if deleted.After != nil || !strings.Contains(string(deleted.Before), "audit clue four") {
+		c.Errorf("destination deletion recorded %s before and %s after", deleted.Before, deleted.After)
+	}
+	if clueEntry.TargetType != models.AuditTargetClue || clueEntry.TargetID != clue.ID ||
+		strings.Contains(string(clueEntry.Before), "audited") || !strings.Contains(string(clueEntry.After), "audited") {
+		c.Errorf("clue update recorded as %+v", clueEntry)
+	}
+	if userEntry.TargetType != models.AuditTargetUser || userEntry.TargetID != user.ID || userEntry.Before != nil {
+		c.Errorf("user creation recorded as %+v", userEntry)
+	}
+	if entries[0].CreatedAt.IsZero() || entries[0].ID <= entries[1].ID {
+		c.Errorf("audit entries are not stamped and newest first: %+v", entries[:2])
+	}
+
+	// Filters
+	history, err := c.store.ListAuditEntries(storage.AuditFilter{TargetType: models.AuditTargetDestination, TargetID: dest.ID})
+	if err != nil {
+		c.Fatalf("ListAuditEntries by target: %v", err)
+	}
+	if len(history) != 3 {
+		c.Errorf("destination %d has %d audit entries, want 3", dest.ID, len(history))
+	}
+	page, err := c.store.ListAuditEntries(storage.AuditFilter{Actor: c.actor(), BeforeID: entries[1].ID, Limit: 2})
+	if err != nil {
+		c.Fatalf("ListAuditEntries page: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != entries[2].ID || page[1].ID != entries[3].ID {
+		c.Errorf("page before entry %d has %d entries", entries[1].ID, len(page))
+	}
+	creations, err := c.store.ListAuditEntries(storage.AuditFilter{Actor: c.actor(), Action: models.AuditDestinationCreate})
+	if err != nil {
+		c.Fatalf("ListAuditEntries by action: %v", err)
+	}
+	if len(creations) != 2 {
+		c.Errorf("found %d destination creations, want 2", len(creations))
+	}
+	future, err := c.store.ListAuditEntries(storage.AuditFilter{Actor: c.actor(), Since: time.Now().Add(time.Hour)})
+	if err != nil || len(future) != 0 {
+		c.Errorf("found %d entries from the future, %v", len(future), err)
+	}
+	past, err := c.store.ListAuditEntries(storage.AuditFilter{Actor: c.actor(), Until: time.Now().Add(-time.Hour)})
+	if err != nil || len(past) != 0 {
+		c.Errorf("found %d entries from an hour ago, %v", len(past), err)
+	}
+}
+
+// checkHardStats checks the statistics of the hard destination or its clue
+func checkHardStats(c *checker, kind string, stat *models.DifficultyStats, hard, decoy *models.Destination) {
+	if stat.Answered != 3 || stat.Correct != 1 || stat.TopWrongCount != 2 ||
+		stat.TopWrongDestinationID == nil || *stat.TopWrongDestinationID != decoy.ID {
+		c.Errorf("%s stats are %+v, want 3 answered, 1 correct and the decoy picked twice", kind, stat)
+	}
+	if stat.City != hard.City || stat.TopWrongOption != decoy.City+", "+decoy.Country {
+		c.Errorf("%s stats name %q and %q", kind, stat.City, stat.TopWrongOption)
+	}
+}
+
+// findStats returns the statistics of a destination, or of a clue if clueID is set
+func findStats(stats []models.DifficultyStats, destinationID, clueID int) *models.DifficultyStats {
+	for i := range stats {
+		if stats[i].DestinationID == destinationID && stats[i].ClueID == clueID {
+			return &stats[i]
+		}
+	}
+	return nil
+}
+
+// containsDestination reports whether destinations has one with the given ID
+func containsDestination(destinations []models.Destination, id int) bool {
+	for _, dest := range destinations {
+		if dest.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -21,11 +21,6 @@ export const getUser = async (username) => {
   return response.data;
 };
 
-export const updateUserScore = async (username, correct) => {
-  const response = await axios.post(`${API_URL}/users/${username}/score`, { correct });
-  return response.data;
-};
-
 // Modified to only handle local reset without API call
 export const resetUserScore = async (username) => {
   console.log(`Resetting score locally for user: ${username}`);
@@ -136,6 +131,11 @@ export const submitAnswer = async (gameId, questionId, answerId) => {
     console.log('Submit answer response:', response.data);
     return response.data;
   } catch (error) {
+    // A repeated submission (double click, retry) gets the answer that was
+    // recorded first, so show that instead of failing
+    if (error.response?.status === 409 && error.response.data?.answer) {
+      return error.response.data.answer;
+    }
     console.error('Error submitting answer:', error);
     throw error;
   }